@@ -0,0 +1,731 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ProgramName is the name of the executable shown in usage information
+const ProgramName = "evernote-note-graph"
+
+// DefaultSnapshotFilename is the NoteGraph snapshot written by fetch and read by the other commands
+const DefaultSnapshotFilename = "notegraph.json"
+
+// DefaultAddress is the network address the NoteGraph is served on
+const DefaultAddress = "localhost:8080"
+
+// Exit codes of the command line interface
+const (
+	ExitOK             = 0 // command completed successfully
+	ExitError          = 1 // command failed with an unexpected error
+	ExitUsage          = 2 // unknown command, invalid flags, or invalid flag values
+	ExitAuthentication = 3 // Evernote API auth token is invalid, expired, or lacks permissions
+	ExitNetwork        = 4 // Evernote API is unreachable or fails temporarily, the command can be retried
+	ExitRateLimit      = 5 // Evernote API rate limit is reached, the command can be retried after the rate limit duration
+	ExitParse          = 6 // input file or note content cannot be read or parsed
+	ExitOutput         = 7 // output file cannot be written or the server cannot listen
+)
+
+// Command is a subcommand of the command line interface, Parse parses the flags of the command and returns the function running it
+type Command struct {
+	Name        string
+	Description string
+	Parse       func(cli *CLI, arguments []string) (func() error, error)
+}
+
+// Commands contains all subcommands of the command line interface
+var Commands []Command
+
+// init defines the Commands, which cannot be initialized in their declaration as their usage information refers to Commands
+func init() {
+	Commands = []Command{
+		{Name: "fetch", Description: "Fetch Notes and NoteLinks from the Evernote API and save them as NoteGraph snapshot", Parse: func(cli *CLI, arguments []string) (func() error, error) {
+			fetchArgs, err := cli.ParseFetchArgs(arguments)
+			return func() error { return Fetch(fetchArgs) }, err
+		}},
+		{Name: "export", Description: "Export the NoteGraph snapshot to GraphML or any other output format", Parse: func(cli *CLI, arguments []string) (func() error, error) {
+			exportArgs, err := cli.ParseExportArgs(arguments)
+			return func() error { return Export(exportArgs) }, err
+		}},
+		{Name: "stats", Description: "Print stats, connected components, communities, broken NoteLinks, and the hygiene report of the NoteGraph snapshot", Parse: func(cli *CLI, arguments []string) (func() error, error) {
+			statsArgs, err := cli.ParseStatsArgs(arguments)
+			return func() error { return Stats(statsArgs) }, err
+		}},
+		{Name: "query", Description: "Query backlinks, paths, and ego networks of Notes in the NoteGraph snapshot", Parse: func(cli *CLI, arguments []string) (func() error, error) {
+			queryArgs, err := cli.ParseQueryArgs(arguments)
+			return func() error { return Query(queryArgs) }, err
+		}},
+		{Name: "serve", Description: "Serve the NoteGraph snapshot as interactive HTML graph viewer and in all single file output formats", Parse: func(cli *CLI, arguments []string) (func() error, error) {
+			serveArgs, err := cli.ParseServeArgs(arguments)
+			return func() error { return Serve(serveArgs) }, err
+		}},
+		{Name: "diff", Description: "Compare two NoteGraph snapshots or GraphML files", Parse: func(cli *CLI, arguments []string) (func() error, error) {
+			diffArgs, err := cli.ParseDiffArgs(arguments)
+			return func() error { return Diff(diffArgs) }, err
+		}},
+		{Name: "config", Description: "Print the effective configuration of a command from flags, environment variables, config file, and defaults", Parse: func(cli *CLI, arguments []string) (func() error, error) {
+			configArgs, err := cli.ParseConfigArgs(arguments)
+			return func() error {
+				cli.ConfigUtil.PrintConfig(cli.Stdout, configArgs.FlagSet, configArgs.Sources)
+				return nil
+			}, err
+		}},
+	}
+}
+
+// InputArgs contains the parsed arguments of the commands reading a NoteGraph snapshot
+type InputArgs struct {
+	SnapshotFilename string
+	NoteGraphFilter  *NoteGraphFilter
+}
+
+// OutputArgs contains the parsed arguments of the commands exporting a NoteGraph
+type OutputArgs struct {
+	LinkedNotes    bool
+	OutputFormat   OutputFormat
+	OutputFilename string
+	ExportOptions  ExportOptions
+	Layout         bool
+}
+
+// LogArgs contains the parsed arguments of all commands controlling the log output
+type LogArgs struct {
+	Verbose     bool
+	Quiet       bool
+	LogFormat   LogFormat
+	LogFilename string
+	Warnings    []string // warnings about deprecated flags logged once the logger is initialized
+}
+
+// FetchArgs contains the parsed arguments of the fetch command
+type FetchArgs struct {
+	EdamAuthToken    string
+	Sandbox          bool
+	NoteURLType      URLType
+	NoteContent      bool
+	SnapshotFilename string
+	ProgressMode     ProgressMode
+	LogArgs
+}
+
+// ExportArgs contains the parsed arguments of the export command
+type ExportArgs struct {
+	InputArgs
+	OutputArgs
+	Analyze bool
+	LogArgs
+}
+
+// StatsArgs contains the parsed arguments of the stats command
+type StatsArgs struct {
+	InputArgs
+	Analyze        bool
+	HygieneReport  bool
+	ReportFilename string
+	Thresholds     HygieneThresholds
+	LogArgs
+}
+
+// QueryArgs contains the parsed arguments of the query command
+type QueryArgs struct {
+	InputArgs
+	OutputArgs
+	Backlinks     string
+	EgoNote       string
+	EgoDepth      int
+	EgoDirection  LinkDirection
+	PathFrom      string
+	PathTo        string
+	PathMaxLength int
+	PathFilename  string
+	LogArgs
+}
+
+// ServeArgs contains the parsed arguments of the serve command
+type ServeArgs struct {
+	InputArgs
+	OutputArgs
+	Address string
+	Analyze bool
+	LogArgs
+}
+
+// DiffArgs contains the parsed arguments of the diff command
+type DiffArgs struct {
+	DiffFrom     string
+	DiffTo       string
+	DiffFilename string
+	LogArgs
+}
+
+// ConfigArgs contains the parsed arguments of the config command, the FlagSet and ConfigSources of the command whose effective
+// configuration is printed
+type ConfigArgs struct {
+	FlagSet *flag.FlagSet
+	Sources map[string]ConfigSource
+}
+
+// inputFlags are the flags shared by the commands reading a NoteGraph snapshot
+type inputFlags struct {
+	snapshotFilename *string
+	noteFilter       *string
+	linkFilter       *string
+}
+
+// outputFlags are the flags shared by the commands exporting a NoteGraph, outputFormat and outputFilename are nil for commands
+// that do not write an output file
+type outputFlags struct {
+	linkedNotes      *bool
+	outputFormat     *string
+	outputFilename   *string
+	graphMLFilename  *string
+	clusterNotebooks *bool
+	brokenLinks      *bool
+	yEdGraphics      *bool
+	yEdColorBy       *string
+	aggregateLinks   *bool
+	dropSelfLoops    *bool
+	undirected       *bool
+	reciprocal       *bool
+	layout           *bool
+	layoutIterations *int
+	labels           *bool
+}
+
+// logFlags are the flags shared by all commands controlling the log output
+type logFlags struct {
+	flagSet     *flag.FlagSet
+	verbose     *bool
+	quiet       *bool
+	logFormat   *string
+	logFilename *string
+}
+
+// errConfigOnly stops parsing the flags of a command after the configuration has been applied for the config command
+var errConfigOnly = errors.New("configuration applied")
+
+// CLI is the subcommand-based command line interface, usage information and flag errors are written to Output and the effective
+// configuration to Stdout, flags not set on the command line are read from environment variables or the config file by ConfigUtil
+type CLI struct {
+	Output                io.Writer
+	Stdout                io.Writer
+	ConfigUtil            *ConfigUtil
+	DefaultConfigFilename string
+	configOnly            bool
+	flagSet               *flag.FlagSet
+	flagSources           map[string]ConfigSource
+}
+
+// NewCLI creates a new instance of CLI reading the environment variables of the process and the default config file
+func NewCLI(output io.Writer) *CLI {
+	return &CLI{Output: output, Stdout: os.Stdout, ConfigUtil: NewConfigUtil(os.LookupEnv), DefaultConfigFilename: DefaultConfigFilename()}
+}
+
+// Run runs the command named by the first argument with the remaining arguments and returns the exit code
+func (c *CLI) Run(arguments []string) int {
+	if len(arguments) == 0 {
+		c.Usage()
+		return ExitUsage
+	}
+
+	if arguments[0] == "-h" || arguments[0] == "-help" || arguments[0] == "--help" || arguments[0] == "help" {
+		c.Usage()
+		return ExitOK
+	}
+
+	command := c.FindCommand(arguments[0])
+	if command == nil {
+		fmt.Fprintf(c.Output, "Unknown command [%s]\n", arguments[0])
+		c.Usage()
+		return ExitUsage
+	}
+
+	run, err := command.Parse(c, arguments[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		return ExitOK
+	} else if err != nil {
+		return ExitUsage
+	}
+
+	if runErr := run(); runErr != nil {
+		return c.RunError(runErr)
+	}
+
+	return ExitOK
+}
+
+// RunError prints the error of a failed command and the hint of its ErrorKind, logs the error if the log is written to a log file,
+// and returns the exit code of the ErrorKind
+func (c *CLI) RunError(err error) int {
+	errorKind := ErrorKindOf(err)
+	if logFile != nil {
+		logrus.WithFields(logrus.Fields{"kind": errorKind.String(), "exit_code": errorKind.ExitCode()}).WithError(err).Error("Command failed")
+	}
+
+	fmt.Fprintf(c.Output, "Error (%s): %v\n", errorKind, err)
+	if hint := errorKind.Hint(); hint != "" {
+		fmt.Fprintf(c.Output, "Hint: %s\n", hint)
+	}
+
+	return errorKind.ExitCode()
+}
+
+// FindCommand returns the Command with the name, returns nil if the Command does not exist
+func (c *CLI) FindCommand(name string) *Command {
+	for index := range Commands {
+		if Commands[index].Name == name {
+			return &Commands[index]
+		}
+	}
+
+	return nil
+}
+
+// Usage prints the usage information listing all commands
+func (c *CLI) Usage() {
+	fmt.Fprintf(c.Output, "Usage: %s <command> [flags]\n\nCommands:\n", ProgramName)
+	tabWriter := tabwriter.NewWriter(c.Output, 0, 0, 3, ' ', 0)
+	for _, command := range Commands {
+		fmt.Fprintf(tabWriter, "  %s\t%s\n", command.Name, command.Description)
+	}
+	tabWriter.Flush()
+	fmt.Fprintf(c.Output, "\nRun '%s <command> -h' for the flags of a command\n", ProgramName)
+}
+
+// NewFlagSet creates the FlagSet of the command with the config flag and usage information listing the flags of the command
+func (c *CLI) NewFlagSet(name string) *flag.FlagSet {
+	flagSet := flag.NewFlagSet(name, flag.ContinueOnError)
+	flagSet.SetOutput(c.Output)
+	flagSet.String(ConfigFlagName, c.DefaultConfigFilename, "YAML config file with flag values, flags not set on the command line are read from environment variables, then from the config file")
+	flagSet.Usage = func() {
+		fmt.Fprintf(c.Output, "Usage: %s %s [flags]\n\n%s\n\nFlags:\n", ProgramName, name, c.FindCommand(name).Description)
+		c.PrintFlags(flagSet)
+	}
+
+	return flagSet
+}
+
+// PrintFlags prints the usage information of all flags of the FlagSet except the DeprecatedFlags
+func (c *CLI) PrintFlags(flagSet *flag.FlagSet) {
+	visibleFlagSet := flag.NewFlagSet(flagSet.Name(), flag.ContinueOnError)
+	visibleFlagSet.SetOutput(c.Output)
+	flagSet.VisitAll(func(f *flag.Flag) {
+		if _, deprecated := DeprecatedFlags[f.Name]; !deprecated {
+			visibleFlagSet.Var(f.Value, f.Name, f.Usage)
+			visibleFlagSet.Lookup(f.Name).DefValue = f.DefValue
+		}
+	})
+	visibleFlagSet.PrintDefaults()
+}
+
+// ParseFlags parses the arguments with the FlagSet and sets the flags not set on the command line from environment variables or
+// the config file, positional arguments are not supported by any command
+func (c *CLI) ParseFlags(flagSet *flag.FlagSet, arguments []string) error {
+	err := flagSet.Parse(arguments)
+	if err != nil {
+		return err
+	}
+
+	if flagSet.NArg() > 0 {
+		return c.UsageError(flagSet, fmt.Errorf("Unexpected argument [%s]", flagSet.Arg(0)))
+	}
+
+	flagSources, err := c.ConfigUtil.ApplyConfig(flagSet)
+	if err != nil {
+		return c.UsageError(flagSet, err)
+	}
+
+	c.flagSet, c.flagSources = flagSet, flagSources
+	if c.configOnly {
+		return errConfigOnly
+	}
+
+	return nil
+}
+
+// UsageError prints the error and the usage information of the command and returns the error
+func (c *CLI) UsageError(flagSet *flag.FlagSet, err error) error {
+	fmt.Fprintln(c.Output, err)
+	flagSet.Usage()
+	return err
+}
+
+// addLogFlags adds the flags controlling the log output to the FlagSet
+func (c *CLI) addLogFlags(flagSet *flag.FlagSet) *logFlags {
+	return &logFlags{
+		flagSet:     flagSet,
+		verbose:     flagSet.Bool("v", false, "Verbose output"),
+		quiet:       flagSet.Bool("quiet", false, "Log warnings and errors only"),
+		logFormat:   flagSet.String("logFormat", PlainLogFormat.String(), "plain, logfmt, or json log format"),
+		logFilename: flagSet.String("logFilename", "", "Append log output to file instead of writing it to stderr")}
+}
+
+// logArgs validates the parsed log flags and warns about DeprecatedFlags set on the command line, in environment variables, or in
+// the config file
+func (f *logFlags) logArgs() (LogArgs, error) {
+	logFormat, err := NewLogFormat(*f.logFormat)
+	if err != nil {
+		return LogArgs{}, err
+	}
+
+	if *f.verbose && *f.quiet {
+		return LogArgs{}, errors.New("Invalid combination of v and quiet")
+	}
+
+	var warnings []string
+	f.flagSet.Visit(func(setFlag *flag.Flag) {
+		if replacement, deprecated := DeprecatedFlags[setFlag.Name]; deprecated {
+			warnings = append(warnings, fmt.Sprintf("Flag [%s] is deprecated, use [%s] instead", setFlag.Name, replacement))
+		}
+	})
+
+	return LogArgs{Verbose: *f.verbose, Quiet: *f.quiet, LogFormat: *logFormat, LogFilename: *f.logFilename, Warnings: warnings}, nil
+}
+
+// addInputFlags adds the flags of commands reading a NoteGraph snapshot to the FlagSet
+func (c *CLI) addInputFlags(flagSet *flag.FlagSet) *inputFlags {
+	return &inputFlags{
+		snapshotFilename: flagSet.String("snapshotFilename", DefaultSnapshotFilename, "NoteGraph snapshot or GraphML file created by fetch or export"),
+		noteFilter:       flagSet.String("noteFilter", "", "Filter expression selecting the Notes to include, e.g. notebook == \"Work\" && degree >= 2"),
+		linkFilter:       flagSet.String("linkFilter", "", "Filter expression selecting the NoteLinks to include, e.g. link.type in [AppLink, WebLink]")}
+}
+
+// inputArgs validates the parsed input flags
+func (f *inputFlags) inputArgs() (InputArgs, error) {
+	noteGraphFilter, err := NewNoteGraphFilter(*f.noteFilter, *f.linkFilter)
+	if err != nil {
+		return InputArgs{}, err
+	}
+
+	return InputArgs{SnapshotFilename: *f.snapshotFilename, NoteGraphFilter: noteGraphFilter}, nil
+}
+
+// addOutputFlags adds the flags of commands exporting a NoteGraph to the FlagSet, the outputFormat and outputFilename flags are
+// only added if the command writes an output file
+func (c *CLI) addOutputFlags(flagSet *flag.FlagSet, outputFile bool, outputFilenameUsage string) *outputFlags {
+	outputFlags := &outputFlags{}
+	outputFlags.linkedNotes = flagSet.Bool("linkedNotes", true, "Include only linked Notes")
+	if outputFile {
+		outputFlags.outputFormat = flagSet.String("outputFormat", "GraphML", "GraphML, GEXF, DOT, Cytoscape, JGF, Cypher, Neo4jCSV, SQLite, Vault, HTML, SVG, or PNG output format")
+		outputFlags.outputFilename = flagSet.String("outputFilename", "", outputFilenameUsage)
+		outputFlags.graphMLFilename = flagSet.String("graphMLFilename", "", "Deprecated alias of outputFilename")
+	}
+	outputFlags.clusterNotebooks = flagSet.Bool("clusterNotebooks", false, "Group Notes in one cluster per notebook (DOT only)")
+	outputFlags.brokenLinks = flagSet.Bool("brokenLinks", false, "Include broken NoteLinks as relationships to placeholder nodes (Cypher and Neo4jCSV only)")
+	outputFlags.yEdGraphics = flagSet.Bool("yEdGraphics", false, "Add node and edge graphics for yEd (GraphML only)")
+	outputFlags.yEdColorBy = flagSet.String("yEdColorBy", "notebook", "Fill colour of nodes by notebook or community (yEdGraphics only, community requires analyze)")
+	outputFlags.aggregateLinks = flagSet.Bool("aggregateLinks", false, "Collapse parallel NoteLinks into a single edge with weight attribute (GraphML only)")
+	outputFlags.dropSelfLoops = flagSet.Bool("dropSelfLoops", false, "Omit NoteLinks from a Note to itself (GraphML only)")
+	outputFlags.undirected = flagSet.Bool("undirected", false, "Merge NoteLinks in both directions into a single undirected edge with weight attribute (GraphML only)")
+	outputFlags.reciprocal = flagSet.Bool("reciprocal", false, "Add reciprocal edge attribute for NoteLinks in both directions (GraphML only)")
+	outputFlags.layout = flagSet.Bool("layout", false, "Compute force-directed layout and add x and y node attributes")
+	outputFlags.layoutIterations = flagSet.Int("layoutIterations", DefaultLayoutIterations, "Iterations of the force-directed layout")
+	outputFlags.labels = flagSet.Bool("labels", false, "Draw Note titles as node labels (SVG and PNG only)")
+	return outputFlags
+}
+
+// outputArgs validates the parsed output flags, the NodeColorBy community requires the NoteGraph to be analyzed, the output
+// filename is read from the deprecated graphMLFilename flag if not set and defaults to notegraph with the extension of the output
+// format if defaultOutputFilename is set
+func (f *outputFlags) outputArgs(analyze bool, defaultOutputFilename bool) (OutputArgs, error) {
+	outputFormat, outputFilename := GraphML, ""
+	if f.outputFormat != nil {
+		noteGraphOutputFormat, err := NewOutputFormat(*f.outputFormat)
+		if err != nil {
+			return OutputArgs{}, err
+		}
+
+		outputFormat, outputFilename = *noteGraphOutputFormat, *f.outputFilename
+		if outputFilename == "" {
+			outputFilename = *f.graphMLFilename
+		}
+		if outputFilename == "" && defaultOutputFilename {
+			outputFilename = "notegraph" + outputFormat.Extension()
+		}
+	}
+
+	nodeColorBy, err := NewNodeColorBy(*f.yEdColorBy)
+	if err != nil {
+		return OutputArgs{}, err
+	}
+
+	if *nodeColorBy == CommunityColor && !analyze {
+		return OutputArgs{}, errors.New("Invalid yEdColorBy [community] without analyze")
+	}
+
+	if *f.layoutIterations <= 0 {
+		return OutputArgs{}, fmt.Errorf("Invalid layoutIterations [%d]", *f.layoutIterations)
+	}
+
+	return OutputArgs{
+		LinkedNotes:    *f.linkedNotes,
+		OutputFormat:   outputFormat,
+		OutputFilename: outputFilename,
+		ExportOptions:  ExportOptions{ClusterNotebooks: *f.clusterNotebooks, BrokenLinks: *f.brokenLinks, YFiles: *f.yEdGraphics, NodeColorBy: *nodeColorBy, AggregateLinks: *f.aggregateLinks, DropSelfLoops: *f.dropSelfLoops, Undirected: *f.undirected, Reciprocal: *f.reciprocal, Labels: *f.labels, LayoutIterations: *f.layoutIterations},
+		Layout:         *f.layout}, nil
+}
+
+// ParseFetchArgs parses the arguments of the fetch command
+func (c *CLI) ParseFetchArgs(arguments []string) (*FetchArgs, error) {
+	flagSet := c.NewFlagSet("fetch")
+	edamAuthToken := flagSet.String("edamAuthToken", "", "Evernote API auth token (required)")
+	sandbox := flagSet.Bool("sandbox", false, "Use sandbox.evernote.com")
+	noteURL := flagSet.String("noteURL", "WebLink", "WebLink or AppLink for Note URLs")
+	noteContent := flagSet.Bool("noteContent", false, "Save the Note content in the NoteGraph snapshot (required for Vault output format)")
+	snapshotFilename := flagSet.String("snapshotFilename", DefaultSnapshotFilename, "NoteGraph snapshot output filename")
+	progress := flagSet.String("progress", AutoProgress.String(), "auto, bar, lines, or none progress display, auto shows a live bar if stderr is a terminal and logs summary lines otherwise")
+	logFlags := c.addLogFlags(flagSet)
+
+	if err := c.ParseFlags(flagSet, arguments); err != nil {
+		return nil, err
+	}
+
+	logArgs, err := logFlags.logArgs()
+	if err != nil {
+		return nil, c.UsageError(flagSet, err)
+	}
+
+	if *edamAuthToken == "" {
+		return nil, c.UsageError(flagSet, errors.New("Missing edamAuthToken"))
+	}
+
+	noteURLType, err := NewURLType(*noteURL)
+	if err != nil || (*noteURLType != WebLink && *noteURLType != AppLink) {
+		return nil, c.UsageError(flagSet, errors.New("Invalid noteURL ["+*noteURL+"]"))
+	}
+
+	progressMode, err := NewProgressMode(*progress)
+	if err != nil {
+		return nil, c.UsageError(flagSet, err)
+	}
+
+	return &FetchArgs{
+		EdamAuthToken:    *edamAuthToken,
+		Sandbox:          *sandbox,
+		NoteURLType:      *noteURLType,
+		NoteContent:      *noteContent,
+		SnapshotFilename: *snapshotFilename,
+		ProgressMode:     *progressMode,
+		LogArgs:          logArgs}, nil
+}
+
+// ParseExportArgs parses the arguments of the export command
+func (c *CLI) ParseExportArgs(arguments []string) (*ExportArgs, error) {
+	flagSet := c.NewFlagSet("export")
+	inputFlags := c.addInputFlags(flagSet)
+	outputFlags := c.addOutputFlags(flagSet, true, "Output filename, - for stdout (default \"notegraph\" with extension of output format)")
+	analyze := flagSet.Bool("analyze", false, "Detect connected components and communities and add component and community node attributes")
+	logFlags := c.addLogFlags(flagSet)
+
+	if err := c.ParseFlags(flagSet, arguments); err != nil {
+		return nil, err
+	}
+
+	logArgs, err := logFlags.logArgs()
+	if err != nil {
+		return nil, c.UsageError(flagSet, err)
+	}
+
+	inputArgs, err := inputFlags.inputArgs()
+	if err != nil {
+		return nil, c.UsageError(flagSet, err)
+	}
+
+	outputArgs, err := outputFlags.outputArgs(*analyze, true)
+	if err != nil {
+		return nil, c.UsageError(flagSet, err)
+	}
+
+	return &ExportArgs{InputArgs: inputArgs, OutputArgs: outputArgs, Analyze: *analyze, LogArgs: logArgs}, nil
+}
+
+// ParseStatsArgs parses the arguments of the stats command
+func (c *CLI) ParseStatsArgs(arguments []string) (*StatsArgs, error) {
+	flagSet := c.NewFlagSet("stats")
+	inputFlags := c.addInputFlags(flagSet)
+	analyze := flagSet.Bool("analyze", false, "Detect connected components and communities")
+	hygieneReport := flagSet.Bool("hygieneReport", false, "Print hygiene report with orphan, dead-end, and hub Notes")
+	reportFilename := flagSet.String("reportFilename", "", "Hygiene report JSON output filename")
+	fanOutThreshold := flagSet.Int("fanOutThreshold", DefaultFanOutThreshold, "Outgoing NoteLinks from which a Note is reported as high fan-out Note")
+	fanInThreshold := flagSet.Int("fanInThreshold", DefaultFanInThreshold, "Incoming NoteLinks from which a Note is reported as hub Note")
+	logFlags := c.addLogFlags(flagSet)
+
+	if err := c.ParseFlags(flagSet, arguments); err != nil {
+		return nil, err
+	}
+
+	logArgs, err := logFlags.logArgs()
+	if err != nil {
+		return nil, c.UsageError(flagSet, err)
+	}
+
+	inputArgs, err := inputFlags.inputArgs()
+	if err != nil {
+		return nil, c.UsageError(flagSet, err)
+	}
+
+	return &StatsArgs{
+		InputArgs:      inputArgs,
+		Analyze:        *analyze,
+		HygieneReport:  *hygieneReport,
+		ReportFilename: *reportFilename,
+		Thresholds:     HygieneThresholds{FanOut: *fanOutThreshold, FanIn: *fanInThreshold},
+		LogArgs:        logArgs}, nil
+}
+
+// ParseQueryArgs parses the arguments of the query command, at least one of backlinks, paths, or ego network must be queried
+func (c *CLI) ParseQueryArgs(arguments []string) (*QueryArgs, error) {
+	flagSet := c.NewFlagSet("query")
+	inputFlags := c.addInputFlags(flagSet)
+	backlinks := flagSet.String("backlinks", "", "GUID, title, or URL of the Note to print the backlinks of")
+	pathFrom := flagSet.String("pathFrom", "", "GUID, title, or URL of the Note to find paths from")
+	pathTo := flagSet.String("pathTo", "", "GUID, title, or URL of the Note to find paths to")
+	pathMaxLength := flagSet.Int("pathMaxLength", 0, "Find all paths with up to this number of NoteLinks in addition to shortest paths")
+	pathFilename := flagSet.String("pathGraphMLFilename", "", "GraphML output filename for the Notes along the paths")
+	egoNote := flagSet.String("egoNote", "", "GUID, title, or URL of the focal Note of the ego network")
+	egoDepth := flagSet.Int("egoDepth", 1, "Maximum number of NoteLinks between focal Note and Notes of the ego network")
+	egoDirection := flagSet.String("egoDirection", "both", "Follow NoteLinks out, in, or both directions for the ego network")
+	outputFlags := c.addOutputFlags(flagSet, true, "Output filename for the ego network, - for stdout (ego network is not exported if empty)")
+	logFlags := c.addLogFlags(flagSet)
+
+	if err := c.ParseFlags(flagSet, arguments); err != nil {
+		return nil, err
+	}
+
+	logArgs, err := logFlags.logArgs()
+	if err != nil {
+		return nil, c.UsageError(flagSet, err)
+	}
+
+	if *backlinks == "" && *pathFrom == "" && *pathTo == "" && *egoNote == "" {
+		return nil, c.UsageError(flagSet, errors.New("Missing backlinks, pathFrom and pathTo, or egoNote"))
+	}
+
+	if (*pathFrom == "") != (*pathTo == "") || *pathMaxLength < 0 {
+		return nil, c.UsageError(flagSet, errors.New("Invalid path, pathFrom and pathTo are both required and pathMaxLength must not be negative"))
+	}
+
+	egoLinkDirection, err := NewLinkDirection(*egoDirection)
+	if err != nil {
+		return nil, c.UsageError(flagSet, err)
+	}
+
+	if *egoDepth < 0 {
+		return nil, c.UsageError(flagSet, fmt.Errorf("Invalid egoDepth [%d]", *egoDepth))
+	}
+
+	inputArgs, err := inputFlags.inputArgs()
+	if err != nil {
+		return nil, c.UsageError(flagSet, err)
+	}
+
+	outputArgs, err := outputFlags.outputArgs(false, false)
+	if err != nil {
+		return nil, c.UsageError(flagSet, err)
+	}
+
+	return &QueryArgs{
+		InputArgs:     inputArgs,
+		OutputArgs:    outputArgs,
+		Backlinks:     *backlinks,
+		EgoNote:       *egoNote,
+		EgoDepth:      *egoDepth,
+		EgoDirection:  *egoLinkDirection,
+		PathFrom:      *pathFrom,
+		PathTo:        *pathTo,
+		PathMaxLength: *pathMaxLength,
+		PathFilename:  *pathFilename,
+		LogArgs:       logArgs}, nil
+}
+
+// ParseServeArgs parses the arguments of the serve command
+func (c *CLI) ParseServeArgs(arguments []string) (*ServeArgs, error) {
+	flagSet := c.NewFlagSet("serve")
+	inputFlags := c.addInputFlags(flagSet)
+	outputFlags := c.addOutputFlags(flagSet, false, "")
+	address := flagSet.String("address", DefaultAddress, "Network address to serve the NoteGraph on")
+	analyze := flagSet.Bool("analyze", false, "Detect connected components and communities and add component and community node attributes")
+	logFlags := c.addLogFlags(flagSet)
+
+	if err := c.ParseFlags(flagSet, arguments); err != nil {
+		return nil, err
+	}
+
+	logArgs, err := logFlags.logArgs()
+	if err != nil {
+		return nil, c.UsageError(flagSet, err)
+	}
+
+	inputArgs, err := inputFlags.inputArgs()
+	if err != nil {
+		return nil, c.UsageError(flagSet, err)
+	}
+
+	outputArgs, err := outputFlags.outputArgs(*analyze, false)
+	if err != nil {
+		return nil, c.UsageError(flagSet, err)
+	}
+
+	return &ServeArgs{InputArgs: inputArgs, OutputArgs: outputArgs, Address: *address, Analyze: *analyze, LogArgs: logArgs}, nil
+}
+
+// ParseDiffArgs parses the arguments of the diff command
+func (c *CLI) ParseDiffArgs(arguments []string) (*DiffArgs, error) {
+	flagSet := c.NewFlagSet("diff")
+	diffFrom := flagSet.String("diffFrom", "", "Previous NoteGraph snapshot or GraphML file to compare (required)")
+	diffTo := flagSet.String("diffTo", "", "Current NoteGraph snapshot or GraphML file to compare (required)")
+	diffFilename := flagSet.String("diffGraphMLFilename", "", "GraphML output filename for the NoteGraph diff")
+	logFlags := c.addLogFlags(flagSet)
+
+	if err := c.ParseFlags(flagSet, arguments); err != nil {
+		return nil, err
+	}
+
+	logArgs, err := logFlags.logArgs()
+	if err != nil {
+		return nil, c.UsageError(flagSet, err)
+	}
+
+	if *diffFrom == "" || *diffTo == "" {
+		return nil, c.UsageError(flagSet, errors.New("Missing diffFrom or diffTo"))
+	}
+
+	return &DiffArgs{DiffFrom: *diffFrom, DiffTo: *diffTo, DiffFilename: *diffFilename, LogArgs: logArgs}, nil
+}
+
+// ParseConfigArgs parses the arguments of the config command, the first argument is the command whose effective configuration is
+// printed followed by the flags of that command, the flags are not validated
+func (c *CLI) ParseConfigArgs(arguments []string) (*ConfigArgs, error) {
+	flagSet := flag.NewFlagSet("config", flag.ContinueOnError)
+	flagSet.SetOutput(c.Output)
+	flagSet.Usage = func() {
+		fmt.Fprintf(c.Output, "Usage: %s config <command> [flags of command]\n\n%s\n", ProgramName, c.FindCommand("config").Description)
+	}
+
+	if err := flagSet.Parse(arguments); err != nil {
+		return nil, err
+	}
+
+	if flagSet.NArg() == 0 {
+		return nil, c.UsageError(flagSet, errors.New("Missing command"))
+	}
+
+	command := c.FindCommand(flagSet.Arg(0))
+	if command == nil || command.Name == "config" {
+		return nil, c.UsageError(flagSet, fmt.Errorf("Invalid command [%s]", flagSet.Arg(0)))
+	}
+
+	c.configOnly = true
+	defer func() { c.configOnly = false }()
+	if _, err := command.Parse(c, flagSet.Args()[1:]); !errors.Is(err, errConfigOnly) {
+		return nil, err
+	}
+
+	return &ConfigArgs{FlagSet: c.flagSet, Sources: c.flagSources}, nil
+}
@@ -0,0 +1,282 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func NewTestCLI(output io.Writer, env map[string]string) *CLI {
+	cli := NewCLI(output)
+	cli.Stdout = output
+	cli.DefaultConfigFilename = ""
+	cli.ConfigUtil = NewConfigUtil(func(key string) (string, bool) {
+		value, found := env[key]
+		return value, found
+	})
+
+	return cli
+}
+
+func TestCLIRun(t *testing.T) {
+	var output bytes.Buffer
+	cli := NewTestCLI(&output, map[string]string{})
+
+	assert.Equal(t, ExitUsage, cli.Run([]string{}))
+	assert.Contains(t, output.String(), "Usage: evernote-note-graph <command> [flags]")
+	assert.Contains(t, output.String(), "  fetch    Fetch Notes and NoteLinks from the Evernote API")
+
+	output.Reset()
+	assert.Equal(t, ExitOK, cli.Run([]string{"-h"}))
+	assert.Contains(t, output.String(), "  serve    Serve the NoteGraph snapshot")
+
+	output.Reset()
+	assert.Equal(t, ExitUsage, cli.Run([]string{"crawl"}))
+	assert.Contains(t, output.String(), "Unknown command [crawl]")
+
+	output.Reset()
+	assert.Equal(t, ExitOK, cli.Run([]string{"export", "-h"}))
+	assert.Contains(t, output.String(), "Usage: evernote-note-graph export [flags]")
+	assert.Contains(t, output.String(), "-outputFormat string")
+	assert.NotContains(t, output.String(), "-edamAuthToken")
+
+	output.Reset()
+	assert.Equal(t, ExitUsage, cli.Run([]string{"fetch"}))
+	assert.Contains(t, output.String(), "Missing edamAuthToken")
+}
+
+func TestCLIParseFetchArgs(t *testing.T) {
+	cli := NewTestCLI(&bytes.Buffer{}, map[string]string{})
+
+	fetchArgs, err := cli.ParseFetchArgs([]string{"-edamAuthToken=token", "-noteURL=AppLink", "-v"})
+	assert.Nil(t, err)
+	assert.Equal(t, &FetchArgs{EdamAuthToken: "token", NoteURLType: AppLink, SnapshotFilename: DefaultSnapshotFilename, LogArgs: LogArgs{Verbose: true}}, fetchArgs)
+
+	fetchArgs, err = cli.ParseFetchArgs([]string{"-edamAuthToken=token", "-noteContent"})
+	assert.Nil(t, err)
+	assert.True(t, fetchArgs.NoteContent)
+
+	_, err = cli.ParseFetchArgs([]string{"-edamAuthToken=token", "-noteURL=PublicLink"})
+	assert.NotNil(t, err)
+
+	fetchArgs, err = cli.ParseFetchArgs([]string{"-edamAuthToken=token", "-quiet", "-logFormat=json", "-logFilename=fetch.log"})
+	assert.Nil(t, err)
+	assert.Equal(t, LogArgs{Quiet: true, LogFormat: JSONLogFormat, LogFilename: "fetch.log"}, fetchArgs.LogArgs)
+
+	_, err = cli.ParseFetchArgs([]string{"-edamAuthToken=token", "-v", "-quiet"})
+	assert.Contains(t, err.Error(), "Invalid combination of v and quiet")
+
+	_, err = cli.ParseFetchArgs([]string{"-edamAuthToken=token", "-logFormat=xml"})
+	assert.NotNil(t, err)
+
+	fetchArgs, err = cli.ParseFetchArgs([]string{"-edamAuthToken=token", "-progress=lines"})
+	assert.Nil(t, err)
+	assert.Equal(t, LinesProgress, fetchArgs.ProgressMode)
+
+	_, err = cli.ParseFetchArgs([]string{"-edamAuthToken=token", "-progress=spinner"})
+	assert.NotNil(t, err)
+
+	_, err = cli.ParseFetchArgs([]string{"-edamAuthToken=token", "notegraph.json"})
+	assert.Contains(t, err.Error(), "Unexpected argument [notegraph.json]")
+}
+
+func TestCLIParseExportArgs(t *testing.T) {
+	cli := NewTestCLI(&bytes.Buffer{}, map[string]string{})
+
+	exportArgs, err := cli.ParseExportArgs([]string{"-snapshotFilename=snapshot.json", "-outputFormat=DOT", "-clusterNotebooks", "-noteFilter=degree > 1"})
+	assert.Nil(t, err)
+	assert.Equal(t, "snapshot.json", exportArgs.SnapshotFilename)
+	assert.NotNil(t, exportArgs.NoteGraphFilter.NoteExpression)
+	assert.Nil(t, exportArgs.NoteGraphFilter.NoteLinkExpression)
+	assert.Equal(t, DOT, exportArgs.OutputFormat)
+	assert.Equal(t, "notegraph.dot", exportArgs.OutputFilename)
+	assert.True(t, exportArgs.ExportOptions.ClusterNotebooks)
+	assert.True(t, exportArgs.LinkedNotes)
+
+	exportArgs, err = cli.ParseExportArgs([]string{"-outputFilename=-", "-yEdColorBy=community", "-analyze"})
+	assert.Nil(t, err)
+	assert.Equal(t, DefaultSnapshotFilename, exportArgs.SnapshotFilename)
+	assert.Equal(t, GraphML, exportArgs.OutputFormat)
+	assert.Equal(t, StdoutFilename, exportArgs.OutputFilename)
+	assert.Equal(t, CommunityColor, exportArgs.ExportOptions.NodeColorBy)
+
+	_, err = cli.ParseExportArgs([]string{"-yEdColorBy=community"})
+	assert.NotNil(t, err)
+
+	_, err = cli.ParseExportArgs([]string{"-outputFormat=PDF"})
+	assert.NotNil(t, err)
+
+	_, err = cli.ParseExportArgs([]string{"-noteFilter=degree >"})
+	assert.Contains(t, err.Error(), "Invalid filter expression")
+
+	_, err = cli.ParseExportArgs([]string{"-h"})
+	assert.True(t, errors.Is(err, flag.ErrHelp))
+}
+
+func TestCLIParseExportArgsDeprecatedGraphMLFilename(t *testing.T) {
+	output := &bytes.Buffer{}
+	cli := NewTestCLI(output, map[string]string{})
+
+	exportArgs, err := cli.ParseExportArgs([]string{"-graphMLFilename=old.graphml"})
+	assert.Nil(t, err)
+	assert.Equal(t, "old.graphml", exportArgs.OutputFilename)
+	assert.Equal(t, []string{"Flag [graphMLFilename] is deprecated, use [outputFilename] instead"}, exportArgs.Warnings)
+
+	exportArgs, err = cli.ParseExportArgs([]string{"-graphMLFilename=old.graphml", "-outputFilename=new.graphml"})
+	assert.Nil(t, err)
+	assert.Equal(t, "new.graphml", exportArgs.OutputFilename)
+
+	_, err = cli.ParseExportArgs([]string{"-h"})
+	assert.True(t, errors.Is(err, flag.ErrHelp))
+	assert.Contains(t, output.String(), "-outputFilename")
+	assert.NotContains(t, output.String(), "graphMLFilename")
+}
+
+func TestCLIParseStatsArgs(t *testing.T) {
+	statsArgs, err := NewTestCLI(&bytes.Buffer{}, map[string]string{}).ParseStatsArgs([]string{"-analyze", "-hygieneReport", "-fanInThreshold=3"})
+	assert.Nil(t, err)
+	assert.True(t, statsArgs.Analyze)
+	assert.True(t, statsArgs.HygieneReport)
+	assert.Equal(t, HygieneThresholds{FanOut: DefaultFanOutThreshold, FanIn: 3}, statsArgs.Thresholds)
+}
+
+func TestCLIParseQueryArgs(t *testing.T) {
+	cli := NewTestCLI(&bytes.Buffer{}, map[string]string{})
+
+	queryArgs, err := cli.ParseQueryArgs([]string{"-backlinks=Project X", "-egoNote=Project Y", "-egoDirection=out"})
+	assert.Nil(t, err)
+	assert.Equal(t, "Project X", queryArgs.Backlinks)
+	assert.Equal(t, "Project Y", queryArgs.EgoNote)
+	assert.Equal(t, Outgoing, queryArgs.EgoDirection)
+	assert.Equal(t, "", queryArgs.OutputFilename)
+
+	queryArgs, err = cli.ParseQueryArgs([]string{"-pathFrom=A", "-pathTo=B", "-pathMaxLength=3"})
+	assert.Nil(t, err)
+	assert.Equal(t, 3, queryArgs.PathMaxLength)
+
+	_, err = cli.ParseQueryArgs([]string{})
+	assert.NotNil(t, err)
+
+	_, err = cli.ParseQueryArgs([]string{"-pathFrom=A"})
+	assert.NotNil(t, err)
+
+	_, err = cli.ParseQueryArgs([]string{"-egoNote=A", "-egoDirection=sideways"})
+	assert.NotNil(t, err)
+}
+
+func TestCLIParseServeArgs(t *testing.T) {
+	cli := NewTestCLI(&bytes.Buffer{}, map[string]string{})
+
+	serveArgs, err := cli.ParseServeArgs([]string{"-address=:9090", "-linkedNotes=false"})
+	assert.Nil(t, err)
+	assert.Equal(t, ":9090", serveArgs.Address)
+	assert.False(t, serveArgs.LinkedNotes)
+
+	_, err = cli.ParseServeArgs([]string{"-outputFormat=HTML"})
+	assert.NotNil(t, err)
+}
+
+func TestCLIParseDiffArgs(t *testing.T) {
+	cli := NewTestCLI(&bytes.Buffer{}, map[string]string{})
+
+	diffArgs, err := cli.ParseDiffArgs([]string{"-diffFrom=a.json", "-diffTo=b.json"})
+	assert.Nil(t, err)
+	assert.Equal(t, &DiffArgs{DiffFrom: "a.json", DiffTo: "b.json"}, diffArgs)
+
+	_, err = cli.ParseDiffArgs([]string{"-diffFrom=a.json"})
+	assert.NotNil(t, err)
+}
+
+func TestCLIParseArgsWithConfig(t *testing.T) {
+	testConfigFile := filepath.Join(os.TempDir(), "testConfig.yaml")
+	defer os.Remove(testConfigFile)
+
+	err := os.WriteFile(testConfigFile, []byte("edamAuthToken: fileToken\nsnapshotFilename: file.json\nsandbox: true\nexport:\n  outputFormat: GEXF\n"), 0600)
+	if err != nil {
+		panic(err)
+	}
+
+	cli := NewTestCLI(&bytes.Buffer{}, map[string]string{"EVERNOTE_NOTE_GRAPH_CONFIG": testConfigFile, "EVERNOTE_NOTE_GRAPH_SNAPSHOT_FILENAME": "env.json"})
+
+	fetchArgs, err := cli.ParseFetchArgs([]string{"-noteURL=AppLink"})
+	assert.Nil(t, err)
+	assert.Equal(t, &FetchArgs{EdamAuthToken: "fileToken", Sandbox: true, NoteURLType: AppLink, SnapshotFilename: "env.json"}, fetchArgs)
+
+	exportArgs, err := cli.ParseExportArgs([]string{"-snapshotFilename=flag.json"})
+	assert.Nil(t, err)
+	assert.Equal(t, "flag.json", exportArgs.SnapshotFilename)
+	assert.Equal(t, GEXF, exportArgs.OutputFormat)
+	assert.Equal(t, "notegraph.gexf", exportArgs.OutputFilename)
+
+	statsArgs, err := cli.ParseStatsArgs([]string{"-config=" + testConfigFile + ".missing"})
+	assert.Nil(t, statsArgs)
+	assert.Contains(t, err.Error(), "Failed to read config file")
+}
+
+func TestCLIConfig(t *testing.T) {
+	var output bytes.Buffer
+	cli := NewTestCLI(&output, map[string]string{"EVERNOTE_NOTE_GRAPH_EDAM_AUTH_TOKEN": "S=s1:U=1:secret"})
+
+	assert.Equal(t, ExitOK, cli.Run([]string{"config", "fetch", "-sandbox"}))
+	assert.NotContains(t, output.String(), "secret")
+	assert.Regexp(t, `edamAuthToken\s+"<redacted>"\s+env\s+EVERNOTE_NOTE_GRAPH_EDAM_AUTH_TOKEN`, output.String())
+	assert.Regexp(t, `sandbox\s+"true"\s+flag\s+EVERNOTE_NOTE_GRAPH_SANDBOX`, output.String())
+	assert.Regexp(t, `noteURL\s+"WebLink"\s+default\s+EVERNOTE_NOTE_GRAPH_NOTE_URL`, output.String())
+
+	output.Reset()
+	assert.Equal(t, ExitOK, cli.Run([]string{"config", "query"}))
+	assert.Contains(t, output.String(), "egoDirection")
+
+	output.Reset()
+	assert.Equal(t, ExitUsage, cli.Run([]string{"config"}))
+	assert.Contains(t, output.String(), "Missing command")
+
+	output.Reset()
+	assert.Equal(t, ExitUsage, cli.Run([]string{"config", "config"}))
+	assert.Contains(t, output.String(), "Invalid command [config]")
+}
+
+func TestCLIRunErrors(t *testing.T) {
+	testSnapshotFile := filepath.Join(os.TempDir(), "testCLISnapshot.json")
+	defer os.Remove(testSnapshotFile)
+
+	noteGraph := NewNoteGraph()
+	noteGraph.Add(Note{GUID: "A", Title: "TitleA"}, []NoteLink{})
+	err := NewSnapshotUtil().SaveSnapshot(testSnapshotFile, noteGraph)
+	if err != nil {
+		panic(err)
+	}
+
+	var output bytes.Buffer
+	cli := NewTestCLI(&output, map[string]string{})
+
+	assert.Equal(t, ExitParse, cli.Run([]string{"stats", "-snapshotFilename=" + testSnapshotFile + ".missing"}))
+	assert.Contains(t, output.String(), "Error (parse): Failed to load NoteGraph snapshot file")
+	assert.Contains(t, output.String(), "Hint: Check that the input file is a NoteGraph snapshot")
+
+	output.Reset()
+	outputFilename := filepath.Join(os.TempDir(), "missing", "notegraph.graphml")
+	assert.Equal(t, ExitOutput, cli.Run([]string{"export", "-snapshotFilename=" + testSnapshotFile, "-outputFilename=" + outputFilename}))
+	assert.Contains(t, output.String(), "Error (output): Failed to save NoteGraph to GraphML file")
+
+	output.Reset()
+	assert.Equal(t, ExitError, cli.Run([]string{"query", "-snapshotFilename=" + testSnapshotFile, "-backlinks=TitleB"}))
+	assert.Contains(t, output.String(), "Error (unknown): Failed to find Note [TitleB] in NoteGraph")
+
+	testLogFile := filepath.Join(os.TempDir(), "testCLI.log")
+	defer os.Remove(testLogFile)
+	defer InitLogger(LogArgs{}, false)
+
+	output.Reset()
+	assert.Equal(t, ExitParse, cli.Run([]string{"stats", "-snapshotFilename=" + testSnapshotFile + ".missing", "-logFormat=json", "-logFilename=" + testLogFile}))
+	assert.Contains(t, output.String(), "Error (parse): Failed to load NoteGraph snapshot file")
+	logContent, err := os.ReadFile(testLogFile)
+	assert.Nil(t, err)
+	assert.Contains(t, string(logContent), `"exit_code":6,"kind":"parse","level":"error","msg":"Command failed"`)
+}
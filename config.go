@@ -0,0 +1,225 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigFlagName is the name of the flag selecting the config file
+const ConfigFlagName = "config"
+
+// EnvPrefix is the prefix of the environment variables of the flags, e.g. EVERNOTE_NOTE_GRAPH_EDAM_AUTH_TOKEN for -edamAuthToken
+const EnvPrefix = "EVERNOTE_NOTE_GRAPH_"
+
+// RedactedValue replaces the values of SecretFlags when the configuration is printed
+const RedactedValue = "<redacted>"
+
+// SecretFlags contains the names of the flags whose values are never printed
+var SecretFlags = map[string]bool{"edamAuthToken": true}
+
+// DeprecatedFlags maps the names of flags kept for compatibility with earlier versions to the names of the flags replacing them,
+// deprecated flags are accepted but neither listed in the usage information nor printed with the configuration
+var DeprecatedFlags = map[string]string{"graphMLFilename": "outputFilename"}
+
+// Enum of all ConfigSources in ascending order of precedence
+const (
+	DefaultSource ConfigSource = iota // default value of the flag
+	FileSource    ConfigSource = iota // value from the config file
+	EnvSource     ConfigSource = iota // value from the environment variable
+	FlagSource    ConfigSource = iota // value from the command line
+)
+
+// ConfigSource identifies where the effective value of a flag comes from
+type ConfigSource int
+
+func (cs ConfigSource) String() string {
+	return [...]string{"default", "file", "env", "flag"}[cs]
+}
+
+// Config contains the flag values of the config file, values at the top level apply to all commands with a flag of the same name,
+// values in a section named after a command apply to that command only and take precedence over top level values
+type Config struct {
+	Values        map[string]string
+	CommandValues map[string]map[string]string
+}
+
+// Value returns the value of the flag for the command
+func (c *Config) Value(command, name string) (string, bool) {
+	if value, found := c.CommandValues[command][name]; found {
+		return value, true
+	}
+
+	value, found := c.Values[name]
+	return value, found
+}
+
+// ConfigUtil applies the config file and environment variables to the flags of a command
+type ConfigUtil struct {
+	LookupEnv func(key string) (string, bool)
+}
+
+// NewConfigUtil creates a new instance of ConfigUtil reading environment variables with lookupEnv
+func NewConfigUtil(lookupEnv func(key string) (string, bool)) *ConfigUtil {
+	return &ConfigUtil{LookupEnv: lookupEnv}
+}
+
+// DefaultConfigFilename returns the config file in the user config directory, e.g. ~/.config/evernote-note-graph/config.yaml on
+// Linux, or an empty string if the user config directory is unknown
+func DefaultConfigFilename() string {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+
+	return filepath.Join(configDir, ProgramName, "config.yaml")
+}
+
+// EnvName returns the name of the environment variable of the flag, the camel case flag name is converted to upper snake case
+// keeping acronyms together, e.g. noteURL to NOTE_URL
+func (cu *ConfigUtil) EnvName(flagName string) string {
+	runes := []rune(flagName)
+	var envName strings.Builder
+	envName.WriteString(EnvPrefix)
+	for index, r := range runes {
+		if index > 0 && unicode.IsUpper(r) && (unicode.IsLower(runes[index-1]) || (index+1 < len(runes) && unicode.IsLower(runes[index+1]))) {
+			envName.WriteRune('_')
+		}
+		envName.WriteRune(unicode.ToUpper(r))
+	}
+
+	return envName.String()
+}
+
+// LoadConfig loads the YAML config file, a missing config file results in an empty Config unless the config file is required
+func (cu *ConfigUtil) LoadConfig(filename string, required bool) (*Config, error) {
+	config := &Config{Values: map[string]string{}, CommandValues: map[string]map[string]string{}}
+	if filename == "" {
+		return config, nil
+	}
+
+	content, readErr := os.ReadFile(filename)
+	if errors.Is(readErr, os.ErrNotExist) && !required {
+		return config, nil
+	} else if readErr != nil {
+		return nil, fmt.Errorf("Failed to read config file [%s]: %w", filename, readErr)
+	}
+
+	document := map[string]interface{}{}
+	if unmarshalErr := yaml.Unmarshal(content, &document); unmarshalErr != nil {
+		return nil, fmt.Errorf("Failed to parse config file [%s]: %w", filename, unmarshalErr)
+	}
+
+	for key, value := range document {
+		if section, isSection := value.(map[string]interface{}); isSection {
+			config.CommandValues[key] = map[string]string{}
+			for name, sectionValue := range section {
+				flagValue, isFlagValue := cu.FlagValue(sectionValue)
+				if !isFlagValue {
+					return nil, fmt.Errorf("Invalid value of [%s.%s] in config file [%s]", key, name, filename)
+				}
+				config.CommandValues[key][name] = flagValue
+			}
+		} else {
+			flagValue, isFlagValue := cu.FlagValue(value)
+			if !isFlagValue {
+				return nil, fmt.Errorf("Invalid value of [%s] in config file [%s]", key, filename)
+			}
+			config.Values[key] = flagValue
+		}
+	}
+
+	return config, nil
+}
+
+// FlagValue converts the YAML scalar to a flag value, lists, mappings, and null are not supported
+func (cu *ConfigUtil) FlagValue(value interface{}) (string, bool) {
+	switch value.(type) {
+	case string, bool, int, float64:
+		return fmt.Sprint(value), true
+	}
+
+	return "", false
+}
+
+// ConfigFilename returns the config file selected with the config flag or environment variable, or else the default value of the
+// config flag, and the ConfigSource of the config file
+func (cu *ConfigUtil) ConfigFilename(flagSet *flag.FlagSet, explicit map[string]bool) (string, ConfigSource) {
+	if explicit[ConfigFlagName] {
+		return flagSet.Lookup(ConfigFlagName).Value.String(), FlagSource
+	}
+
+	if filename, found := cu.LookupEnv(cu.EnvName(ConfigFlagName)); found {
+		return filename, EnvSource
+	}
+
+	return flagSet.Lookup(ConfigFlagName).DefValue, DefaultSource
+}
+
+// ApplyConfig sets all flags of the command not set on the command line from environment variables or else from the config file
+// selected with the config flag and returns the ConfigSource of each flag, a missing config file is ignored unless it is selected
+// explicitly
+func (cu *ConfigUtil) ApplyConfig(flagSet *flag.FlagSet) (map[string]ConfigSource, error) {
+	explicit := map[string]bool{}
+	flagSet.Visit(func(f *flag.Flag) {
+		explicit[f.Name] = true
+	})
+
+	configFilename, configSource := cu.ConfigFilename(flagSet, explicit)
+	config, loadErr := cu.LoadConfig(configFilename, configSource != DefaultSource)
+	if loadErr != nil {
+		return nil, loadErr
+	}
+
+	sources := map[string]ConfigSource{}
+	var applyErr error
+	flagSet.VisitAll(func(f *flag.Flag) {
+		sources[f.Name] = DefaultSource
+		if explicit[f.Name] {
+			sources[f.Name] = FlagSource
+		} else if f.Name == ConfigFlagName {
+			sources[f.Name] = configSource
+			flagSet.Set(f.Name, configFilename)
+		} else if value, found := cu.LookupEnv(cu.EnvName(f.Name)); found {
+			sources[f.Name] = EnvSource
+			if err := flagSet.Set(f.Name, value); err != nil && applyErr == nil {
+				applyErr = fmt.Errorf("Invalid value [%s] of environment variable [%s]: %w", value, cu.EnvName(f.Name), err)
+			}
+		} else if value, found := config.Value(flagSet.Name(), f.Name); found {
+			sources[f.Name] = FileSource
+			if err := flagSet.Set(f.Name, value); err != nil && applyErr == nil {
+				applyErr = fmt.Errorf("Invalid value [%s] of [%s] in config file [%s]: %w", value, f.Name, configFilename, err)
+			}
+		}
+	})
+
+	return sources, applyErr
+}
+
+// PrintConfig prints the effective value, the ConfigSource, and the environment variable of each flag of the command, the values of
+// SecretFlags are redacted
+func (cu *ConfigUtil) PrintConfig(writer io.Writer, flagSet *flag.FlagSet, sources map[string]ConfigSource) {
+	tabWriter := tabwriter.NewWriter(writer, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(tabWriter, "FLAG\tVALUE\tSOURCE\tENVIRONMENT VARIABLE")
+	flagSet.VisitAll(func(f *flag.Flag) {
+		if _, deprecated := DeprecatedFlags[f.Name]; deprecated {
+			return
+		}
+
+		value := f.Value.String()
+		if SecretFlags[f.Name] && value != "" {
+			value = RedactedValue
+		}
+
+		fmt.Fprintf(tabWriter, "%s\t%q\t%s\t%s\n", f.Name, value, sources[f.Name], cu.EnvName(f.Name))
+	})
+	tabWriter.Flush()
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func NewTestConfigUtil(env map[string]string) *ConfigUtil {
+	return NewConfigUtil(func(key string) (string, bool) {
+		value, found := env[key]
+		return value, found
+	})
+}
+
+func CreateTestConfigFlagSet(configFilename string) *flag.FlagSet {
+	flagSet := flag.NewFlagSet("export", flag.ContinueOnError)
+	flagSet.String(ConfigFlagName, configFilename, "")
+	flagSet.String("outputFormat", "GraphML", "")
+	flagSet.String("outputFilename", "", "")
+	flagSet.Bool("yEdGraphics", false, "")
+	flagSet.Int("layoutIterations", 300, "")
+	flagSet.String("edamAuthToken", "", "")
+	return flagSet
+}
+
+func WriteTestConfigFile(content string) string {
+	testConfigFile := filepath.Join(os.TempDir(), "testConfig.yaml")
+	err := os.WriteFile(testConfigFile, []byte(content), 0600)
+	if err != nil {
+		panic(err)
+	}
+
+	return testConfigFile
+}
+
+func TestEnvName(t *testing.T) {
+	configUtil := NewTestConfigUtil(map[string]string{})
+	assert.Equal(t, "EVERNOTE_NOTE_GRAPH_EDAM_AUTH_TOKEN", configUtil.EnvName("edamAuthToken"))
+	assert.Equal(t, "EVERNOTE_NOTE_GRAPH_Y_ED_GRAPHICS", configUtil.EnvName("yEdGraphics"))
+	assert.Equal(t, "EVERNOTE_NOTE_GRAPH_NOTE_URL", configUtil.EnvName("noteURL"))
+	assert.Equal(t, "EVERNOTE_NOTE_GRAPH_PATH_GRAPH_ML_FILENAME", configUtil.EnvName("pathGraphMLFilename"))
+	assert.Equal(t, "EVERNOTE_NOTE_GRAPH_V", configUtil.EnvName("v"))
+}
+
+func TestLoadConfig(t *testing.T) {
+	testConfigFile := WriteTestConfigFile("outputFormat: DOT\nlayoutIterations: 50\nyEdGraphics: true\nexport:\n  outputFormat: GEXF\n")
+	defer os.Remove(testConfigFile)
+
+	configUtil := NewTestConfigUtil(map[string]string{})
+	config, err := configUtil.LoadConfig(testConfigFile, true)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"outputFormat": "DOT", "layoutIterations": "50", "yEdGraphics": "true"}, config.Values)
+
+	value, found := config.Value("export", "outputFormat")
+	assert.True(t, found)
+	assert.Equal(t, "GEXF", value)
+
+	value, found = config.Value("query", "outputFormat")
+	assert.True(t, found)
+	assert.Equal(t, "DOT", value)
+
+	_, found = config.Value("export", "outputFilename")
+	assert.False(t, found)
+
+	config, err = configUtil.LoadConfig(testConfigFile+".missing", false)
+	assert.Nil(t, err)
+	assert.Empty(t, config.Values)
+
+	_, err = configUtil.LoadConfig(testConfigFile+".missing", true)
+	assert.NotNil(t, err)
+
+	for _, content := range []string{"outputFormat: [DOT, GEXF]\n", "export:\n  outputFormat:\n", "outputFormat: DOT: GEXF\n", "export: [DOT]\n"} {
+		WriteTestConfigFile(content)
+		_, err = configUtil.LoadConfig(testConfigFile, true)
+		assert.NotNil(t, err, content)
+	}
+}
+
+func TestApplyConfig(t *testing.T) {
+	testConfigFile := WriteTestConfigFile("outputFormat: DOT\noutputFilename: file.dot\nlayoutIterations: 50\nyEdGraphics: true\n")
+	defer os.Remove(testConfigFile)
+
+	configUtil := NewTestConfigUtil(map[string]string{"EVERNOTE_NOTE_GRAPH_OUTPUT_FILENAME": "env.dot", "EVERNOTE_NOTE_GRAPH_LAYOUT_ITERATIONS": "100"})
+	flagSet := CreateTestConfigFlagSet(testConfigFile)
+	flagSet.Parse([]string{"-layoutIterations=200"})
+
+	sources, err := configUtil.ApplyConfig(flagSet)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]ConfigSource{ConfigFlagName: DefaultSource, "outputFormat": FileSource, "outputFilename": EnvSource, "yEdGraphics": FileSource, "layoutIterations": FlagSource, "edamAuthToken": DefaultSource}, sources)
+	assert.Equal(t, "DOT", flagSet.Lookup("outputFormat").Value.String())
+	assert.Equal(t, "env.dot", flagSet.Lookup("outputFilename").Value.String())
+	assert.Equal(t, "true", flagSet.Lookup("yEdGraphics").Value.String())
+	assert.Equal(t, "200", flagSet.Lookup("layoutIterations").Value.String())
+
+	flagSet = CreateTestConfigFlagSet("")
+	_, err = NewTestConfigUtil(map[string]string{"EVERNOTE_NOTE_GRAPH_Y_ED_GRAPHICS": "maybe"}).ApplyConfig(flagSet)
+	assert.Contains(t, err.Error(), "Invalid value [maybe] of environment variable [EVERNOTE_NOTE_GRAPH_Y_ED_GRAPHICS]")
+
+	WriteTestConfigFile("layoutIterations: many\n")
+	flagSet = CreateTestConfigFlagSet("")
+	_, err = NewTestConfigUtil(map[string]string{"EVERNOTE_NOTE_GRAPH_CONFIG": testConfigFile}).ApplyConfig(flagSet)
+	assert.Contains(t, err.Error(), "Invalid value [many] of [layoutIterations] in config file")
+}
+
+func TestPrintConfig(t *testing.T) {
+	configUtil := NewTestConfigUtil(map[string]string{"EVERNOTE_NOTE_GRAPH_EDAM_AUTH_TOKEN": "S=s1:U=1:secret"})
+	flagSet := CreateTestConfigFlagSet("")
+	flagSet.Parse([]string{})
+
+	sources, err := configUtil.ApplyConfig(flagSet)
+	assert.Nil(t, err)
+
+	var output bytes.Buffer
+	configUtil.PrintConfig(&output, flagSet, sources)
+	assert.NotContains(t, output.String(), "secret")
+	assert.Contains(t, output.String(), "FLAG               VALUE          SOURCE    ENVIRONMENT VARIABLE\n")
+	assert.Contains(t, output.String(), `edamAuthToken      "<redacted>"   env       EVERNOTE_NOTE_GRAPH_EDAM_AUTH_TOKEN`)
+	assert.Contains(t, output.String(), `outputFormat       "GraphML"      default   EVERNOTE_NOTE_GRAPH_OUTPUT_FORMAT`)
+}
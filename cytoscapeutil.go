@@ -0,0 +1,111 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/sirupsen/logrus"
+)
+
+// CytoscapeDocument is the Cytoscape.js JSON representation of a graph
+type CytoscapeDocument struct {
+	Elements CytoscapeElements `json:"elements"`
+}
+
+// CytoscapeElements contains the nodes and edges of a Cytoscape.js graph
+type CytoscapeElements struct {
+	Nodes []CytoscapeElement `json:"nodes"`
+	Edges []CytoscapeElement `json:"edges"`
+}
+
+// CytoscapeElement is a node or edge of a Cytoscape.js graph with all attributes in the data object
+type CytoscapeElement struct {
+	Data map[string]interface{} `json:"data"`
+}
+
+// CytoscapeUtil converts a NoteGraph to Cytoscape.js elements JSON
+type CytoscapeUtil struct {
+	ExportAttributes
+}
+
+// NewCytoscapeUtil creates a new instance of CytoscapeUtil
+func NewCytoscapeUtil() *CytoscapeUtil {
+	return &CytoscapeUtil{}
+}
+
+// ExportNoteGraph converts the NoteGraph into Cytoscape.js elements JSON and writes the JSON to the writer
+func (cu *CytoscapeUtil) ExportNoteGraph(noteGraph *NoteGraph, allNotes bool, writer io.Writer) error {
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	encodeErr := encoder.Encode(cu.ConvertNoteGraph(noteGraph, allNotes))
+	if encodeErr != nil {
+		return fmt.Errorf("Failed to encode Cytoscape.js JSON: %w", encodeErr)
+	}
+
+	return nil
+}
+
+// ConvertNoteGraph converts the NoteGraph into a CytoscapeDocument
+func (cu *CytoscapeUtil) ConvertNoteGraph(noteGraph *NoteGraph, allNotes bool) *CytoscapeDocument {
+	notes := *noteGraph.GetLinkedNotes()
+	if allNotes {
+		notes = *noteGraph.GetNotes()
+	}
+
+	noteLinks := *noteGraph.GetValidNoteLinks()
+
+	nodes := cu.CreateNodes(notes)
+	edges := cu.CreateEdges(noteLinks)
+
+	logrus.Infof("Converting NoteGraph with [%d|%d] Notes|nodes and [%d|%d] NoteLinks|edges to Cytoscape.js JSON", len(notes), len(nodes), len(noteLinks), len(edges))
+
+	return &CytoscapeDocument{Elements: CytoscapeElements{Nodes: nodes, Edges: edges}}
+}
+
+// CreateNodes creates Cytoscape.js nodes from the Notes
+func (cu *CytoscapeUtil) CreateNodes(notes []Note) []CytoscapeElement {
+	nodes := []CytoscapeElement{}
+	for _, note := range notes {
+		data := map[string]interface{}{
+			"id":                note.GUID,
+			NodeLabelName:       note.Title,
+			NodeDescriptionName: note.Description,
+			NodeURLName:         note.URL.String(),
+			"urlType":           note.URLType.String()}
+		for _, noteAttribute := range cu.NoteAttributes {
+			if value, found := noteAttribute.Values[note.GUID]; found {
+				data[noteAttribute.Name] = TypedAttributeValue(noteAttribute.Type, value)
+			}
+		}
+
+		nodes = append(nodes, CytoscapeElement{Data: data})
+	}
+
+	return nodes
+}
+
+// CreateEdges creates Cytoscape.js edges from the NoteLinks
+func (cu *CytoscapeUtil) CreateEdges(noteLinks []NoteLink) []CytoscapeElement {
+	edges := []CytoscapeElement{}
+	for index, edgeNoteLink := range CanonicalNoteLinks(noteLinks) {
+		edgeID, noteLink := edgeNoteLink.EdgeID, edgeNoteLink.NoteLink
+		data := map[string]interface{}{
+			"id":                "edge-" + strconv.Itoa(index),
+			"source":            noteLink.SourceNoteGUID,
+			"target":            noteLink.TargetNoteGUID,
+			EdgeLabelName:       noteLink.Text,
+			EdgeDescriptionName: noteLink.Text,
+			"urlType":           noteLink.URLType.String()}
+		for _, noteLinkAttribute := range cu.NoteLinkAttributes {
+			if value, found := noteLinkAttribute.Value(edgeID, noteLink); found {
+				data[noteLinkAttribute.Name] = TypedAttributeValue(noteLinkAttribute.Type, value)
+			}
+		}
+
+		edges = append(edges, CytoscapeElement{Data: data})
+	}
+
+	return edges
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConvertNoteGraphToCytoscape(t *testing.T) {
+	cytoscapeUtil := NewCytoscapeUtil()
+	cytoscapeUtil.AddNoteAttributes(NoteAttribute{ID: NodeCommunityID, Name: NodeCommunityName, Type: "int", Values: map[string]string{"A": "1"}})
+	cytoscapeDocument := cytoscapeUtil.ConvertNoteGraph(CreateDOTTestNoteGraph(), false)
+
+	assert.Len(t, cytoscapeDocument.Elements.Nodes, 4)
+	assert.Len(t, cytoscapeDocument.Elements.Edges, 3)
+
+	for _, node := range cytoscapeDocument.Elements.Nodes {
+		if node.Data["id"] == "A" {
+			assert.Equal(t, "Title \"A\"", node.Data[NodeLabelName])
+			assert.Equal(t, "DescriptionA", node.Data[NodeDescriptionName])
+			assert.Equal(t, "https://www.evernote.com/shard/s1/nl/1/A/", node.Data[NodeURLName])
+			assert.Equal(t, int64(1), node.Data[NodeCommunityName])
+		}
+	}
+
+	assert.Equal(t, map[string]interface{}{"id": "edge-0", "source": "A", "target": "B", "label": "LinkAB", "description": "LinkAB", "urlType": "AppLink"}, cytoscapeDocument.Elements.Edges[0].Data)
+}
+
+func TestExportNoteGraphToCytoscape(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	err := NewCytoscapeUtil().ExportNoteGraph(CreateDOTTestNoteGraph(), true, buffer)
+	if err != nil {
+		panic(err)
+	}
+
+	cytoscapeDocument := &CytoscapeDocument{}
+	err = json.Unmarshal(buffer.Bytes(), cytoscapeDocument)
+	if err != nil {
+		panic(err)
+	}
+
+	assert.Len(t, cytoscapeDocument.Elements.Nodes, 5)
+	assert.Len(t, cytoscapeDocument.Elements.Edges, 3)
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// DOTUtil converts a NoteGraph to a Graphviz DOT digraph with clickable nodes and optional clusters per notebook
+type DOTUtil struct {
+	ExportAttributes
+	ClusterNotebooks bool
+}
+
+// NewDOTUtil creates a new instance of DOTUtil
+func NewDOTUtil(clusterNotebooks bool) *DOTUtil {
+	return &DOTUtil{ClusterNotebooks: clusterNotebooks}
+}
+
+// ExportNoteGraph converts the NoteGraph into a DOT digraph and writes the DOT digraph to the writer
+func (du *DOTUtil) ExportNoteGraph(noteGraph *NoteGraph, allNotes bool, writer io.Writer) error {
+	notes := *noteGraph.GetLinkedNotes()
+	if allNotes {
+		notes = *noteGraph.GetNotes()
+	}
+
+	sort.Slice(notes, func(i, j int) bool {
+		return notes[i].GUID < notes[j].GUID
+	})
+
+	noteLinks := *noteGraph.GetValidNoteLinks()
+
+	logrus.Infof("Converting NoteGraph with [%d] Notes and [%d] NoteLinks to DOT", len(notes), len(noteLinks))
+
+	bufferedWriter := bufio.NewWriter(writer)
+	fmt.Fprintf(bufferedWriter, "digraph %s {\n", du.Quote(NoteGraphID))
+	if du.ClusterNotebooks {
+		du.WriteNotebookClusters(bufferedWriter, notes)
+	} else {
+		for _, note := range notes {
+			fmt.Fprintf(bufferedWriter, "  %s\n", du.CreateNode(note))
+		}
+	}
+
+	for _, edgeNoteLink := range CanonicalNoteLinks(noteLinks) {
+		fmt.Fprintf(bufferedWriter, "  %s\n", du.CreateEdge(edgeNoteLink.EdgeID, edgeNoteLink.NoteLink))
+	}
+
+	fmt.Fprintf(bufferedWriter, "}\n")
+	return bufferedWriter.Flush()
+}
+
+// WriteNotebookClusters writes one subgraph cluster with all Notes of each notebook ordered by notebook name, Notes with unknown
+// notebook are written outside of any cluster
+func (du *DOTUtil) WriteNotebookClusters(writer io.Writer, notes []Note) {
+	notebookNotes := map[string][]Note{}
+	for _, note := range notes {
+		notebookNotes[note.Notebook] = append(notebookNotes[note.Notebook], note)
+	}
+
+	notebooks := []string{}
+	for notebook := range notebookNotes {
+		if notebook != "" {
+			notebooks = append(notebooks, notebook)
+		}
+	}
+
+	sort.Strings(notebooks)
+	for index, notebook := range notebooks {
+		fmt.Fprintf(writer, "  subgraph %s {\n", du.Quote(fmt.Sprintf("cluster_%d", index)))
+		fmt.Fprintf(writer, "    label=%s\n", du.Quote(notebook))
+		for _, note := range notebookNotes[notebook] {
+			fmt.Fprintf(writer, "    %s\n", du.CreateNode(note))
+		}
+		fmt.Fprintf(writer, "  }\n")
+	}
+
+	for _, note := range notebookNotes[""] {
+		fmt.Fprintf(writer, "  %s\n", du.CreateNode(note))
+	}
+}
+
+// CreateNode creates a DOT node statement from the Note with label, tooltip, and URL/href attributes
+func (du *DOTUtil) CreateNode(note Note) string {
+	attributes := []string{
+		du.CreateAttribute("label", note.Title),
+		du.CreateAttribute("tooltip", note.Description),
+		du.CreateAttribute("URL", note.URL.String()),
+		du.CreateAttribute("href", note.URL.String())}
+	for _, noteAttribute := range du.NoteAttributes {
+		if value, found := noteAttribute.Values[note.GUID]; found {
+			attributes = append(attributes, du.CreateAttribute(noteAttribute.Name, value))
+		}
+	}
+
+	return fmt.Sprintf("%s [%s]", du.Quote(note.GUID), strings.Join(attributes, ", "))
+}
+
+// CreateEdge creates a DOT edge statement from the NoteLink with the NoteLink text as label and the values of the NoteLinkAttributes
+func (du *DOTUtil) CreateEdge(edgeID string, noteLink NoteLink) string {
+	attributes := []string{du.CreateAttribute("label", noteLink.Text)}
+	for _, noteLinkAttribute := range du.NoteLinkAttributes {
+		if value, found := noteLinkAttribute.Value(edgeID, noteLink); found {
+			attributes = append(attributes, du.CreateAttribute(noteLinkAttribute.Name, value))
+		}
+	}
+
+	return fmt.Sprintf("%s -> %s [%s]", du.Quote(noteLink.SourceNoteGUID), du.Quote(noteLink.TargetNoteGUID), strings.Join(attributes, ", "))
+}
+
+// CreateAttribute creates a DOT attribute assignment with quoted value
+func (du *DOTUtil) CreateAttribute(name, value string) string {
+	return du.Quote(name) + "=" + du.Quote(value)
+}
+
+// Quote creates a DOT double-quoted string escaping double quotes, backslashes, and line breaks
+func (du *DOTUtil) Quote(value string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\r", "", "\n", `\n`)
+	return `"` + replacer.Replace(value) + `"`
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"bytes"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func CreateDOTTestNoteGraph() *NoteGraph {
+	noteURL, err := url.Parse("https://www.evernote.com/shard/s1/nl/1/A/")
+	if err != nil {
+		panic(err)
+	}
+
+	// Notes A and B in notebook Work, Note C in notebook Home, Note D without notebook, Note E is not linked
+	noteGraph := NewNoteGraph()
+	noteGraph.Add(Note{GUID: "A", Title: "Title \"A\"", Description: "DescriptionA", URL: *noteURL, Notebook: "Work"}, []NoteLink{{SourceNoteGUID: "A", TargetNoteGUID: "B", Text: "LinkAB"}})
+	noteGraph.Add(Note{GUID: "B", Title: "TitleB", Notebook: "Work"}, []NoteLink{{SourceNoteGUID: "B", TargetNoteGUID: "C", Text: "LinkBC"}})
+	noteGraph.Add(Note{GUID: "C", Title: "TitleC", Notebook: "Home"}, []NoteLink{{SourceNoteGUID: "C", TargetNoteGUID: "D", Text: "LinkCD"}})
+	noteGraph.Add(Note{GUID: "D", Title: "TitleD"}, []NoteLink{})
+	noteGraph.Add(Note{GUID: "E", Title: "TitleE", Notebook: "Home"}, []NoteLink{})
+	return noteGraph
+}
+
+func TestExportNoteGraphToDOT(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	err := NewDOTUtil(false).ExportNoteGraph(CreateDOTTestNoteGraph(), false, buffer)
+	if err != nil {
+		panic(err)
+	}
+
+	expectedDOT := `digraph "NoteGraph" {
+  "A" ["label"="Title \"A\"", "tooltip"="DescriptionA", "URL"="https://www.evernote.com/shard/s1/nl/1/A/", "href"="https://www.evernote.com/shard/s1/nl/1/A/"]
+  "B" ["label"="TitleB", "tooltip"="", "URL"="", "href"=""]
+  "C" ["label"="TitleC", "tooltip"="", "URL"="", "href"=""]
+  "D" ["label"="TitleD", "tooltip"="", "URL"="", "href"=""]
+  "A" -> "B" ["label"="LinkAB"]
+  "B" -> "C" ["label"="LinkBC"]
+  "C" -> "D" ["label"="LinkCD"]
+}
+`
+	assert.Equal(t, expectedDOT, buffer.String())
+}
+
+func TestExportNoteGraphToDOTWithNotebookClusters(t *testing.T) {
+	dotUtil := NewDOTUtil(true)
+	dotUtil.AddNoteLinkAttributes(NoteLinkAttribute{ID: EdgePathID, Name: EdgePathName, Type: "boolean", Values: map[NoteLinkKey]string{{SourceNoteGUID: "A", TargetNoteGUID: "B"}: "true"}})
+
+	buffer := &bytes.Buffer{}
+	err := dotUtil.ExportNoteGraph(CreateDOTTestNoteGraph(), true, buffer)
+	if err != nil {
+		panic(err)
+	}
+
+	expectedDOT := `digraph "NoteGraph" {
+  subgraph "cluster_0" {
+    label="Home"
+    "C" ["label"="TitleC", "tooltip"="", "URL"="", "href"=""]
+    "E" ["label"="TitleE", "tooltip"="", "URL"="", "href"=""]
+  }
+  subgraph "cluster_1" {
+    label="Work"
+    "A" ["label"="Title \"A\"", "tooltip"="DescriptionA", "URL"="https://www.evernote.com/shard/s1/nl/1/A/", "href"="https://www.evernote.com/shard/s1/nl/1/A/"]
+    "B" ["label"="TitleB", "tooltip"="", "URL"="", "href"=""]
+  }
+  "D" ["label"="TitleD", "tooltip"="", "URL"="", "href"=""]
+  "A" -> "B" ["label"="LinkAB", "path"="true"]
+  "B" -> "C" ["label"="LinkBC"]
+  "C" -> "D" ["label"="LinkCD"]
+}
+`
+	assert.Equal(t, expectedDOT, buffer.String())
+}
+
+func TestQuoteDOT(t *testing.T) {
+	assert.Equal(t, `"a\\b \"c\"\nd"`, NewDOTUtil(false).Quote("a\\b \"c\"\r\nd"))
+}
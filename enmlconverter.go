@@ -0,0 +1,336 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/antchfx/htmlquery"
+	"golang.org/x/net/html"
+)
+
+// LinkResolver returns the Markdown for the link with the href and text, or an empty string to use a regular Markdown link
+type LinkResolver func(href, text string) string
+
+// ENMLConverter converts note content (ENML) to Markdown with headings, lists, checkboxes, tables, bold, italic, strikethrough,
+// code, quotes, and links
+type ENMLConverter struct {
+	LinkResolver LinkResolver
+}
+
+// NewENMLConverter creates a new instance of ENMLConverter, linkResolver may be nil
+func NewENMLConverter(linkResolver LinkResolver) *ENMLConverter {
+	return &ENMLConverter{LinkResolver: linkResolver}
+}
+
+var whitespaceRegexp = regexp.MustCompile(`\s+`)
+var blankLinesRegexp = regexp.MustCompile(`\n{3,}`)
+
+// markdownWriter writes Markdown line by line prefixing each line with the indentation of lists and quotes
+type markdownWriter struct {
+	builder     strings.Builder
+	indent      string
+	lineStart   bool // nothing but indentation or list marker written on the current line
+	freshLine   bool // indentation not yet written on the current line
+	afterMarker bool // list marker written on the current line
+}
+
+func newMarkdownWriter() *markdownWriter {
+	return &markdownWriter{lineStart: true, freshLine: true}
+}
+
+func (mw *markdownWriter) write(text string) {
+	if text == "" {
+		return
+	}
+
+	if mw.freshLine {
+		mw.builder.WriteString(mw.indent)
+		mw.freshLine = false
+	}
+
+	mw.builder.WriteString(text)
+	mw.lineStart = false
+	mw.afterMarker = false
+}
+
+func (mw *markdownWriter) newline() {
+	mw.builder.WriteString("\n")
+	mw.lineStart = true
+	mw.freshLine = true
+	mw.afterMarker = false
+}
+
+func (mw *markdownWriter) ensureLine() {
+	if !mw.lineStart {
+		mw.newline()
+	}
+}
+
+func (mw *markdownWriter) ensureBlankLine() {
+	mw.ensureLine()
+	if mw.builder.Len() > 0 && !strings.HasSuffix(mw.builder.String(), "\n\n") {
+		mw.newline()
+	}
+}
+
+func (mw *markdownWriter) marker(marker string) {
+	mw.ensureLine()
+	mw.write(marker)
+	mw.lineStart = true
+	mw.afterMarker = true
+}
+
+// ConvertENML converts the note content (ENML) to Markdown
+func (ec *ENMLConverter) ConvertENML(content string) (string, error) {
+	document, err := htmlquery.Parse(strings.NewReader(content))
+	if err != nil {
+		return "", fmt.Errorf("Failed to parse note content: %w", err)
+	}
+
+	writer := newMarkdownWriter()
+	ec.ConvertNode(writer, document)
+	markdown := blankLinesRegexp.ReplaceAllString(writer.builder.String(), "\n\n")
+	return strings.TrimSpace(markdown) + "\n", nil
+}
+
+// ConvertNode converts the HTML node and its children to Markdown
+func (ec *ENMLConverter) ConvertNode(writer *markdownWriter, node *html.Node) {
+	if node.Type == html.TextNode {
+		text := whitespaceRegexp.ReplaceAllString(node.Data, " ")
+		if writer.lineStart {
+			text = strings.TrimLeft(text, " ")
+		}
+		writer.write(text)
+		return
+	}
+
+	if node.Type != html.ElementNode {
+		ec.ConvertChildren(writer, node)
+		return
+	}
+
+	style := htmlquery.SelectAttr(node, "style")
+	switch node.Data {
+	case "head", "title", "script", "style":
+	case "h1", "h2", "h3", "h4", "h5", "h6":
+		level, _ := strconv.Atoi(node.Data[1:])
+		writer.ensureBlankLine()
+		writer.write(strings.Repeat("#", level) + " " + ec.InlineText(node))
+		writer.ensureBlankLine()
+	case "p":
+		writer.ensureBlankLine()
+		ec.ConvertChildren(writer, node)
+		writer.ensureBlankLine()
+	case "div":
+		if strings.Contains(style, "-en-codeblock") {
+			ec.ConvertCodeBlock(writer, node)
+			return
+		}
+		writer.ensureLine()
+		ec.ConvertChildren(writer, node)
+		writer.ensureLine()
+	case "br":
+		writer.newline()
+	case "hr":
+		writer.ensureBlankLine()
+		writer.write("---")
+		writer.ensureBlankLine()
+	case "b", "strong":
+		ec.ConvertEmphasis(writer, node, "**")
+	case "i", "em":
+		ec.ConvertEmphasis(writer, node, "*")
+	case "s", "strike", "del":
+		ec.ConvertEmphasis(writer, node, "~~")
+	case "code":
+		writer.write("`" + ec.PlainText(node) + "`")
+	case "pre":
+		ec.ConvertCodeBlock(writer, node)
+	case "blockquote":
+		writer.ensureBlankLine()
+		indent := writer.indent
+		writer.indent = indent + "> "
+		ec.ConvertChildren(writer, node)
+		writer.ensureLine()
+		writer.indent = indent
+		writer.ensureBlankLine()
+	case "ul", "ol":
+		ec.ConvertList(writer, node)
+	case "table":
+		ec.ConvertTable(writer, node)
+	case "a":
+		ec.ConvertLink(writer, node)
+	case "en-todo":
+		checkbox := "[ ] "
+		if htmlquery.SelectAttr(node, "checked") == "true" {
+			checkbox = "[x] "
+		}
+		if writer.lineStart && !writer.afterMarker {
+			checkbox = "- " + checkbox
+		}
+		writer.write(checkbox)
+		ec.ConvertChildren(writer, node)
+	case "en-media", "img":
+		writer.write("*[attachment " + htmlquery.SelectAttr(node, "type") + "]*")
+	case "en-crypt":
+		writer.write("*[encrypted content]*")
+	case "span":
+		if strings.Contains(style, "font-weight: bold") {
+			ec.ConvertEmphasis(writer, node, "**")
+		} else if strings.Contains(style, "font-style: italic") {
+			ec.ConvertEmphasis(writer, node, "*")
+		} else {
+			ec.ConvertChildren(writer, node)
+		}
+	default:
+		ec.ConvertChildren(writer, node)
+	}
+}
+
+// ConvertChildren converts all children of the HTML node to Markdown
+func (ec *ENMLConverter) ConvertChildren(writer *markdownWriter, node *html.Node) {
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		ec.ConvertNode(writer, child)
+	}
+}
+
+// ConvertEmphasis converts the HTML node to Markdown enclosed in the delimiter, empty nodes are omitted
+func (ec *ENMLConverter) ConvertEmphasis(writer *markdownWriter, node *html.Node, delimiter string) {
+	text := ec.InlineText(node)
+	if text != "" {
+		writer.write(delimiter + text + delimiter)
+	}
+}
+
+// ConvertList converts an ordered or unordered list with one list item per line and indented nested lists
+func (ec *ENMLConverter) ConvertList(writer *markdownWriter, node *html.Node) {
+	writer.ensureLine()
+	number := 1
+	indent := writer.indent
+	itemIndent := indent + "  "
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type != html.ElementNode {
+			continue
+		}
+
+		// Evernote nests lists as direct children of the parent list, these are indented under the previous list item
+		if child.Data == "ul" || child.Data == "ol" {
+			writer.indent = itemIndent
+			ec.ConvertList(writer, child)
+			writer.indent = indent
+			continue
+		}
+
+		if child.Data != "li" {
+			continue
+		}
+
+		marker := "- "
+		if node.Data == "ol" {
+			marker = strconv.Itoa(number) + ". "
+			number++
+		}
+
+		writer.marker(marker)
+		itemIndent = indent + strings.Repeat(" ", len(marker))
+		writer.indent = itemIndent
+		ec.ConvertChildren(writer, child)
+		writer.indent = indent
+		writer.ensureLine()
+	}
+}
+
+// ConvertTable converts a table to a Markdown table with the first row as header row
+func (ec *ENMLConverter) ConvertTable(writer *markdownWriter, node *html.Node) {
+	rows := [][]string{}
+	columns := 0
+	for _, tr := range htmlquery.Find(node, ".//tr") {
+		row := []string{}
+		for cell := tr.FirstChild; cell != nil; cell = cell.NextSibling {
+			if cell.Type == html.ElementNode && (cell.Data == "td" || cell.Data == "th") {
+				row = append(row, strings.ReplaceAll(ec.InlineText(cell), "|", `\|`))
+			}
+		}
+
+		if len(row) > columns {
+			columns = len(row)
+		}
+		rows = append(rows, row)
+	}
+
+	if len(rows) == 0 {
+		return
+	}
+
+	writer.ensureBlankLine()
+	for index, row := range rows {
+		for len(row) < columns {
+			row = append(row, "")
+		}
+
+		writer.write("| " + strings.Join(row, " | ") + " |")
+		writer.newline()
+		if index == 0 {
+			writer.write(strings.TrimSpace(strings.Repeat("| --- ", columns)) + " |")
+			writer.newline()
+		}
+	}
+	writer.ensureBlankLine()
+}
+
+// ConvertCodeBlock converts a code block to a fenced Markdown code block
+func (ec *ENMLConverter) ConvertCodeBlock(writer *markdownWriter, node *html.Node) {
+	writer.ensureBlankLine()
+	writer.write("```")
+	writer.newline()
+	for _, line := range strings.Split(strings.TrimRight(ec.PlainText(node), "\n"), "\n") {
+		writer.write(line)
+		writer.newline()
+	}
+	writer.write("```")
+	writer.ensureBlankLine()
+}
+
+// ConvertLink converts a link with the LinkResolver or to a regular Markdown link
+func (ec *ENMLConverter) ConvertLink(writer *markdownWriter, node *html.Node) {
+	href := htmlquery.SelectAttr(node, "href")
+	text := ec.InlineText(node)
+	if ec.LinkResolver != nil {
+		if markdown := ec.LinkResolver(href, text); markdown != "" {
+			writer.write(markdown)
+			return
+		}
+	}
+
+	if text == "" {
+		text = href
+	}
+
+	writer.write("[" + text + "](" + href + ")")
+}
+
+// InlineText converts the children of the HTML node to single line Markdown
+func (ec *ENMLConverter) InlineText(node *html.Node) string {
+	writer := newMarkdownWriter()
+	ec.ConvertChildren(writer, node)
+	return strings.TrimSpace(whitespaceRegexp.ReplaceAllString(writer.builder.String(), " "))
+}
+
+// PlainText returns the text of the HTML node with line breaks for div, p, and br elements
+func (ec *ENMLConverter) PlainText(node *html.Node) string {
+	if node.Type == html.TextNode {
+		return node.Data
+	}
+
+	text := ""
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		text += ec.PlainText(child)
+	}
+
+	if node.Type == html.ElementNode && (node.Data == "div" || node.Data == "p" || node.Data == "br") && !strings.HasSuffix(text, "\n") {
+		text += "\n"
+	}
+
+	return text
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func ConvertTestENML(body string, linkResolver LinkResolver) string {
+	markdown, err := NewENMLConverter(linkResolver).ConvertENML(`<?xml version="1.0" encoding="UTF-8"?><!DOCTYPE en-note SYSTEM "http://xml.evernote.com/pub/enml2.dtd"><en-note>` + body + `</en-note>`)
+	if err != nil {
+		panic(err)
+	}
+
+	return markdown
+}
+
+func TestConvertENMLHeadingsAndEmphasis(t *testing.T) {
+	markdown := ConvertTestENML(`<h1>Title</h1><div>Some <b>bold</b>, <i>italic</i>, <s>old</s>, and <code>code</code> text</div><div><span style="font-weight: bold;">Span</span></div>`, nil)
+
+	assert.Equal(t, "# Title\n\nSome **bold**, *italic*, ~~old~~, and `code` text\n**Span**\n", markdown)
+}
+
+func TestConvertENMLLists(t *testing.T) {
+	markdown := ConvertTestENML(`<ul><li>One</li><li>Two<ol><li>Nested</li></ol></li></ul>`, nil)
+
+	assert.Equal(t, "- One\n- Two\n  1. Nested\n", markdown)
+}
+
+func TestConvertENMLListsNestedAsListChildren(t *testing.T) {
+	markdown := ConvertTestENML(`<ul><li>a</li><ul><li>b</li><ol><li>c</li></ol></ul><li>d</li></ul>`, nil)
+
+	assert.Equal(t, "- a\n  - b\n    1. c\n- d\n", markdown)
+}
+
+func TestConvertENMLCheckboxes(t *testing.T) {
+	markdown := ConvertTestENML(`<div><en-todo checked="true"/>Done</div><div><en-todo checked="false"/>Open</div>`, nil)
+
+	assert.Equal(t, "- [x] Done\n- [ ] Open\n", markdown)
+}
+
+func TestConvertENMLTable(t *testing.T) {
+	markdown := ConvertTestENML(`<table><tr><td>A</td><td>B</td></tr><tr><td>1</td><td>2|3</td></tr></table>`, nil)
+
+	assert.Equal(t, "| A | B |\n| --- | --- |\n| 1 | 2\\|3 |\n", markdown)
+}
+
+func TestConvertENMLCodeBlockAndQuote(t *testing.T) {
+	markdown := ConvertTestENML(`<div style="-en-codeblock:true;"><div>x := 1</div><div>y := 2</div></div><blockquote><div>Quoted</div></blockquote>`, nil)
+
+	assert.Equal(t, "```\nx := 1\ny := 2\n```\n\n> Quoted\n", markdown)
+}
+
+func TestConvertENMLLinks(t *testing.T) {
+	linkResolver := func(href, text string) string {
+		if href == "evernote:///view/1/s1/B/B/" {
+			return "[[" + text + "]]"
+		}
+		return ""
+	}
+
+	markdown := ConvertTestENML(`<div><a href="evernote:///view/1/s1/B/B/">TitleB</a> and <a href="https://example.com">Example</a></div>`, linkResolver)
+
+	assert.Equal(t, "[[TitleB]] and [Example](https://example.com)\n", markdown)
+}
+
+func TestConvertENMLMedia(t *testing.T) {
+	markdown := ConvertTestENML(`<div><en-media type="image/png" hash="abc"/></div>`, nil)
+
+	assert.Equal(t, "*[attachment image/png]*\n", markdown)
+}
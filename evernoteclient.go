@@ -37,6 +37,7 @@ type EvernoteClient struct {
 	Sandbox         bool
 	UserStoreClient *edam.UserStoreClient
 	NoteStoreClient *edam.NoteStoreClient
+	Progress        *FetchProgress // optional progress display counting retries
 }
 
 // IEvernoteClient is an interface that exposes all EvernoteClient functions required to contstruct a NoteGraph
@@ -46,6 +47,8 @@ type IEvernoteClient interface {
 	GetUserStoreURL() string
 	FindAllNotesMetadata(offset int32, maxNotes int32) (*edam.NotesMetadataList, error)
 	GetNoteWithContent(guid edam.GUID) (*edam.Note, error)
+	ListNotebooks() ([]*edam.Notebook, error)
+	ListTags() ([]*edam.Tag, error)
 }
 
 // NewEvernoteClient creates a new instance of EvernoteClient
@@ -92,20 +95,13 @@ func (ec *EvernoteClient) GetUser() (*edam.User, error) {
 		return nil, fmt.Errorf("Failed to create UserStoreClient: %w", err)
 	}
 
-	retriable := retry.New()
 	context, cancel := context.WithTimeout(context.Background(), Timeout)
 	defer cancel()
 
 	user := &edam.User{}
-	retriableErr := retriable.EnsureN(context, Retries, func() error {
-		logrus.Debugf("Retrieving user information from Evernote API endpoint [%s]", ec.GetHost())
+	retriableErr := ec.Ensure(context, "Retrieving user information from Evernote API", logrus.Fields{"host": ec.GetHost()}, func() error {
 		user, err = userStoreClient.GetUser(context, ec.AuthToken)
-		if err != nil {
-			logrus.Warnf("Retrieving user information from Evernote API endpoint [%s] failed with error [%s] - retrying [%d] times", ec.GetHost(), err, Retries)
-			return retry.Retriable(err)
-		}
-
-		return nil
+		return err
 	})
 
 	if retriableErr != nil {
@@ -115,6 +111,41 @@ func (ec *EvernoteClient) GetUser() (*edam.User, error) {
 	return user, nil
 }
 
+// Ensure calls the Evernote API up to Retries times until the call succeeds, only calls failing with a NetworkError are retried,
+// the error of the last call is returned as NoteGraphError, every attempt is logged with the fields and the attempt number
+func (ec *EvernoteClient) Ensure(context context.Context, description string, fields logrus.Fields, call func() error) error {
+	attempt := 0
+	var callErr *NoteGraphError
+	retriableErr := retry.New().EnsureN(context, Retries, func() error {
+		attempt++
+		logEntry := logrus.WithFields(fields).WithField("attempt", attempt)
+		logEntry.Debug(description)
+		err := call()
+		if err == nil {
+			return nil
+		}
+
+		callErr = ClassifyAPIError(err)
+		if callErr.Kind != NetworkError {
+			return callErr
+		}
+
+		logEntry.WithError(err).WithField("retries", Retries).Warn(description + " failed - retrying")
+		ec.Progress.Retry()
+		return retry.Retriable(callErr)
+	})
+
+	if retriableErr != nil && callErr != nil {
+		return callErr
+	}
+
+	if retriableErr != nil {
+		return ClassifyAPIError(retriableErr)
+	}
+
+	return nil
+}
+
 // GetNoteStoreClient returns the Evernote NoteStoreClient
 func (ec *EvernoteClient) GetNoteStoreClient() (*edam.NoteStoreClient, error) {
 	if ec.NoteStoreClient != nil {
@@ -126,20 +157,13 @@ func (ec *EvernoteClient) GetNoteStoreClient() (*edam.NoteStoreClient, error) {
 		return nil, fmt.Errorf("Failed to create UserStoreClient: %w", err)
 	}
 
-	retriable := retry.New()
 	context, cancel := context.WithTimeout(context.Background(), Timeout)
 	defer cancel()
 
 	userUrls := &edam.UserUrls{}
-	retriableErr := retriable.EnsureN(context, Retries, func() error {
-		logrus.Tracef("Retrieving user URLs from Evernote API endpoint [%s]", ec.GetHost())
+	retriableErr := ec.Ensure(context, "Retrieving user URLs from Evernote API", logrus.Fields{"host": ec.GetHost()}, func() error {
 		userUrls, err = userStoreClient.GetUserUrls(context, ec.AuthToken)
-		if err != nil {
-			logrus.Warnf("Retrieving user URLs from Evernote API endpoint [%s] failed with error [%s] - retrying [%d] times", ec.GetHost(), err, Retries)
-			return retry.Retriable(err)
-		}
-
-		return nil
+		return err
 	})
 
 	if retriableErr != nil {
@@ -163,23 +187,16 @@ func (ec *EvernoteClient) FindAllNotesMetadata(offset int32, maxNotes int32) (*e
 		return nil, fmt.Errorf("Failed to create NoteStoreClient: %w", err)
 	}
 
-	retriable := retry.New()
 	context, cancel := context.WithTimeout(context.Background(), Timeout)
 	defer cancel()
 
 	filter := &edam.NoteFilter{Order: &NoteSortOrder, Ascending: &no}
-	resultSpec := &edam.NotesMetadataResultSpec{IncludeTitle: &yes, IncludeAttributes: &yes}
+	resultSpec := &edam.NotesMetadataResultSpec{IncludeTitle: &yes, IncludeCreated: &yes, IncludeUpdated: &yes, IncludeAttributes: &yes}
 
 	notesMetadataList := &edam.NotesMetadataList{}
-	retriableErr := retriable.EnsureN(context, Retries, func() error {
-		logrus.Debugf("Retrieving metadata for notes from offset [%d] with page size [%d] from Evernote API endpoint [%s]", offset, maxNotes, ec.GetHost())
+	retriableErr := ec.Ensure(context, "Retrieving metadata of notes from Evernote API", logrus.Fields{"host": ec.GetHost(), "offset": offset, "page_size": maxNotes}, func() error {
 		notesMetadataList, err = noteStoreClient.FindNotesMetadata(context, ec.AuthToken, filter, offset, maxNotes, resultSpec)
-		if err != nil {
-			logrus.Warnf("Retrieving metadata for notes from offset [%d] with page size [%d] from Evernote API endpoint [%s] failed with error [%s] - retrying [%d] times", offset, maxNotes, ec.GetHost(), err, Retries)
-			return retry.Retriable(err)
-		}
-
-		return nil
+		return err
 	})
 
 	if retriableErr != nil {
@@ -196,22 +213,15 @@ func (ec *EvernoteClient) GetNoteWithContent(guid edam.GUID) (*edam.Note, error)
 		return nil, fmt.Errorf("Failed to create NoteStoreClient: %w", err)
 	}
 
-	retriable := retry.New()
 	context, cancel := context.WithTimeout(context.Background(), Timeout)
 	defer cancel()
 
 	resultSpec := &edam.NoteResultSpec{IncludeContent: &yes}
 
 	note := &edam.Note{}
-	retriableErr := retriable.EnsureN(context, Retries, func() error {
-		logrus.Debugf("Retrieving note with GUID [%s] from Evernote API endpoint [%s]", guid, ec.GetHost())
+	retriableErr := ec.Ensure(context, "Retrieving note from Evernote API", logrus.Fields{"host": ec.GetHost(), "note_guid": guid}, func() error {
 		note, err = noteStoreClient.GetNoteWithResultSpec(context, ec.AuthToken, guid, resultSpec)
-		if err != nil {
-			logrus.Warnf("Retrieving note with GUID [%s] from Evernote API endpoint [%s] failed with error [%s] - retrying [%d] times", guid, ec.GetHost(), err, Retries)
-			return retry.Retriable(err)
-		}
-
-		return nil
+		return err
 	})
 
 	if retriableErr != nil {
@@ -220,3 +230,49 @@ func (ec *EvernoteClient) GetNoteWithContent(guid edam.GUID) (*edam.Note, error)
 
 	return note, nil
 }
+
+// ListNotebooks returns all notebooks in the Evernote account
+func (ec *EvernoteClient) ListNotebooks() ([]*edam.Notebook, error) {
+	noteStoreClient, err := ec.GetNoteStoreClient()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create NoteStoreClient: %w", err)
+	}
+
+	context, cancel := context.WithTimeout(context.Background(), Timeout)
+	defer cancel()
+
+	notebooks := []*edam.Notebook{}
+	retriableErr := ec.Ensure(context, "Retrieving notebooks from Evernote API", logrus.Fields{"host": ec.GetHost()}, func() error {
+		notebooks, err = noteStoreClient.ListNotebooks(context, ec.AuthToken)
+		return err
+	})
+
+	if retriableErr != nil {
+		return nil, fmt.Errorf("Failed to retrieve notebooks from Evernote API endpoint [%s] after [%d] retries: %w", ec.GetHost(), Retries, retriableErr)
+	}
+
+	return notebooks, nil
+}
+
+// ListTags returns all tags in the Evernote account
+func (ec *EvernoteClient) ListTags() ([]*edam.Tag, error) {
+	noteStoreClient, err := ec.GetNoteStoreClient()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create NoteStoreClient: %w", err)
+	}
+
+	context, cancel := context.WithTimeout(context.Background(), Timeout)
+	defer cancel()
+
+	tags := []*edam.Tag{}
+	retriableErr := ec.Ensure(context, "Retrieving tags from Evernote API", logrus.Fields{"host": ec.GetHost()}, func() error {
+		tags, err = noteStoreClient.ListTags(context, ec.AuthToken)
+		return err
+	})
+
+	if retriableErr != nil {
+		return nil, fmt.Errorf("Failed to retrieve tags from Evernote API endpoint [%s] after [%d] retries: %w", ec.GetHost(), Retries, retriableErr)
+	}
+
+	return tags, nil
+}
@@ -2,6 +2,8 @@ package main
 
 import (
 	"context"
+	"errors"
+	"net"
 	"os"
 	"testing"
 
@@ -104,3 +106,36 @@ func (ec *EvernoteClient) ExpungeNote(guid edam.GUID) (*int32, error) {
 
 	return &sequenceNumber, nil
 }
+
+func TestEvernoteClientEnsure(t *testing.T) {
+	evernoteClient := NewEvernoteClient("token", true)
+
+	networkCalls := 0
+	networkErr := evernoteClient.Ensure(context.Background(), "Retrieving user", logrus.Fields{}, func() error {
+		networkCalls++
+		return &net.OpError{Op: "dial", Err: errors.New("connection refused")}
+	})
+	assert.Equal(t, Retries, networkCalls)
+	assert.Equal(t, NetworkError, ErrorKindOf(networkErr))
+	assert.Contains(t, networkErr.Error(), "connection refused")
+
+	authCalls := 0
+	authExpired := edam.EDAMErrorCode_AUTH_EXPIRED
+	authErr := evernoteClient.Ensure(context.Background(), "Retrieving user", logrus.Fields{}, func() error {
+		authCalls++
+		return &edam.EDAMUserException{ErrorCode: authExpired}
+	})
+	assert.Equal(t, 1, authCalls)
+	assert.Equal(t, AuthenticationError, ErrorKindOf(authErr))
+
+	calls := 0
+	err := evernoteClient.Ensure(context.Background(), "Retrieving user", logrus.Fields{}, func() error {
+		calls++
+		if calls < 2 {
+			return &net.OpError{Op: "dial", Err: errors.New("connection refused")}
+		}
+		return nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, calls)
+}
@@ -4,6 +4,8 @@ import (
 	"errors"
 	"fmt"
 	"net/url"
+	"sort"
+	"time"
 
 	"github.com/dreampuf/evernote-sdk-golang/edam"
 	"github.com/sirupsen/logrus"
@@ -19,6 +21,10 @@ type EvernoteNoteGraph struct {
 	NoteURLType    URLType
 	GraphMLUtil    *GraphMLUtil
 	PageSize       int32
+	Notebooks      map[string]string // notebook names by notebook GUID
+	Tags           map[string]string // tag names by tag GUID
+	Progress       *FetchProgress    // optional progress display of CreateNoteGraph
+	NoteContent    bool              // keep the ENML content of Notes, only required for the Vault OutputFormat
 }
 
 // NewEvernoteNoteGraph creates a new instance of EvernoteNoteGraph
@@ -28,7 +34,9 @@ func NewEvernoteNoteGraph(evernoteClient IEvernoteClient, noteLinkParser *NoteLi
 		NoteLinkParser: noteLinkParser,
 		NoteURLType:    noteURLType,
 		GraphMLUtil:    &GraphMLUtil{},
-		PageSize:       DefaultPageSize}
+		PageSize:       DefaultPageSize,
+		Notebooks:      map[string]string{},
+		Tags:           map[string]string{}}
 }
 
 // SetPageSize sets the pagesize
@@ -43,15 +51,29 @@ func (eng *EvernoteNoteGraph) GetPageSize() int32 {
 
 // CreateNoteGraph creates a NoteGraph based on all Evernote notes in the Evernote account
 func (eng *EvernoteNoteGraph) CreateNoteGraph() (*NoteGraph, error) {
+	err := eng.LoadNotebooks()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to load notebooks: %w", err)
+	}
+
+	err = eng.LoadTags()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to load tags: %w", err)
+	}
+
+	eng.Progress.Start()
+	defer eng.Progress.Finish()
+
 	offset := int32(0)
 	noteGraph := NewNoteGraph()
 	for {
-		logrus.Infof("Processing metadata of Evernote notes from offset [%d] with page size [%d]", offset, eng.PageSize)
+		logrus.WithFields(logrus.Fields{"offset": offset, "page_size": eng.PageSize}).Info("Processing metadata of Evernote notes")
 		evernoteNoteMetadataList, err := eng.EvernoteClient.FindAllNotesMetadata(offset, eng.PageSize)
 		if err != nil {
 			return nil, fmt.Errorf("Failed to process metadata of Evernote notes from offset [%d] with page size [%d]: %w", offset, eng.PageSize, err)
 		}
 
+		eng.Progress.SetTotal(int(evernoteNoteMetadataList.GetTotalNotes()))
 		for _, evernoteNoteMetadata := range evernoteNoteMetadataList.GetNotes() {
 			note, noteLinks, err := eng.ProcessEvernoteNote(evernoteNoteMetadata)
 			if err != nil {
@@ -59,6 +81,7 @@ func (eng *EvernoteNoteGraph) CreateNoteGraph() (*NoteGraph, error) {
 			}
 
 			noteGraph.Add(*note, noteLinks)
+			eng.Progress.NoteProcessed()
 		}
 
 		remainingNotes := evernoteNoteMetadataList.TotalNotes - (evernoteNoteMetadataList.StartIndex + int32(len(evernoteNoteMetadataList.Notes)))
@@ -72,9 +95,39 @@ func (eng *EvernoteNoteGraph) CreateNoteGraph() (*NoteGraph, error) {
 	return noteGraph, nil
 }
 
+// LoadNotebooks loads the names of all notebooks in the Evernote account
+func (eng *EvernoteNoteGraph) LoadNotebooks() error {
+	logrus.Info("Loading Evernote notebooks")
+	notebooks, err := eng.EvernoteClient.ListNotebooks()
+	if err != nil {
+		return err
+	}
+
+	for _, notebook := range notebooks {
+		eng.Notebooks[string(notebook.GetGUID())] = notebook.GetName()
+	}
+
+	return nil
+}
+
+// LoadTags loads the names of all tags in the Evernote account
+func (eng *EvernoteNoteGraph) LoadTags() error {
+	logrus.Info("Loading Evernote tags")
+	tags, err := eng.EvernoteClient.ListTags()
+	if err != nil {
+		return err
+	}
+
+	for _, tag := range tags {
+		eng.Tags[string(tag.GetGUID())] = tag.GetName()
+	}
+
+	return nil
+}
+
 // ProcessEvernoteNote extracts Note and NoteLinks for the NoteGraph from an Evernote note
 func (eng *EvernoteNoteGraph) ProcessEvernoteNote(evernoteNoteMetadata *edam.NoteMetadata) (*Note, []NoteLink, error) {
-	logrus.Infof("Processing Evernote note with GUID [%s] and title [%s]", evernoteNoteMetadata.GetGUID(), evernoteNoteMetadata.GetTitle())
+	logrus.WithFields(logrus.Fields{"note_guid": evernoteNoteMetadata.GetGUID(), "title": evernoteNoteMetadata.GetTitle()}).Info("Processing Evernote note")
 
 	evernoteNote, err := eng.FetchNote(evernoteNoteMetadata)
 	if err != nil {
@@ -95,9 +148,9 @@ func (eng *EvernoteNoteGraph) ProcessEvernoteNote(evernoteNoteMetadata *edam.Not
 	return note, selectedNoteLinks, nil
 }
 
-// CreateNote extracts Note for the NoteGraph from the Evernote note metadata
+// CreateNote extracts Note for the NoteGraph from the Evernote note metadata, the content is only kept if NoteContent is set
 func (eng *EvernoteNoteGraph) CreateNote(evernoteNote *edam.Note) (*Note, error) {
-	logrus.Debugf("Creating Note representation of Evernote note with GUID [%s] and title [%s]", evernoteNote.GetGUID(), evernoteNote.GetTitle())
+	logrus.WithFields(logrus.Fields{"note_guid": evernoteNote.GetGUID(), "title": evernoteNote.GetTitle()}).Debug("Creating Note representation of Evernote note")
 
 	noteGUID := string(evernoteNote.GetGUID())
 	noteTitle := evernoteNote.GetTitle()
@@ -106,7 +159,38 @@ func (eng *EvernoteNoteGraph) CreateNote(evernoteNote *edam.Note) (*Note, error)
 		return nil, fmt.Errorf("Failed to create Note URL for Evernote note with GUID [%s] and title [%s]: %w", evernoteNote.GetGUID(), evernoteNote.GetTitle(), err)
 	}
 
-	return &Note{GUID: noteGUID, Title: noteTitle, Description: noteTitle, URL: *noteURL, URLType: *noteURLType}, nil
+	noteCreated := eng.CreateNoteTime(evernoteNote.Created)
+	noteUpdated := eng.CreateNoteTime(evernoteNote.Updated)
+	noteNotebook := eng.Notebooks[evernoteNote.GetNotebookGuid()]
+	noteTags := eng.CreateNoteTags(evernoteNote.GetTagGuids())
+	noteContent := ""
+	if eng.NoteContent {
+		noteContent = evernoteNote.GetContent()
+	}
+
+	return &Note{GUID: noteGUID, Title: noteTitle, Description: noteTitle, URL: *noteURL, URLType: *noteURLType, Notebook: noteNotebook, Tags: noteTags, Content: noteContent, Created: noteCreated, Updated: noteUpdated}, nil
+}
+
+// CreateNoteTags returns the sorted names of the tags with the tag GUIDs, returns nil if the Evernote note has no known tags
+func (eng *EvernoteNoteGraph) CreateNoteTags(tagGUIDs []edam.GUID) []string {
+	var noteTags []string
+	for _, tagGUID := range tagGUIDs {
+		if tagName, found := eng.Tags[string(tagGUID)]; found {
+			noteTags = append(noteTags, tagName)
+		}
+	}
+
+	sort.Strings(noteTags)
+	return noteTags
+}
+
+// CreateNoteTime converts the Evernote timestamp (milliseconds since the epoch) to UTC time, returns zero time if the timestamp is not set
+func (eng *EvernoteNoteGraph) CreateNoteTime(timestamp *edam.Timestamp) time.Time {
+	if timestamp == nil {
+		return time.Time{}
+	}
+
+	return time.Unix(0, int64(*timestamp)*int64(time.Millisecond)).UTC()
 }
 
 // CreateNoteURL creates the URL for the Note with EvernoteNoteGraph.NoteURLType
@@ -132,7 +216,7 @@ func (eng *EvernoteNoteGraph) CreateNoteURL(noteGUID string) (*url.URL, *URLType
 
 // FetchNote fetches the Evernote note
 func (eng *EvernoteNoteGraph) FetchNote(evernoteNoteMetadata *edam.NoteMetadata) (*edam.Note, error) {
-	logrus.Debugf("Fetching Evernote note and note content with GUID [%s] and title [%s]", evernoteNoteMetadata.GetGUID(), evernoteNoteMetadata.GetTitle())
+	logrus.WithFields(logrus.Fields{"note_guid": evernoteNoteMetadata.GetGUID(), "title": evernoteNoteMetadata.GetTitle()}).Debug("Fetching Evernote note and note content")
 	evernoteNote, err := eng.EvernoteClient.GetNoteWithContent(evernoteNoteMetadata.GetGUID())
 	if err != nil {
 		return nil, fmt.Errorf("Failed to fetch Evernote note and note content with GUID [%s] and title [%s]: %w", evernoteNoteMetadata.GetGUID(), evernoteNoteMetadata.GetTitle(), err)
@@ -143,14 +227,15 @@ func (eng *EvernoteNoteGraph) FetchNote(evernoteNoteMetadata *edam.NoteMetadata)
 
 // ExtractNoteLinks extracts NoteLinks for the NoteGraph from the Evernote note
 func (eng *EvernoteNoteGraph) ExtractNoteLinks(evernoteNote *edam.Note) ([]NoteLink, error) {
-	logrus.Debugf("Parsing content of Evernote note with GUID [%s] and title [%s]", evernoteNote.GetGUID(), evernoteNote.GetTitle())
+	logEntry := logrus.WithFields(logrus.Fields{"note_guid": evernoteNote.GetGUID(), "title": evernoteNote.GetTitle()})
+	logEntry.Debug("Parsing content of Evernote note")
 	noteLinks, err := eng.NoteLinkParser.ExtractNoteLinks(string(evernoteNote.GetGUID()), evernoteNote.GetContent())
 	if err != nil {
 		return nil, fmt.Errorf("Failed to parse content of Evernote note with GUID [%s] and title [%s]: %w", evernoteNote.GetGUID(), evernoteNote.GetTitle(), err)
 	}
 
-	logrus.Debugf("Detected [%d] NoteLinks in Evernote note with GUID [%s] and title [%s]", len(noteLinks), evernoteNote.GetGUID(), evernoteNote.GetTitle())
-	logrus.Tracef("Evernote note with GUID [%s] and title [%s] has [%d] NoteLinks: [%s]", evernoteNote.GetGUID(), evernoteNote.GetTitle(), len(noteLinks), noteLinks)
+	logEntry.WithField("note_links", len(noteLinks)).Debug("Detected NoteLinks in Evernote note")
+	logEntry.Tracef("Evernote note has NoteLinks: [%s]", noteLinks)
 	return noteLinks, nil
 }
 
@@ -167,7 +252,7 @@ func (eng *EvernoteNoteGraph) SelectNoteLinks(note *Note, noteLinks []NoteLink)
 		}
 	}
 
-	logrus.Tracef("Selected [%d] out of [%d] links with types AppLink and WebLink for Note with GUID [%s] and title [%s] to be included in NoteGraph", len(noteLinks), len(selectedNoteLinks), note.GUID, note.Title)
+	logrus.WithFields(logrus.Fields{"note_guid": note.GUID, "title": note.Title, "note_links": len(noteLinks), "selected_note_links": len(selectedNoteLinks)}).Trace("Selected links with types AppLink and WebLink to be included in NoteGraph")
 
 	return selectedNoteLinks
 }
@@ -3,6 +3,7 @@ package main
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/dreampuf/evernote-sdk-golang/edam"
 	"github.com/stretchr/testify/assert"
@@ -38,6 +39,16 @@ func (m *MockEvernoteClient) GetNoteWithContent(guid edam.GUID) (*edam.Note, err
 	return args.Get(0).(*edam.Note), args.Error(1)
 }
 
+func (m *MockEvernoteClient) ListNotebooks() ([]*edam.Notebook, error) {
+	args := m.Called()
+	return args.Get(0).([]*edam.Notebook), args.Error(1)
+}
+
+func (m *MockEvernoteClient) ListTags() ([]*edam.Tag, error) {
+	args := m.Called()
+	return args.Get(0).([]*edam.Tag), args.Error(1)
+}
+
 func TestSelectNoteLinks(t *testing.T) {
 	evernoteNoteGraph := NewEvernoteNoteGraph(nil, nil, WebLink)
 
@@ -70,6 +81,85 @@ func TestCreateNote(t *testing.T) {
 	assert.Equal(t, expectedNote, createdNote)
 }
 
+func TestCreateNoteContent(t *testing.T) {
+	noteLinkParser := NewNoteLinkParser(SandboxEvernoteCom, "userId", "shardId")
+	evernoteNoteGraph := NewEvernoteNoteGraph(nil, noteLinkParser, WebLink)
+
+	evernoteNoteGUID := edam.GUID("1")
+	evernoteNoteTitle := "Test"
+	evernoteNoteContent := `<?xml version="1.0" encoding="UTF-8"?><!DOCTYPE en-note SYSTEM "http://xml.evernote.com/pub/enml2.dtd"><en-note><div>Test</div></en-note>`
+	evernoteNote := &edam.Note{GUID: &evernoteNoteGUID, Title: &evernoteNoteTitle, Content: &evernoteNoteContent}
+	createdNote, err := evernoteNoteGraph.CreateNote(evernoteNote)
+	if err != nil {
+		panic(err)
+	}
+
+	assert.Empty(t, createdNote.Content)
+
+	evernoteNoteGraph.NoteContent = true
+	createdNote, err = evernoteNoteGraph.CreateNote(evernoteNote)
+	if err != nil {
+		panic(err)
+	}
+
+	assert.Equal(t, evernoteNoteContent, createdNote.Content)
+}
+
+func TestCreateNoteWithNotebook(t *testing.T) {
+	noteLinkParser := NewNoteLinkParser(SandboxEvernoteCom, "userId", "shardId")
+	mockEvernoteClient := new(MockEvernoteClient)
+	evernoteNoteGraph := NewEvernoteNoteGraph(mockEvernoteClient, noteLinkParser, WebLink)
+
+	notebookGUID := edam.GUID("notebook")
+	notebookName := "Notebook"
+	mockEvernoteClient.On("ListNotebooks").Return([]*edam.Notebook{{GUID: &notebookGUID, Name: &notebookName}}, nil)
+	err := evernoteNoteGraph.LoadNotebooks()
+	if err != nil {
+		panic(err)
+	}
+
+	evernoteNoteGUID := edam.GUID("1")
+	evernoteNoteTitle := "Test"
+	evernoteNotebookGUID := string(notebookGUID)
+	createdNote, err := evernoteNoteGraph.CreateNote(&edam.Note{GUID: &evernoteNoteGUID, Title: &evernoteNoteTitle, NotebookGuid: &evernoteNotebookGUID})
+	if err != nil {
+		panic(err)
+	}
+
+	assert.Equal(t, notebookName, createdNote.Notebook)
+}
+
+func TestCreateNoteWithTags(t *testing.T) {
+	noteLinkParser := NewNoteLinkParser(SandboxEvernoteCom, "userId", "shardId")
+	mockEvernoteClient := new(MockEvernoteClient)
+	evernoteNoteGraph := NewEvernoteNoteGraph(mockEvernoteClient, noteLinkParser, WebLink)
+
+	tagGUIDs := []edam.GUID{"tag1", "tag2"}
+	tagNames := []string{"Zeta", "Alpha"}
+	mockEvernoteClient.On("ListTags").Return([]*edam.Tag{{GUID: &tagGUIDs[0], Name: &tagNames[0]}, {GUID: &tagGUIDs[1], Name: &tagNames[1]}}, nil)
+	err := evernoteNoteGraph.LoadTags()
+	if err != nil {
+		panic(err)
+	}
+
+	evernoteNoteGUID := edam.GUID("1")
+	evernoteNoteTitle := "Test"
+	createdNote, err := evernoteNoteGraph.CreateNote(&edam.Note{GUID: &evernoteNoteGUID, Title: &evernoteNoteTitle, TagGuids: []edam.GUID{"tag1", "tag2", "unknown"}})
+	if err != nil {
+		panic(err)
+	}
+
+	assert.Equal(t, []string{"Alpha", "Zeta"}, createdNote.Tags)
+}
+
+func TestCreateNoteTime(t *testing.T) {
+	evernoteNoteGraph := NewEvernoteNoteGraph(nil, nil, WebLink)
+
+	timestamp := edam.Timestamp(1577872800000)
+	assert.Equal(t, time.Date(2020, 1, 1, 10, 0, 0, 0, time.UTC), evernoteNoteGraph.CreateNoteTime(&timestamp))
+	assert.True(t, evernoteNoteGraph.CreateNoteTime(nil).IsZero())
+}
+
 func TestCreateNoteURL(t *testing.T) {
 	noteLinkParser := NewNoteLinkParser(SandboxEvernoteCom, "userId", "shardId")
 
@@ -153,6 +243,8 @@ func TestCreateNoteGraphWithoutNotes(t *testing.T) {
 	noteLinkParser := NewNoteLinkParser(SandboxEvernoteCom, "userId", "shardId")
 	evernoteNoteGraph := NewEvernoteNoteGraph(mockEvernoteClient, noteLinkParser, WebLink)
 
+	mockEvernoteClient.On("ListNotebooks").Return([]*edam.Notebook{}, nil)
+	mockEvernoteClient.On("ListTags").Return([]*edam.Tag{}, nil)
 	mockEvernoteClient.On("FindAllNotesMetadata", int32(0), mock.Anything).Return(&edam.NotesMetadataList{}, nil)
 
 	noteGraph, err := evernoteNoteGraph.CreateNoteGraph()
@@ -176,6 +268,8 @@ func TestCreateNoteGraphWithOneNote(t *testing.T) {
 	evernoteNoteMetadata := []*edam.NoteMetadata{{GUID: evernoteNoteGUID, Title: &evernoteNoteTitle}}
 	evernoteNoteMetadataList := &edam.NotesMetadataList{StartIndex: offset, TotalNotes: int32(len(evernoteNoteMetadata)), Notes: evernoteNoteMetadata}
 
+	mockEvernoteClient.On("ListNotebooks").Return([]*edam.Notebook{}, nil)
+	mockEvernoteClient.On("ListTags").Return([]*edam.Tag{}, nil)
 	mockEvernoteClient.On("FindAllNotesMetadata", offset, mock.Anything).Return(evernoteNoteMetadataList, nil)
 	mockEvernoteClient.On("GetNoteWithContent", evernoteNoteGUID).Return(&edam.Note{GUID: &evernoteNoteGUID, Title: &evernoteNoteTitle, Content: &evernoteNoteContent}, nil)
 
@@ -193,6 +287,10 @@ func TestCreateNoteGraphWithMultipleNotes(t *testing.T) {
 	noteLinkParser := NewNoteLinkParser(EvernoteCom, "76136038", "s12")
 	evernoteNoteGraph := NewEvernoteNoteGraph(mockEvernoteClient, noteLinkParser, WebLink)
 	evernoteNoteGraph.SetPageSize(2)
+	evernoteNoteGraph.Progress = &FetchProgress{Mode: NoProgress, Now: time.Now}
+
+	mockEvernoteClient.On("ListNotebooks").Return([]*edam.Notebook{}, nil)
+	mockEvernoteClient.On("ListTags").Return([]*edam.Tag{}, nil)
 
 	evernoteNoteMetadataListFirstPage, notesFirstPage := CreateNotes(0, int32(2), int32(3))
 	mockEvernoteClient.On("FindAllNotesMetadata", int32(0), int32(2)).Return(evernoteNoteMetadataListFirstPage, nil)
@@ -210,6 +308,8 @@ func TestCreateNoteGraphWithMultipleNotes(t *testing.T) {
 
 	assert.Len(t, noteGraph.Notes, 3)
 	assert.Len(t, noteGraph.NoteLinks, 6)
+	assert.Equal(t, 3, evernoteNoteGraph.Progress.Total)
+	assert.Equal(t, 3, evernoteNoteGraph.Progress.Processed)
 }
 
 func CreateNotes(offset, count, total int32) (*edam.NotesMetadataList, []edam.Note) {
@@ -0,0 +1,272 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ProgressBarWidth is the number of characters of the bar of the live progress display
+const ProgressBarWidth = 30
+
+// DefaultProgressInterval is the default time between two progress summary lines
+const DefaultProgressInterval = 10 * time.Second
+
+// Enum of all ProgressModes
+const (
+	AutoProgress  ProgressMode = iota // live bar if stderr is a terminal, otherwise summary lines
+	BarProgress   ProgressMode = iota // live bar on stderr
+	LinesProgress ProgressMode = iota // summary lines logged periodically
+	NoProgress    ProgressMode = iota // no progress display
+)
+
+// ProgressMode specifies how the progress of fetching Evernote notes is displayed
+type ProgressMode int
+
+func (pm ProgressMode) String() string {
+	return [...]string{"auto", "bar", "lines", "none"}[pm]
+}
+
+// NewProgressMode creates a ProgressMode from the string value
+func NewProgressMode(value string) (*ProgressMode, error) {
+	for progressMode := AutoProgress; progressMode <= NoProgress; progressMode++ {
+		if value == progressMode.String() {
+			return &progressMode, nil
+		}
+	}
+
+	return nil, errors.New("Invalid ProgressMode value [" + value + "]")
+}
+
+// IsTerminal returns true if the file is a character device such as a terminal, false if it is redirected to a file or pipe
+func IsTerminal(file *os.File) bool {
+	fileInfo, err := file.Stat()
+	return err == nil && fileInfo.Mode()&os.ModeCharDevice != 0
+}
+
+// FetchProgress tracks and displays the progress of fetching Evernote notes, the number of processed notes out of the total
+// number of notes, the rate, the estimated time remaining, and the number of retries and errors, all methods do nothing if the
+// FetchProgress is nil
+type FetchProgress struct {
+	Mode       ProgressMode
+	Output     io.Writer
+	Interval   time.Duration
+	Now        func() time.Time
+	Total      int
+	Processed  int
+	Retries    int
+	Errors     int
+	started    time.Time
+	lastLine   time.Time
+	barVisible bool
+	mutex      sync.Mutex
+}
+
+// NewFetchProgress creates a new instance of FetchProgress displaying the live bar on the output file, AutoProgress is resolved
+// to BarProgress if the output file is a terminal and to LinesProgress otherwise
+func NewFetchProgress(mode ProgressMode, output *os.File) *FetchProgress {
+	if mode == AutoProgress {
+		mode = LinesProgress
+		if IsTerminal(output) {
+			mode = BarProgress
+		}
+	}
+
+	return &FetchProgress{Mode: mode, Output: output, Interval: DefaultProgressInterval, Now: time.Now}
+}
+
+// Start starts measuring the rate
+func (fp *FetchProgress) Start() {
+	if fp == nil {
+		return
+	}
+
+	fp.mutex.Lock()
+	defer fp.mutex.Unlock()
+	fp.started = fp.Now()
+	fp.lastLine = fp.started
+}
+
+// SetTotal sets the total number of notes as reported by the Evernote API
+func (fp *FetchProgress) SetTotal(total int) {
+	if fp == nil {
+		return
+	}
+
+	fp.mutex.Lock()
+	fp.Total = total
+	fp.mutex.Unlock()
+	fp.Display(false)
+}
+
+// NoteProcessed counts a processed note
+func (fp *FetchProgress) NoteProcessed() {
+	if fp == nil {
+		return
+	}
+
+	fp.mutex.Lock()
+	fp.Processed++
+	fp.mutex.Unlock()
+	fp.Display(false)
+}
+
+// Retry counts a retried call to the Evernote API
+func (fp *FetchProgress) Retry() {
+	if fp == nil {
+		return
+	}
+
+	fp.mutex.Lock()
+	fp.Retries++
+	fp.mutex.Unlock()
+	fp.Display(false)
+}
+
+// Finish displays the final progress, the live bar is completed with a line break
+func (fp *FetchProgress) Finish() {
+	if fp == nil {
+		return
+	}
+
+	fp.Display(true)
+	fp.mutex.Lock()
+	defer fp.mutex.Unlock()
+	if fp.barVisible {
+		fmt.Fprintln(fp.Output)
+		fp.barVisible = false
+	}
+}
+
+// Levels returns the log levels counted as errors, FetchProgress is a logrus Hook
+func (fp *FetchProgress) Levels() []logrus.Level {
+	return []logrus.Level{logrus.PanicLevel, logrus.FatalLevel, logrus.ErrorLevel}
+}
+
+// Fire counts the logged error, errors are displayed with the next update as the logrus Logger is locked while hooks are fired
+func (fp *FetchProgress) Fire(entry *logrus.Entry) error {
+	if fp == nil {
+		return nil
+	}
+
+	fp.mutex.Lock()
+	defer fp.mutex.Unlock()
+	fp.Errors++
+	return nil
+}
+
+// Rate returns the processed notes per second
+func (fp *FetchProgress) Rate() float64 {
+	elapsed := fp.Now().Sub(fp.started).Seconds()
+	if elapsed <= 0 {
+		return 0
+	}
+
+	return float64(fp.Processed) / elapsed
+}
+
+// ETA returns the estimated time until all notes are processed, returns false if the ETA cannot be estimated yet
+func (fp *FetchProgress) ETA() (time.Duration, bool) {
+	rate := fp.Rate()
+	if rate <= 0 || fp.Total == 0 {
+		return 0, false
+	}
+
+	remaining := fp.Total - fp.Processed
+	if remaining < 0 {
+		remaining = 0
+	}
+
+	return (time.Duration(float64(remaining)/rate) * time.Second).Round(time.Second), true
+}
+
+// Summary returns the progress as text, e.g. 120/1500 notes (8%), 4.0 notes/s, ETA 5m45s, 1 retries, 0 errors
+func (fp *FetchProgress) Summary() string {
+	percent := 0
+	if fp.Total > 0 {
+		percent = fp.Processed * 100 / fp.Total
+	}
+
+	eta := "?"
+	if duration, known := fp.ETA(); known {
+		eta = duration.String()
+	}
+
+	return fmt.Sprintf("%d/%d notes (%d%%), %.1f notes/s, ETA %s, %d retries, %d errors", fp.Processed, fp.Total, percent, fp.Rate(), eta, fp.Retries, fp.Errors)
+}
+
+// Bar returns the live bar followed by the Summary
+func (fp *FetchProgress) Bar() string {
+	filled := 0
+	if fp.Total > 0 {
+		filled = fp.Processed * ProgressBarWidth / fp.Total
+	}
+	if filled > ProgressBarWidth {
+		filled = ProgressBarWidth
+	}
+
+	return "[" + strings.Repeat("#", filled) + strings.Repeat(".", ProgressBarWidth-filled) + "] " + fp.Summary()
+}
+
+// Display redraws the live bar or logs a summary line if the Interval has passed since the last summary line or final is set
+func (fp *FetchProgress) Display(final bool) {
+	fp.mutex.Lock()
+	if fp.Mode == BarProgress {
+		defer fp.mutex.Unlock()
+		fp.drawBar()
+		return
+	}
+
+	now := fp.Now()
+	if fp.Mode != LinesProgress || (!final && now.Sub(fp.lastLine) < fp.Interval) {
+		fp.mutex.Unlock()
+		return
+	}
+
+	fp.lastLine = now
+	fields := logrus.Fields{"processed": fp.Processed, "total": fp.Total, "rate": fmt.Sprintf("%.1f", fp.Rate()), "retries": fp.Retries, "errors": fp.Errors}
+	if eta, known := fp.ETA(); known {
+		fields["eta"] = eta.String()
+	}
+	fp.mutex.Unlock()
+
+	logrus.WithFields(fields).Info("Fetch progress")
+}
+
+// drawBar overwrites the current line of the Output with the live bar, the mutex must be held
+func (fp *FetchProgress) drawBar() {
+	fmt.Fprint(fp.Output, "\r\033[K"+fp.Bar())
+	fp.barVisible = true
+}
+
+// Writer returns a writer for log output to the same terminal as the live bar, the live bar is cleared before and redrawn after
+// each log entry so that log entries are not mixed with the live bar
+func (fp *FetchProgress) Writer(output io.Writer) io.Writer {
+	return &progressWriter{fetchProgress: fp, output: output}
+}
+
+// progressWriter writes log output around the live bar of the FetchProgress
+type progressWriter struct {
+	fetchProgress *FetchProgress
+	output        io.Writer
+}
+
+// Write clears the live bar, writes the log entry, and redraws the live bar
+func (pw *progressWriter) Write(p []byte) (int, error) {
+	pw.fetchProgress.mutex.Lock()
+	defer pw.fetchProgress.mutex.Unlock()
+	if !pw.fetchProgress.barVisible {
+		return pw.output.Write(p)
+	}
+
+	fmt.Fprint(pw.output, "\r\033[K")
+	n, err := pw.output.Write(p)
+	pw.fetchProgress.drawBar()
+	return n, err
+}
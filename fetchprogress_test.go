@@ -0,0 +1,96 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func CreateTestFetchProgress(mode ProgressMode, output *bytes.Buffer, now *time.Time) *FetchProgress {
+	fetchProgress := &FetchProgress{Mode: mode, Output: output, Interval: 10 * time.Second, Now: func() time.Time { return *now }}
+	fetchProgress.Start()
+	fetchProgress.SetTotal(100)
+	return fetchProgress
+}
+
+func TestNewProgressMode(t *testing.T) {
+	progressMode, err := NewProgressMode("lines")
+	assert.Nil(t, err)
+	assert.Equal(t, LinesProgress, *progressMode)
+
+	_, err = NewProgressMode("spinner")
+	assert.NotNil(t, err)
+}
+
+func TestFetchProgressSummary(t *testing.T) {
+	var output bytes.Buffer
+	now := time.Date(2020, 6, 1, 10, 0, 0, 0, time.UTC)
+	fetchProgress := CreateTestFetchProgress(NoProgress, &output, &now)
+	assert.Equal(t, "0/100 notes (0%), 0.0 notes/s, ETA ?, 0 retries, 0 errors", fetchProgress.Summary())
+
+	now = now.Add(10 * time.Second)
+	for processed := 0; processed < 25; processed++ {
+		fetchProgress.NoteProcessed()
+	}
+	fetchProgress.Retry()
+	fetchProgress.Fire(&logrus.Entry{})
+
+	assert.Equal(t, 2.5, fetchProgress.Rate())
+	assert.Equal(t, "25/100 notes (25%), 2.5 notes/s, ETA 30s, 1 retries, 1 errors", fetchProgress.Summary())
+	assert.Equal(t, "[#######.......................] 25/100 notes (25%), 2.5 notes/s, ETA 30s, 1 retries, 1 errors", fetchProgress.Bar())
+	assert.Equal(t, "", output.String())
+}
+
+func TestFetchProgressBar(t *testing.T) {
+	var output bytes.Buffer
+	now := time.Date(2020, 6, 1, 10, 0, 0, 0, time.UTC)
+	fetchProgress := CreateTestFetchProgress(BarProgress, &output, &now)
+	assert.Equal(t, "\r\033[K["+"..............................] 0/100 notes (0%), 0.0 notes/s, ETA ?, 0 retries, 0 errors", output.String())
+
+	output.Reset()
+	logWriter := fetchProgress.Writer(&output)
+	logWriter.Write([]byte("INFO\t - Processing Evernote note\n"))
+	assert.Equal(t, "\r\033[KINFO\t - Processing Evernote note\n\r\033[K[..............................] 0/100 notes (0%), 0.0 notes/s, ETA ?, 0 retries, 0 errors", output.String())
+
+	output.Reset()
+	fetchProgress.Finish()
+	logWriter.Write([]byte("INFO\t - Saving NoteGraph snapshot to file\n"))
+	assert.Equal(t, "\r\033[K[..............................] 0/100 notes (0%), 0.0 notes/s, ETA ?, 0 retries, 0 errors\nINFO\t - Saving NoteGraph snapshot to file\n", output.String())
+}
+
+func TestFetchProgressLines(t *testing.T) {
+	var logOutput bytes.Buffer
+	logrus.SetFormatter(&PlainFormatter{})
+	logrus.SetOutput(&logOutput)
+	defer InitLogger(LogArgs{}, false)
+
+	var output bytes.Buffer
+	now := time.Date(2020, 6, 1, 10, 0, 0, 0, time.UTC)
+	fetchProgress := CreateTestFetchProgress(LinesProgress, &output, &now)
+
+	now = now.Add(5 * time.Second)
+	fetchProgress.NoteProcessed()
+	assert.Equal(t, "", logOutput.String())
+
+	now = now.Add(5 * time.Second)
+	fetchProgress.NoteProcessed()
+	assert.Equal(t, "INFO\t - Fetch progress errors=0 eta=8m10s processed=2 rate=0.2 retries=0 total=100\n", logOutput.String())
+
+	logOutput.Reset()
+	fetchProgress.Finish()
+	assert.Contains(t, logOutput.String(), "Fetch progress")
+	assert.Equal(t, "", output.String())
+}
+
+func TestFetchProgressNil(t *testing.T) {
+	var fetchProgress *FetchProgress
+	fetchProgress.Start()
+	fetchProgress.SetTotal(100)
+	fetchProgress.NoteProcessed()
+	fetchProgress.Retry()
+	fetchProgress.Finish()
+	assert.Nil(t, fetchProgress.Fire(&logrus.Entry{}))
+}
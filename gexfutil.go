@@ -0,0 +1,259 @@
+package main
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// GEXFNamespace is the XML namespace of GEXF 1.2 documents
+const GEXFNamespace = "http://www.gexf.net/1.2draft"
+
+// GEXFVersion is the version of GEXF documents
+const GEXFVersion = "1.2"
+
+// GEXFCreator is the creator of GEXF documents stored in the meta data
+const GEXFCreator = "evernote-note-graph"
+
+// NodeStateID is the ID of the dynamic GEXF attribute used for the state (created or updated) of nodes over time
+const NodeStateID = "node-state"
+
+// NodeStateName is the name of the dynamic GEXF attribute used for the state (created or updated) of nodes over time
+const NodeStateName = "state"
+
+// GEXFDocument is the root element of a GEXF document
+type GEXFDocument struct {
+	XMLName xml.Name  `xml:"gexf"`
+	XMLNS   string    `xml:"xmlns,attr"`
+	Version string    `xml:"version,attr"`
+	Meta    GEXFMeta  `xml:"meta"`
+	Graph   GEXFGraph `xml:"graph"`
+}
+
+// GEXFMeta contains the meta data of a GEXF document
+type GEXFMeta struct {
+	Creator     string `xml:"creator"`
+	Description string `xml:"description"`
+}
+
+// GEXFGraph is a dynamic graph of a GEXF document
+type GEXFGraph struct {
+	Mode            string           `xml:"mode,attr"`
+	DefaultEdgeType string           `xml:"defaultedgetype,attr"`
+	TimeFormat      string           `xml:"timeformat,attr"`
+	Attributes      []GEXFAttributes `xml:"attributes"`
+	Nodes           []GEXFNode       `xml:"nodes>node"`
+	Edges           []GEXFEdge       `xml:"edges>edge"`
+}
+
+// GEXFAttributes declares the static or dynamic attributes of either nodes or edges
+type GEXFAttributes struct {
+	Class      string          `xml:"class,attr"`
+	Mode       string          `xml:"mode,attr"`
+	Attributes []GEXFAttribute `xml:"attribute"`
+}
+
+// GEXFAttribute declares an attribute of nodes or edges
+type GEXFAttribute struct {
+	ID    string `xml:"id,attr"`
+	Title string `xml:"title,attr"`
+	Type  string `xml:"type,attr"`
+}
+
+// GEXFNode is a node with attribute values and spells
+type GEXFNode struct {
+	ID        string         `xml:"id,attr"`
+	Label     string         `xml:"label,attr"`
+	AttValues []GEXFAttValue `xml:"attvalues>attvalue,omitempty"`
+	Spells    []GEXFSpell    `xml:"spells>spell,omitempty"`
+}
+
+// GEXFEdge is a directed edge with attribute values and spells
+type GEXFEdge struct {
+	ID        string         `xml:"id,attr"`
+	Source    string         `xml:"source,attr"`
+	Target    string         `xml:"target,attr"`
+	Label     string         `xml:"label,attr"`
+	AttValues []GEXFAttValue `xml:"attvalues>attvalue,omitempty"`
+	Spells    []GEXFSpell    `xml:"spells>spell,omitempty"`
+}
+
+// GEXFAttValue is the value of an attribute, dynamic attribute values have a start and/or end
+type GEXFAttValue struct {
+	For   string `xml:"for,attr"`
+	Value string `xml:"value,attr"`
+	Start string `xml:"start,attr,omitempty"`
+	End   string `xml:"end,attr,omitempty"`
+}
+
+// GEXFSpell is a period of time in which a node or edge exists, spells without end are open-ended
+type GEXFSpell struct {
+	Start string `xml:"start,attr,omitempty"`
+	End   string `xml:"end,attr,omitempty"`
+}
+
+// GEXFUtil converts a NoteGraph to a dynamic GEXF document in which Notes exist from their creation time and NoteLinks from
+// the creation time of the later of their source and target Note
+type GEXFUtil struct {
+	ExportAttributes
+}
+
+// NewGEXFUtil creates a new instance of GEXFUtil
+func NewGEXFUtil() *GEXFUtil {
+	return &GEXFUtil{}
+}
+
+// ExportNoteGraph converts the NoteGraph into a GEXF document and writes the GEXF document to the writer
+func (gu *GEXFUtil) ExportNoteGraph(noteGraph *NoteGraph, allNotes bool, writer io.Writer) error {
+	_, headerErr := io.WriteString(writer, xml.Header)
+	if headerErr != nil {
+		return fmt.Errorf("Failed to write GEXF header: %w", headerErr)
+	}
+
+	encoder := xml.NewEncoder(writer)
+	encoder.Indent("", "  ")
+	encodeErr := encoder.Encode(gu.ConvertNoteGraph(noteGraph, allNotes))
+	if encodeErr != nil {
+		return fmt.Errorf("Failed to encode GEXF document: %w", encodeErr)
+	}
+
+	_, newlineErr := io.WriteString(writer, "\n")
+	return newlineErr
+}
+
+// ConvertNoteGraph converts the NoteGraph into a GEXF document
+func (gu *GEXFUtil) ConvertNoteGraph(noteGraph *NoteGraph, allNotes bool) *GEXFDocument {
+	notes := *noteGraph.GetLinkedNotes()
+	if allNotes {
+		notes = *noteGraph.GetNotes()
+	}
+
+	noteLinks := *noteGraph.GetValidNoteLinks()
+
+	nodes := gu.CreateNodes(notes)
+	edges := gu.CreateEdges(noteGraph, noteLinks)
+
+	logrus.Infof("Converting NoteGraph with [%d|%d] Notes|nodes and [%d|%d] NoteLinks|edges to GEXF", len(notes), len(nodes), len(noteLinks), len(edges))
+
+	return &GEXFDocument{
+		XMLNS:   GEXFNamespace,
+		Version: GEXFVersion,
+		Meta:    GEXFMeta{Creator: GEXFCreator, Description: "Evernote NoteGraph"},
+		Graph: GEXFGraph{
+			Mode:            "dynamic",
+			DefaultEdgeType: "directed",
+			TimeFormat:      "dateTime",
+			Attributes:      gu.CreateAttributes(),
+			Nodes:           nodes,
+			Edges:           edges}}
+}
+
+// CreateAttributes declares the static node attributes, the dynamic node state attribute, and the static edge attributes
+func (gu *GEXFUtil) CreateAttributes() []GEXFAttributes {
+	staticNodeAttributes := GEXFAttributes{Class: "node", Mode: "static", Attributes: []GEXFAttribute{
+		{ID: NodeDescriptionID, Title: NodeDescriptionName, Type: "string"},
+		{ID: NodeURLID, Title: NodeURLName, Type: "string"}}}
+	for _, noteAttribute := range gu.NoteAttributes {
+		staticNodeAttributes.Attributes = append(staticNodeAttributes.Attributes, GEXFAttribute{ID: noteAttribute.ID, Title: noteAttribute.Name, Type: gu.AttributeType(noteAttribute.Type)})
+	}
+
+	dynamicNodeAttributes := GEXFAttributes{Class: "node", Mode: "dynamic", Attributes: []GEXFAttribute{
+		{ID: NodeStateID, Title: NodeStateName, Type: "string"}}}
+
+	staticEdgeAttributes := GEXFAttributes{Class: "edge", Mode: "static", Attributes: []GEXFAttribute{}}
+	for _, noteLinkAttribute := range gu.NoteLinkAttributes {
+		staticEdgeAttributes.Attributes = append(staticEdgeAttributes.Attributes, GEXFAttribute{ID: noteLinkAttribute.ID, Title: noteLinkAttribute.Name, Type: gu.AttributeType(noteLinkAttribute.Type)})
+	}
+
+	attributes := []GEXFAttributes{staticNodeAttributes, dynamicNodeAttributes}
+	if len(staticEdgeAttributes.Attributes) > 0 {
+		attributes = append(attributes, staticEdgeAttributes)
+	}
+
+	return attributes
+}
+
+// AttributeType converts a GraphML attribute type into a GEXF attribute type
+func (gu *GEXFUtil) AttributeType(attributeType string) string {
+	if attributeType == "int" {
+		return "integer"
+	}
+
+	return attributeType
+}
+
+// CreateNodes creates GEXF nodes from the Notes, Notes exist from their creation time and are in state "updated" from their
+// last update time, Notes without creation time exist during the whole timeline
+func (gu *GEXFUtil) CreateNodes(notes []Note) []GEXFNode {
+	nodes := []GEXFNode{}
+	for _, note := range notes {
+		node := GEXFNode{ID: note.GUID, Label: note.Title, AttValues: []GEXFAttValue{
+			{For: NodeDescriptionID, Value: note.Description},
+			{For: NodeURLID, Value: note.URL.String()}}}
+		for _, noteAttribute := range gu.NoteAttributes {
+			if value, found := noteAttribute.Values[note.GUID]; found {
+				node.AttValues = append(node.AttValues, GEXFAttValue{For: noteAttribute.ID, Value: value})
+			}
+		}
+
+		if !note.Created.IsZero() {
+			node.Spells = []GEXFSpell{{Start: gu.FormatTime(note.Created)}}
+			if note.Updated.After(note.Created) {
+				node.AttValues = append(node.AttValues,
+					GEXFAttValue{For: NodeStateID, Value: "created", Start: gu.FormatTime(note.Created), End: gu.FormatTime(note.Updated)},
+					GEXFAttValue{For: NodeStateID, Value: "updated", Start: gu.FormatTime(note.Updated)})
+			} else {
+				node.AttValues = append(node.AttValues, GEXFAttValue{For: NodeStateID, Value: "created", Start: gu.FormatTime(note.Created)})
+			}
+		}
+
+		nodes = append(nodes, node)
+	}
+
+	return nodes
+}
+
+// CreateEdges creates GEXF edges from the NoteLinks, the time a NoteLink was added is unknown, NoteLinks therefore exist from
+// the earliest possible time, the creation time of the later of their source and target Note
+func (gu *GEXFUtil) CreateEdges(noteGraph *NoteGraph, noteLinks []NoteLink) []GEXFEdge {
+	edges := []GEXFEdge{}
+	for index, edgeNoteLink := range CanonicalNoteLinks(noteLinks) {
+		edgeID, noteLink := edgeNoteLink.EdgeID, edgeNoteLink.NoteLink
+		edge := GEXFEdge{ID: strconv.Itoa(index), Source: noteLink.SourceNoteGUID, Target: noteLink.TargetNoteGUID, Label: noteLink.Text}
+		for _, noteLinkAttribute := range gu.NoteLinkAttributes {
+			if value, found := noteLinkAttribute.Value(edgeID, noteLink); found {
+				edge.AttValues = append(edge.AttValues, GEXFAttValue{For: noteLinkAttribute.ID, Value: value})
+			}
+		}
+
+		if start := gu.NoteLinkStart(noteGraph, noteLink); !start.IsZero() {
+			edge.Spells = []GEXFSpell{{Start: gu.FormatTime(start)}}
+		}
+
+		edges = append(edges, edge)
+	}
+
+	return edges
+}
+
+// NoteLinkStart returns the creation time of the later of the source and target Note of the NoteLink, or zero if the creation
+// time of both Notes is unknown
+func (gu *GEXFUtil) NoteLinkStart(noteGraph *NoteGraph, noteLink NoteLink) time.Time {
+	start := time.Time{}
+	for _, noteGUID := range []string{noteLink.SourceNoteGUID, noteLink.TargetNoteGUID} {
+		if note := noteGraph.GetNote(noteGUID); note != nil && note.Created.After(start) {
+			start = note.Created
+		}
+	}
+
+	return start
+}
+
+// FormatTime formats the time as xsd:dateTime in UTC
+func (gu *GEXFUtil) FormatTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"bytes"
+	"encoding/xml"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func CreateGEXFTestNoteGraph() *NoteGraph {
+	created := time.Date(2020, 1, 1, 10, 0, 0, 0, time.UTC)
+	updated := time.Date(2020, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	// Note A created before Note B links to Note B, Note C has no creation time, Note D is not linked
+	noteGraph := NewNoteGraph()
+	noteGraph.Add(Note{GUID: "A", Title: "TitleA", Created: created, Updated: updated}, []NoteLink{{SourceNoteGUID: "A", TargetNoteGUID: "B", Text: "LinkAB"}})
+	noteGraph.Add(Note{GUID: "B", Title: "TitleB", Created: created.AddDate(0, 1, 0), Updated: created.AddDate(0, 1, 0)}, []NoteLink{{SourceNoteGUID: "B", TargetNoteGUID: "C", Text: "LinkBC"}})
+	noteGraph.Add(Note{GUID: "C", Title: "TitleC"}, []NoteLink{})
+	noteGraph.Add(Note{GUID: "D", Title: "TitleD"}, []NoteLink{})
+	return noteGraph
+}
+
+func FindGEXFNode(nodes []GEXFNode, id string) *GEXFNode {
+	for _, node := range nodes {
+		if node.ID == id {
+			return &node
+		}
+	}
+
+	return nil
+}
+
+func TestConvertNoteGraphToGEXF(t *testing.T) {
+	gexfUtil := NewGEXFUtil()
+	gexfUtil.AddNoteAttributes(NoteAttribute{ID: NodeCommunityID, Name: NodeCommunityName, Type: "int", Values: map[string]string{"A": "0"}})
+	gexfDocument := gexfUtil.ConvertNoteGraph(CreateGEXFTestNoteGraph(), false)
+
+	assert.Equal(t, "dynamic", gexfDocument.Graph.Mode)
+	assert.Equal(t, "dateTime", gexfDocument.Graph.TimeFormat)
+	assert.Equal(t, 3, len(gexfDocument.Graph.Nodes))
+	assert.Nil(t, FindGEXFNode(gexfDocument.Graph.Nodes, "D"))
+	assert.Contains(t, gexfDocument.Graph.Attributes[0].Attributes, GEXFAttribute{ID: NodeCommunityID, Title: NodeCommunityName, Type: "integer"})
+
+	nodeA := FindGEXFNode(gexfDocument.Graph.Nodes, "A")
+	assert.Equal(t, []GEXFSpell{{Start: "2020-01-01T10:00:00Z"}}, nodeA.Spells)
+	assert.Contains(t, nodeA.AttValues, GEXFAttValue{For: NodeCommunityID, Value: "0"})
+	assert.Contains(t, nodeA.AttValues, GEXFAttValue{For: NodeStateID, Value: "created", Start: "2020-01-01T10:00:00Z", End: "2020-03-01T10:00:00Z"})
+	assert.Contains(t, nodeA.AttValues, GEXFAttValue{For: NodeStateID, Value: "updated", Start: "2020-03-01T10:00:00Z"})
+
+	nodeB := FindGEXFNode(gexfDocument.Graph.Nodes, "B")
+	assert.Contains(t, nodeB.AttValues, GEXFAttValue{For: NodeStateID, Value: "created", Start: "2020-02-01T10:00:00Z"})
+
+	nodeC := FindGEXFNode(gexfDocument.Graph.Nodes, "C")
+	assert.Empty(t, nodeC.Spells)
+
+	for _, edge := range gexfDocument.Graph.Edges {
+		assert.Equal(t, []GEXFSpell{{Start: "2020-02-01T10:00:00Z"}}, edge.Spells)
+	}
+}
+
+func TestExportNoteGraphToGEXF(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	err := NewGEXFUtil().ExportNoteGraph(CreateGEXFTestNoteGraph(), true, buffer)
+	if err != nil {
+		panic(err)
+	}
+
+	assert.Contains(t, buffer.String(), xml.Header)
+	assert.Contains(t, buffer.String(), `<gexf xmlns="http://www.gexf.net/1.2draft" version="1.2">`)
+
+	gexfDocument := &GEXFDocument{}
+	err = xml.Unmarshal(buffer.Bytes(), gexfDocument)
+	if err != nil {
+		panic(err)
+	}
+
+	assert.Equal(t, 4, len(gexfDocument.Graph.Nodes))
+	assert.Equal(t, 2, len(gexfDocument.Graph.Edges))
+}
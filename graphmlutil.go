@@ -1,9 +1,12 @@
 package main
 
 import (
+	"compress/gzip"
 	"encoding/xml"
 	"fmt"
+	"io"
 	"os"
+	"strings"
 
 	"github.com/freddy33/graphml"
 	"github.com/sirupsen/logrus"
@@ -39,6 +42,18 @@ const EdgeDescriptionID = "edge-description"
 // EdgeDescriptionName is the name of the GraphML attribute used for the description of edges in the graph
 const EdgeDescriptionName = "description"
 
+// NodeGraphicsID is the ID of the yFiles GraphML attribute used for the shape, size, colour, and label of nodes in yEd
+const NodeGraphicsID = "node-graphics"
+
+// EdgeGraphicsID is the ID of the yFiles GraphML attribute used for the line style and arrowheads of edges in yEd
+const EdgeGraphicsID = "edge-graphics"
+
+// YFilesNamespace is the XML namespace of the yFiles GraphML extension used by yEd
+const YFilesNamespace = "http://www.yworks.com/xml/graphml"
+
+// GraphMLZExt is the file extension of gzip compressed GraphML files
+const GraphMLZExt = ".graphmlz"
+
 // GraphMLUtil provides a number of util methods to create GraphML documents with standardized node and edge data
 type GraphMLUtil struct{}
 
@@ -47,9 +62,10 @@ func NewGraphMLUtil() *GraphMLUtil {
 	return &GraphMLUtil{}
 }
 
-// SaveGraphMLDocument saves the provided graphMLDocument with the specified filename on the file system
+// SaveGraphMLDocument saves the provided graphMLDocument with the specified filename on the file system, files with GraphMLZExt
+// extension are gzip compressed
 func (gu *GraphMLUtil) SaveGraphMLDocument(filename string, graphMLDocument *graphml.Document) error {
-	logrus.Infof("Saving GraphML to file [%s]", filename)
+	logrus.WithField("filename", filename).Info("Saving GraphML to file")
 
 	file, fileErr := os.Create(filename)
 	defer file.Close()
@@ -57,7 +73,9 @@ func (gu *GraphMLUtil) SaveGraphMLDocument(filename string, graphMLDocument *gra
 		return fmt.Errorf("Failed to create GraphML document file [%s]: %w", filename, fileErr)
 	}
 
-	encodeErr := graphml.Encode(file, graphMLDocument)
+	encodeErr := WriteGraphMLFile(filename, file, func(writer io.Writer) error {
+		return gu.EncodeGraphMLDocument(writer, graphMLDocument)
+	})
 	if encodeErr != nil {
 		return fmt.Errorf("Failed to encode GraphML document to file [%s]: %w", filename, encodeErr)
 	}
@@ -65,6 +83,79 @@ func (gu *GraphMLUtil) SaveGraphMLDocument(filename string, graphMLDocument *gra
 	return nil
 }
 
+// WriteGraphMLFile writes the GraphML document with the write function to the file with the specified filename, the GraphML
+// document is gzip compressed if the filename has GraphMLZExt extension
+func WriteGraphMLFile(filename string, file io.Writer, write func(writer io.Writer) error) error {
+	if !strings.HasSuffix(filename, GraphMLZExt) {
+		return write(file)
+	}
+
+	gzipWriter := gzip.NewWriter(file)
+	writeErr := write(gzipWriter)
+	if writeErr != nil {
+		return writeErr
+	}
+
+	closeErr := gzipWriter.Close()
+	if closeErr != nil {
+		return fmt.Errorf("Failed to compress GraphML document: %w", closeErr)
+	}
+
+	return nil
+}
+
+// EncodeGraphMLDocument writes the provided graphMLDocument to the writer
+func (gu *GraphMLUtil) EncodeGraphMLDocument(writer io.Writer, graphMLDocument *graphml.Document) error {
+	return graphml.Encode(writer, graphMLDocument)
+}
+
+// LoadGraphMLDocument loads the GraphML document with the specified filename from the file system, files with GraphMLZExt
+// extension are decompressed
+func (gu *GraphMLUtil) LoadGraphMLDocument(filename string) (*graphml.Document, error) {
+	logrus.WithField("filename", filename).Info("Loading GraphML from file")
+
+	file, fileErr := os.Open(filename)
+	if fileErr != nil {
+		return nil, fmt.Errorf("Failed to open GraphML document file [%s]: %w", filename, fileErr)
+	}
+	defer file.Close()
+
+	var reader io.Reader = file
+	if strings.HasSuffix(filename, GraphMLZExt) {
+		gzipReader, gzipErr := gzip.NewReader(file)
+		if gzipErr != nil {
+			return nil, fmt.Errorf("Failed to decompress GraphML document file [%s]: %w", filename, gzipErr)
+		}
+		defer gzipReader.Close()
+		reader = gzipReader
+	}
+
+	graphMLDocument, decodeErr := graphml.Decode(reader)
+	if decodeErr != nil {
+		return nil, fmt.Errorf("Failed to decode GraphML document from file [%s]: %w", filename, decodeErr)
+	}
+
+	return graphMLDocument, nil
+}
+
+// GetDataValue returns the text value of the GraphML attribute with the specified key ID, returns false if the attribute is missing
+func (gu *GraphMLUtil) GetDataValue(data []graphml.Data, keyID string) (string, bool) {
+	for _, dataElement := range data {
+		if dataElement.Key == keyID {
+			value := strings.Builder{}
+			for _, token := range dataElement.Data {
+				if charData, isCharData := token.(xml.CharData); isCharData {
+					value.Write(charData)
+				}
+			}
+
+			return value.String(), true
+		}
+	}
+
+	return "", false
+}
+
 // CreateGraphMLDocument creates the GraphML document based on the supplied GraphML graphs with the standardised GraphML attributes definition
 func (gu *GraphMLUtil) CreateGraphMLDocument(graphs []graphml.Graph) *graphml.Document {
 	return &graphml.Document{
@@ -115,3 +206,64 @@ func (gu *GraphMLUtil) CreateEdge(edgeID, sourceNodeID, targetNodeID, edgeLabel,
 		Source: sourceNodeID,
 		Target: targetNodeID}
 }
+
+// AddYFilesKeys adds the yFiles namespace and the GraphML attribute definitions for node and edge graphics to the GraphML document
+func (gu *GraphMLUtil) AddYFilesKeys(graphMLDocument *graphml.Document) {
+	graphMLDocument.Attrs = append(graphMLDocument.Attrs, xml.Attr{Name: xml.Name{Local: "xmlns:y"}, Value: YFilesNamespace})
+	for _, key := range []graphml.Key{graphml.NewKey(graphml.KindNode, NodeGraphicsID, "", ""), graphml.NewKey(graphml.KindEdge, EdgeGraphicsID, "", "")} {
+		key.Unrecognized = []xml.Attr{{Name: xml.Name{Local: "yfiles.type"}, Value: string(key.For) + "graphics"}}
+		graphMLDocument.Keys = append(graphMLDocument.Keys, key)
+	}
+}
+
+// CreateNodeGraphics creates the yFiles GraphML attribute for an ellipse node with the specified center, size, fill colour, and label
+func (gu *GraphMLUtil) CreateNodeGraphics(nodeLabel string, nodeX, nodeY, nodeSize float64, fillColor string) graphml.Data {
+	size := fmt.Sprintf("%.1f", nodeSize)
+	x := fmt.Sprintf("%.1f", nodeX-nodeSize/2)
+	y := fmt.Sprintf("%.1f", nodeY-nodeSize/2)
+	tokens := []xml.Token{}
+	tokens = append(tokens, gu.yStart("ShapeNode"))
+	tokens = append(tokens, gu.yElement("Geometry", "height", size, "width", size, "x", x, "y", y)...)
+	tokens = append(tokens, gu.yElement("Fill", "color", fillColor, "transparent", "false")...)
+	tokens = append(tokens, gu.yElement("BorderStyle", "hasColor", "false", "raised", "false", "type", "line", "width", "1.0")...)
+	tokens = append(tokens, gu.yStart("NodeLabel", "alignment", "center", "autoSizePolicy", "content", "fontFamily", "Dialog", "fontSize", "10", "fontStyle", "plain",
+		"hasBackgroundColor", "false", "hasLineColor", "false", "modelName", "sides", "modelPosition", "s", "textColor", "#000000", "visible", "true"))
+	tokens = append(tokens, xml.CharData(nodeLabel), gu.yStart("NodeLabel").End())
+	tokens = append(tokens, gu.yElement("Shape", "type", "ellipse")...)
+	tokens = append(tokens, gu.yStart("ShapeNode").End())
+
+	return graphml.Data{Key: NodeGraphicsID, Data: tokens}
+}
+
+// CreateEdgeGraphics creates the yFiles GraphML attribute for a straight edge with an arrowhead at the target node if directed
+func (gu *GraphMLUtil) CreateEdgeGraphics(directed bool) graphml.Data {
+	targetArrow := "none"
+	if directed {
+		targetArrow = "standard"
+	}
+
+	tokens := []xml.Token{}
+	tokens = append(tokens, gu.yStart("PolyLineEdge"))
+	tokens = append(tokens, gu.yElement("LineStyle", "color", "#808080", "type", "line", "width", "1.0")...)
+	tokens = append(tokens, gu.yElement("Arrows", "source", "none", "target", targetArrow)...)
+	tokens = append(tokens, gu.yElement("BendStyle", "smoothed", "false")...)
+	tokens = append(tokens, gu.yStart("PolyLineEdge").End())
+
+	return graphml.Data{Key: EdgeGraphicsID, Data: tokens}
+}
+
+// yStart creates the start element of the yFiles element with the specified name and attribute name value pairs
+func (gu *GraphMLUtil) yStart(name string, attributes ...string) xml.StartElement {
+	startElement := xml.StartElement{Name: xml.Name{Local: "y:" + name}}
+	for index := 0; index+1 < len(attributes); index += 2 {
+		startElement.Attr = append(startElement.Attr, xml.Attr{Name: xml.Name{Local: attributes[index]}, Value: attributes[index+1]})
+	}
+
+	return startElement
+}
+
+// yElement creates the start and end element of the empty yFiles element with the specified name and attribute name value pairs
+func (gu *GraphMLUtil) yElement(name string, attributes ...string) []xml.Token {
+	startElement := gu.yStart(name, attributes...)
+	return []xml.Token{startElement, startElement.End()}
+}
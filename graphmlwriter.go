@@ -0,0 +1,135 @@
+package main
+
+import (
+	"encoding/xml"
+	"io"
+
+	"github.com/freddy33/graphml"
+)
+
+// GraphMLWriter writes a GraphML document element by element to a writer without keeping the nodes and edges in memory, the
+// output is identical to the output of graphml.Encode for the same GraphML document
+type GraphMLWriter struct {
+	encoder         *xml.Encoder
+	graphMLDocument *graphml.Document
+	err             error
+}
+
+// NewGraphMLWriter creates a new instance of GraphMLWriter writing to the writer
+func NewGraphMLWriter(writer io.Writer) *GraphMLWriter {
+	return &GraphMLWriter{encoder: xml.NewEncoder(writer)}
+}
+
+// WriteDocumentStart writes the XML declaration, the graphml start element, and the GraphML attribute definitions of the GraphML
+// document, the graphs of the GraphML document are ignored
+func (gw *GraphMLWriter) WriteDocumentStart(graphMLDocument *graphml.Document) error {
+	gw.graphMLDocument = graphMLDocument
+	gw.token(graphMLDocument.Instr)
+	gw.token(xml.StartElement{Name: xml.Name{Local: "graphml"}, Attr: graphMLDocument.Attrs})
+	for _, key := range graphMLDocument.Keys {
+		gw.startEnd("key", gw.keyAttrs(key))
+	}
+
+	return gw.err
+}
+
+// WriteGraphStart writes the graph start element and the GraphML attributes of the graph, the nodes and edges of the graph are ignored
+func (gw *GraphMLWriter) WriteGraphStart(graph *graphml.Graph) error {
+	attrs := gw.objectAttrs(graph.Object)
+	if graph.EdgeDefault != "" {
+		attrs = append(attrs, xml.Attr{Name: xml.Name{Local: "edgedefault"}, Value: string(graph.EdgeDefault)})
+	}
+
+	gw.token(xml.StartElement{Name: xml.Name{Local: "graph"}, Attr: attrs})
+	gw.writeData(graph.Data)
+	return gw.err
+}
+
+// WriteNode writes the node with its GraphML attributes
+func (gw *GraphMLWriter) WriteNode(node *graphml.Node) error {
+	gw.token(xml.StartElement{Name: xml.Name{Local: "node"}, Attr: gw.objectAttrs(node.Object)})
+	gw.writeData(node.Data)
+	gw.token(xml.EndElement{Name: xml.Name{Local: "node"}})
+	return gw.err
+}
+
+// WriteEdge writes the edge with its GraphML attributes
+func (gw *GraphMLWriter) WriteEdge(edge *graphml.Edge) error {
+	attrs := append(gw.objectAttrs(edge.Object),
+		xml.Attr{Name: xml.Name{Local: "source"}, Value: edge.Source},
+		xml.Attr{Name: xml.Name{Local: "target"}, Value: edge.Target})
+
+	gw.token(xml.StartElement{Name: xml.Name{Local: "edge"}, Attr: attrs})
+	gw.writeData(edge.Data)
+	gw.token(xml.EndElement{Name: xml.Name{Local: "edge"}})
+	return gw.err
+}
+
+// WriteGraphEnd writes the graph end element
+func (gw *GraphMLWriter) WriteGraphEnd() error {
+	gw.token(xml.EndElement{Name: xml.Name{Local: "graph"}})
+	return gw.err
+}
+
+// WriteDocumentEnd writes the GraphML attributes of the GraphML document and the graphml end element and flushes the writer
+func (gw *GraphMLWriter) WriteDocumentEnd() error {
+	if gw.graphMLDocument != nil {
+		gw.writeData(gw.graphMLDocument.Data)
+	}
+
+	gw.token(xml.EndElement{Name: xml.Name{Local: "graphml"}})
+	if gw.err == nil {
+		gw.err = gw.encoder.Flush()
+	}
+
+	return gw.err
+}
+
+// token encodes the XML token unless a previous token failed to encode
+func (gw *GraphMLWriter) token(token xml.Token) {
+	if gw.err == nil {
+		gw.err = gw.encoder.EncodeToken(token)
+	}
+}
+
+// startEnd encodes the start and end element of the empty element with the specified name and attributes
+func (gw *GraphMLWriter) startEnd(name string, attrs []xml.Attr) {
+	startElement := xml.StartElement{Name: xml.Name{Local: name}, Attr: attrs}
+	gw.token(startElement)
+	gw.token(startElement.End())
+}
+
+// writeData encodes the data elements with the GraphML attribute values
+func (gw *GraphMLWriter) writeData(data []graphml.Data) {
+	for _, dataElement := range data {
+		attrs := append([]xml.Attr{{Name: xml.Name{Local: "key"}, Value: dataElement.Key}}, dataElement.Unrecognized...)
+		gw.token(xml.StartElement{Name: xml.Name{Local: "data"}, Attr: attrs})
+		for _, token := range dataElement.Data {
+			gw.token(token)
+		}
+		gw.token(xml.EndElement{Name: xml.Name{Local: "data"}})
+	}
+}
+
+// objectAttrs returns the XML attributes of the GraphML object in the order used by graphml.Encode
+func (gw *GraphMLWriter) objectAttrs(object graphml.Object) []xml.Attr {
+	attrs := []xml.Attr{}
+	if object.ID != "" {
+		attrs = append(attrs, xml.Attr{Name: xml.Name{Local: "id"}, Value: object.ID})
+	}
+
+	return append(attrs, object.Unrecognized...)
+}
+
+// keyAttrs returns the XML attributes of the GraphML attribute definition in the order used by graphml.Encode
+func (gw *GraphMLWriter) keyAttrs(key graphml.Key) []xml.Attr {
+	attrs := append(gw.objectAttrs(key.Object), xml.Attr{Name: xml.Name{Local: "for"}, Value: string(key.For)})
+	if key.Name != "" {
+		attrs = append(attrs, xml.Attr{Name: xml.Name{Local: "attr.name"}, Value: key.Name})
+	}
+	if key.Type != "" {
+		attrs = append(attrs, xml.Attr{Name: xml.Name{Local: "attr.type"}, Value: key.Type})
+	}
+
+	return attrs
+}
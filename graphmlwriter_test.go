@@ -0,0 +1,37 @@
+package main
+
+import (
+	"bytes"
+	"encoding/xml"
+	"testing"
+
+	"github.com/freddy33/graphml"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGraphMLWriter(t *testing.T) {
+	graphMLUtil := GraphMLUtil{}
+	graphMLDocument := CreateTestGraphMLDocument()
+	graphMLUtil.AddYFilesKeys(graphMLDocument)
+	graphMLDocument.Graphs[0].Nodes[0].Data = append(graphMLDocument.Graphs[0].Nodes[0].Data, graphMLUtil.CreateNodeGraphics(NodeALabel, 10, 20, 30, "#FF0000"))
+	graphMLDocument.Graphs[0].Edges[0].Data = append(graphMLDocument.Graphs[0].Edges[0].Data, graphMLUtil.CreateEdgeGraphics(true))
+	graphMLDocument.Graphs[0].Edges[0].Unrecognized = []xml.Attr{{Name: xml.Name{Local: "directed"}, Value: "true"}}
+	graphMLDocument.Graphs[0].Data = []graphml.Data{graphml.NewData("graph-data", "Graph <Data>")}
+	graphMLDocument.Data = []graphml.Data{graphml.NewData("document-data", "Document & Data")}
+
+	writtenGraphMLDocument := bytes.Buffer{}
+	graphMLWriter := NewGraphMLWriter(&writtenGraphMLDocument)
+	graphMLWriter.WriteDocumentStart(graphMLDocument)
+	graphMLWriter.WriteGraphStart(&graphMLDocument.Graphs[0])
+	for index := range graphMLDocument.Graphs[0].Nodes {
+		graphMLWriter.WriteNode(&graphMLDocument.Graphs[0].Nodes[index])
+	}
+	for index := range graphMLDocument.Graphs[0].Edges {
+		graphMLWriter.WriteEdge(&graphMLDocument.Graphs[0].Edges[index])
+	}
+	graphMLWriter.WriteGraphEnd()
+	err := graphMLWriter.WriteDocumentEnd()
+
+	assert.Nil(t, err)
+	assert.Equal(t, EncodeGraphMLDocument(graphMLDocument), writtenGraphMLDocument.String())
+}
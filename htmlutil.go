@@ -0,0 +1,122 @@
+package main
+
+import (
+	_ "embed" // embeds the HTML viewer template
+	"fmt"
+	"html/template"
+	"io"
+	"sort"
+
+	"github.com/sirupsen/logrus"
+)
+
+//go:embed notegraph-viewer.html
+var htmlViewerTemplate string
+
+// HTMLGraph is the graph data embedded into the HTML viewer
+type HTMLGraph struct {
+	Title string     `json:"title"`
+	Nodes []HTMLNode `json:"nodes"`
+	Links []HTMLLink `json:"links"`
+}
+
+// HTMLNode is a node of the HTML viewer with the number of backlinks (other Notes with valid NoteLinks to the Note)
+type HTMLNode struct {
+	ID          string            `json:"id"`
+	Label       string            `json:"label"`
+	Description string            `json:"description"`
+	URL         string            `json:"url"`
+	Notebook    string            `json:"notebook,omitempty"`
+	Backlinks   int               `json:"backlinks"`
+	Attributes  map[string]string `json:"attributes,omitempty"`
+}
+
+// HTMLLink is a link of the HTML viewer between the nodes with the source and target ID
+type HTMLLink struct {
+	Source string `json:"source"`
+	Target string `json:"target"`
+	Label  string `json:"label"`
+}
+
+// HTMLUtil converts a NoteGraph to a single self-contained HTML file with an interactive force-directed graph viewer
+type HTMLUtil struct {
+	ExportAttributes
+}
+
+// NewHTMLUtil creates a new instance of HTMLUtil
+func NewHTMLUtil() *HTMLUtil {
+	return &HTMLUtil{}
+}
+
+// ExportNoteGraph converts the NoteGraph into an HTMLGraph and writes the HTML viewer with the embedded HTMLGraph to the writer
+func (hu *HTMLUtil) ExportNoteGraph(noteGraph *NoteGraph, allNotes bool, writer io.Writer) error {
+	viewerTemplate, parseErr := template.New("viewer").Parse(htmlViewerTemplate)
+	if parseErr != nil {
+		return fmt.Errorf("Failed to parse HTML viewer template: %w", parseErr)
+	}
+
+	executeErr := viewerTemplate.Execute(writer, hu.ConvertNoteGraph(noteGraph, allNotes))
+	if executeErr != nil {
+		return fmt.Errorf("Failed to write HTML viewer: %w", executeErr)
+	}
+
+	return nil
+}
+
+// ConvertNoteGraph converts the NoteGraph into an HTMLGraph with nodes ordered by GUID
+func (hu *HTMLUtil) ConvertNoteGraph(noteGraph *NoteGraph, allNotes bool) *HTMLGraph {
+	notes := *noteGraph.GetLinkedNotes()
+	if allNotes {
+		notes = *noteGraph.GetNotes()
+	}
+
+	sort.Slice(notes, func(i, j int) bool {
+		return notes[i].GUID < notes[j].GUID
+	})
+
+	noteLinks := *noteGraph.GetValidNoteLinks()
+
+	logrus.Infof("Converting NoteGraph with [%d] Notes and [%d] NoteLinks to HTML", len(notes), len(noteLinks))
+
+	return &HTMLGraph{Title: NoteGraphID, Nodes: hu.CreateNodes(notes, noteLinks), Links: hu.CreateLinks(noteLinks)}
+}
+
+// CreateNodes creates the HTMLNodes from the Notes counting the other Notes linking to each Note
+func (hu *HTMLUtil) CreateNodes(notes []Note, noteLinks []NoteLink) []HTMLNode {
+	backlinks := map[string]map[string]bool{}
+	for _, noteLink := range noteLinks {
+		if noteLink.SourceNoteGUID != noteLink.TargetNoteGUID {
+			if backlinks[noteLink.TargetNoteGUID] == nil {
+				backlinks[noteLink.TargetNoteGUID] = map[string]bool{}
+			}
+			backlinks[noteLink.TargetNoteGUID][noteLink.SourceNoteGUID] = true
+		}
+	}
+
+	nodes := []HTMLNode{}
+	for _, note := range notes {
+		node := HTMLNode{ID: note.GUID, Label: note.Title, Description: note.Description, URL: note.URL.String(), Notebook: note.Notebook, Backlinks: len(backlinks[note.GUID])}
+		for _, noteAttribute := range hu.NoteAttributes {
+			if value, found := noteAttribute.Values[note.GUID]; found {
+				if node.Attributes == nil {
+					node.Attributes = map[string]string{}
+				}
+				node.Attributes[noteAttribute.Name] = value
+			}
+		}
+
+		nodes = append(nodes, node)
+	}
+
+	return nodes
+}
+
+// CreateLinks creates the HTMLLinks from the NoteLinks
+func (hu *HTMLUtil) CreateLinks(noteLinks []NoteLink) []HTMLLink {
+	links := []HTMLLink{}
+	for _, noteLink := range noteLinks {
+		links = append(links, HTMLLink{Source: noteLink.SourceNoteGUID, Target: noteLink.TargetNoteGUID, Label: noteLink.Text})
+	}
+
+	return links
+}
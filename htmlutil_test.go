@@ -0,0 +1,45 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConvertNoteGraphToHTMLGraph(t *testing.T) {
+	htmlUtil := NewHTMLUtil()
+	htmlUtil.AddNoteAttributes(NoteAttribute{ID: NodeCommunityID, Name: NodeCommunityName, Type: "int", Values: map[string]string{"B": "0"}})
+	htmlGraph := htmlUtil.ConvertNoteGraph(CreateHygieneTestNoteGraph(), true)
+
+	assert.Equal(t, 5, len(htmlGraph.Nodes))
+	assert.Equal(t, 5, len(htmlGraph.Links))
+	assert.Equal(t, HTMLNode{ID: "B", Label: "TitleB", Backlinks: 1, Attributes: map[string]string{NodeCommunityName: "0"}}, htmlGraph.Nodes[1])
+	assert.Equal(t, 0, htmlGraph.Nodes[0].Backlinks)
+	assert.Equal(t, 1, htmlGraph.Nodes[2].Backlinks)
+}
+
+func TestExportNoteGraphToHTML(t *testing.T) {
+	noteGraph := NewNoteGraph()
+	noteGraph.Add(Note{GUID: "A", Title: "</script><b>A</b>", URL: *CreateWebLinkURL("A")}, []NoteLink{{SourceNoteGUID: "A", TargetNoteGUID: "B"}})
+	noteGraph.Add(Note{GUID: "B", Title: "TitleB", URL: *CreateWebLinkURL("B")}, []NoteLink{})
+
+	buffer := bytes.Buffer{}
+	err := NewHTMLUtil().ExportNoteGraph(noteGraph, true, &buffer)
+	assert.Nil(t, err)
+
+	html := buffer.String()
+	assert.False(t, strings.Contains(html, "<script src"))
+	assert.False(t, strings.Contains(html, "</script><b>"))
+	assert.Equal(t, 1, strings.Count(html, "</script>"))
+
+	graphJSON := regexp.MustCompile(`var graph = (.*);`).FindStringSubmatch(html)[1]
+	htmlGraph := HTMLGraph{}
+	assert.Nil(t, json.Unmarshal([]byte(graphJSON), &htmlGraph))
+	assert.Equal(t, "</script><b>A</b>", htmlGraph.Nodes[0].Label)
+	assert.Equal(t, CreateWebLinkURL("A").String(), htmlGraph.Nodes[0].URL)
+	assert.Equal(t, []HTMLLink{{Source: "A", Target: "B"}}, htmlGraph.Links)
+}
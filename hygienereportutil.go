@@ -0,0 +1,207 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultFanOutThreshold specifies the default number of outgoing NoteLinks from which a Note is reported as high fan-out Note
+const DefaultFanOutThreshold = 20
+
+// DefaultFanInThreshold specifies the default number of incoming NoteLinks from which a Note is reported as hub Note
+const DefaultFanInThreshold = 20
+
+// HygieneThresholds contains the thresholds used to detect Notes with a very high number of NoteLinks
+type HygieneThresholds struct {
+	FanOut int `json:"fanOut"`
+	FanIn  int `json:"fanIn"`
+}
+
+// ReportNote is a Note listed in the HygieneReport
+type ReportNote struct {
+	GUID      string `json:"guid"`
+	Title     string `json:"title"`
+	URL       string `json:"url"`
+	InDegree  int    `json:"inDegree"`
+	OutDegree int    `json:"outDegree"`
+}
+
+// ReportNoteLink is a NoteLink, or a number of identical NoteLinks, listed in the HygieneReport
+type ReportNoteLink struct {
+	SourceNoteGUID  string `json:"sourceNoteGuid"`
+	SourceNoteTitle string `json:"sourceNoteTitle"`
+	TargetNoteGUID  string `json:"targetNoteGuid"`
+	TargetNoteTitle string `json:"targetNoteTitle"`
+	Count           int    `json:"count"`
+}
+
+// HygieneReport lists Notes and NoteLinks of the NoteGraph which may need attention when maintaining the Evernote account
+// Only valid NoteLinks are considered, self-links do not count towards the incoming and outgoing NoteLinks of a Note
+type HygieneReport struct {
+	Thresholds        HygieneThresholds `json:"thresholds"`
+	OrphanNotes       []ReportNote      `json:"orphanNotes"`       // neither incoming nor outgoing NoteLinks
+	DeadEndNotes      []ReportNote      `json:"deadEndNotes"`      // incoming NoteLinks only
+	OutgoingOnlyNotes []ReportNote      `json:"outgoingOnlyNotes"` // outgoing NoteLinks only
+	HighFanOutNotes   []ReportNote      `json:"highFanOutNotes"`   // outgoing NoteLinks at or above the fan-out threshold
+	HubNotes          []ReportNote      `json:"hubNotes"`          // incoming NoteLinks at or above the fan-in threshold
+	SelfLinks         []ReportNoteLink  `json:"selfLinks"`
+	DuplicateLinks    []ReportNoteLink  `json:"duplicateLinks"` // more than one NoteLink between the same source and target Note
+}
+
+// HygieneReportUtil creates, prints, and saves HygieneReports
+type HygieneReportUtil struct {
+	Thresholds HygieneThresholds
+}
+
+// NewHygieneReportUtil creates a new instance of HygieneReportUtil
+func NewHygieneReportUtil(thresholds HygieneThresholds) *HygieneReportUtil {
+	return &HygieneReportUtil{Thresholds: thresholds}
+}
+
+// CreateHygieneReport creates the HygieneReport for the NoteGraph
+func (hru *HygieneReportUtil) CreateHygieneReport(noteGraph *NoteGraph) *HygieneReport {
+	inDegrees := map[string]int{}
+	outDegrees := map[string]int{}
+	noteLinkCounts := map[[2]string]int{}
+	for _, noteLink := range *noteGraph.GetValidNoteLinks() {
+		noteLinkCounts[[2]string{noteLink.SourceNoteGUID, noteLink.TargetNoteGUID}]++
+		if noteLink.SourceNoteGUID != noteLink.TargetNoteGUID {
+			outDegrees[noteLink.SourceNoteGUID]++
+			inDegrees[noteLink.TargetNoteGUID]++
+		}
+	}
+
+	hygieneReport := &HygieneReport{
+		Thresholds:        hru.Thresholds,
+		OrphanNotes:       []ReportNote{},
+		DeadEndNotes:      []ReportNote{},
+		OutgoingOnlyNotes: []ReportNote{},
+		HighFanOutNotes:   []ReportNote{},
+		HubNotes:          []ReportNote{},
+		SelfLinks:         []ReportNoteLink{},
+		DuplicateLinks:    []ReportNoteLink{}}
+
+	for _, note := range *noteGraph.GetNotes() {
+		reportNote := ReportNote{GUID: note.GUID, Title: note.Title, URL: note.URL.String(), InDegree: inDegrees[note.GUID], OutDegree: outDegrees[note.GUID]}
+		if reportNote.InDegree == 0 && reportNote.OutDegree == 0 {
+			hygieneReport.OrphanNotes = append(hygieneReport.OrphanNotes, reportNote)
+		} else if reportNote.OutDegree == 0 {
+			hygieneReport.DeadEndNotes = append(hygieneReport.DeadEndNotes, reportNote)
+		} else if reportNote.InDegree == 0 {
+			hygieneReport.OutgoingOnlyNotes = append(hygieneReport.OutgoingOnlyNotes, reportNote)
+		}
+
+		if reportNote.OutDegree > 0 && reportNote.OutDegree >= hru.Thresholds.FanOut {
+			hygieneReport.HighFanOutNotes = append(hygieneReport.HighFanOutNotes, reportNote)
+		}
+
+		if reportNote.InDegree > 0 && reportNote.InDegree >= hru.Thresholds.FanIn {
+			hygieneReport.HubNotes = append(hygieneReport.HubNotes, reportNote)
+		}
+	}
+
+	for noteGUIDs, count := range noteLinkCounts {
+		reportNoteLink := ReportNoteLink{
+			SourceNoteGUID:  noteGUIDs[0],
+			SourceNoteTitle: noteGraph.Notes[noteGUIDs[0]].Title,
+			TargetNoteGUID:  noteGUIDs[1],
+			TargetNoteTitle: noteGraph.Notes[noteGUIDs[1]].Title,
+			Count:           count}
+
+		if noteGUIDs[0] == noteGUIDs[1] {
+			hygieneReport.SelfLinks = append(hygieneReport.SelfLinks, reportNoteLink)
+		}
+
+		if count > 1 {
+			hygieneReport.DuplicateLinks = append(hygieneReport.DuplicateLinks, reportNoteLink)
+		}
+	}
+
+	hru.SortReportNotes(hygieneReport.OrphanNotes)
+	hru.SortReportNotes(hygieneReport.DeadEndNotes)
+	hru.SortReportNotes(hygieneReport.OutgoingOnlyNotes)
+	hru.SortReportNotes(hygieneReport.HighFanOutNotes)
+	hru.SortReportNotes(hygieneReport.HubNotes)
+	hru.SortReportNoteLinks(hygieneReport.SelfLinks)
+	hru.SortReportNoteLinks(hygieneReport.DuplicateLinks)
+
+	return hygieneReport
+}
+
+// SortReportNotes sorts ReportNotes by title and GUID
+func (hru *HygieneReportUtil) SortReportNotes(reportNotes []ReportNote) {
+	sort.Slice(reportNotes, func(i, j int) bool {
+		if reportNotes[i].Title != reportNotes[j].Title {
+			return reportNotes[i].Title < reportNotes[j].Title
+		}
+
+		return reportNotes[i].GUID < reportNotes[j].GUID
+	})
+}
+
+// SortReportNoteLinks sorts ReportNoteLinks by source Note title and target Note title
+func (hru *HygieneReportUtil) SortReportNoteLinks(reportNoteLinks []ReportNoteLink) {
+	sort.Slice(reportNoteLinks, func(i, j int) bool {
+		if reportNoteLinks[i].SourceNoteTitle != reportNoteLinks[j].SourceNoteTitle {
+			return reportNoteLinks[i].SourceNoteTitle < reportNoteLinks[j].SourceNoteTitle
+		} else if reportNoteLinks[i].SourceNoteGUID != reportNoteLinks[j].SourceNoteGUID {
+			return reportNoteLinks[i].SourceNoteGUID < reportNoteLinks[j].SourceNoteGUID
+		} else if reportNoteLinks[i].TargetNoteTitle != reportNoteLinks[j].TargetNoteTitle {
+			return reportNoteLinks[i].TargetNoteTitle < reportNoteLinks[j].TargetNoteTitle
+		}
+
+		return reportNoteLinks[i].TargetNoteGUID < reportNoteLinks[j].TargetNoteGUID
+	})
+}
+
+// PrintHygieneReport prints the HygieneReport
+func (hru *HygieneReportUtil) PrintHygieneReport(hygieneReport *HygieneReport) {
+	Reportf("NoteGraph Hygiene Report")
+	hru.PrintReportNotes("Orphan Notes (no NoteLinks)", hygieneReport.OrphanNotes)
+	hru.PrintReportNotes("Dead-end Notes (incoming NoteLinks only)", hygieneReport.DeadEndNotes)
+	hru.PrintReportNotes("Notes with outgoing NoteLinks only", hygieneReport.OutgoingOnlyNotes)
+	hru.PrintReportNotes(fmt.Sprintf("High fan-out Notes (%d or more outgoing NoteLinks)", hygieneReport.Thresholds.FanOut), hygieneReport.HighFanOutNotes)
+	hru.PrintReportNotes(fmt.Sprintf("Hub Notes (%d or more incoming NoteLinks)", hygieneReport.Thresholds.FanIn), hygieneReport.HubNotes)
+	hru.PrintReportNoteLinks("Self-links", hygieneReport.SelfLinks)
+	hru.PrintReportNoteLinks("Duplicate NoteLinks", hygieneReport.DuplicateLinks)
+}
+
+// PrintReportNotes prints the title and the ReportNotes
+func (hru *HygieneReportUtil) PrintReportNotes(title string, reportNotes []ReportNote) {
+	Reportf("   %s: %d", title, len(reportNotes))
+	for _, reportNote := range reportNotes {
+		Reportf("      Note [%s] with GUID [%s] has [%d] incoming and [%d] outgoing NoteLinks", reportNote.Title, reportNote.GUID, reportNote.InDegree, reportNote.OutDegree)
+	}
+}
+
+// PrintReportNoteLinks prints the title and the ReportNoteLinks
+func (hru *HygieneReportUtil) PrintReportNoteLinks(title string, reportNoteLinks []ReportNoteLink) {
+	Reportf("   %s: %d", title, len(reportNoteLinks))
+	for _, reportNoteLink := range reportNoteLinks {
+		Reportf("      [%d] NoteLinks from Note [%s] to Note [%s]", reportNoteLink.Count, reportNoteLink.SourceNoteTitle, reportNoteLink.TargetNoteTitle)
+	}
+}
+
+// SaveHygieneReport saves the HygieneReport as JSON document with the specified filename on the file system
+func (hru *HygieneReportUtil) SaveHygieneReport(filename string, hygieneReport *HygieneReport) error {
+	logrus.WithField("filename", filename).Info("Saving hygiene report to file")
+
+	file, fileErr := os.Create(filename)
+	if fileErr != nil {
+		return fmt.Errorf("Failed to create hygiene report file [%s]: %w", filename, fileErr)
+	}
+	defer file.Close()
+
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	encodeErr := encoder.Encode(hygieneReport)
+	if encodeErr != nil {
+		return fmt.Errorf("Failed to encode hygiene report to file [%s]: %w", filename, encodeErr)
+	}
+
+	return nil
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func CreateHygieneTestNoteGraph() *NoteGraph {
+	// Note A links to B twice and to C, B links to itself, C links to D, D has a broken NoteLink, E is not linked
+	noteGraph := NewNoteGraph()
+	noteGraph.Add(Note{GUID: "A", Title: "TitleA"}, []NoteLink{{SourceNoteGUID: "A", TargetNoteGUID: "B"}, {SourceNoteGUID: "A", TargetNoteGUID: "B"}, {SourceNoteGUID: "A", TargetNoteGUID: "C"}})
+	noteGraph.Add(Note{GUID: "B", Title: "TitleB"}, []NoteLink{{SourceNoteGUID: "B", TargetNoteGUID: "B"}})
+	noteGraph.Add(Note{GUID: "C", Title: "TitleC"}, []NoteLink{{SourceNoteGUID: "C", TargetNoteGUID: "D"}})
+	noteGraph.Add(Note{GUID: "D", Title: "TitleD"}, []NoteLink{{SourceNoteGUID: "D", TargetNoteGUID: "X"}})
+	noteGraph.Add(Note{GUID: "E", Title: "TitleE"}, []NoteLink{})
+	return noteGraph
+}
+
+func TestCreateHygieneReport(t *testing.T) {
+	hygieneReport := NewHygieneReportUtil(HygieneThresholds{FanOut: 3, FanIn: 2}).CreateHygieneReport(CreateHygieneTestNoteGraph())
+
+	assert.Equal(t, []ReportNote{{GUID: "E", Title: "TitleE", InDegree: 0, OutDegree: 0}}, hygieneReport.OrphanNotes)
+	assert.Equal(t, []ReportNote{{GUID: "B", Title: "TitleB", InDegree: 2, OutDegree: 0}, {GUID: "D", Title: "TitleD", InDegree: 1, OutDegree: 0}}, hygieneReport.DeadEndNotes)
+	assert.Equal(t, []ReportNote{{GUID: "A", Title: "TitleA", InDegree: 0, OutDegree: 3}}, hygieneReport.OutgoingOnlyNotes)
+	assert.Equal(t, []ReportNote{{GUID: "A", Title: "TitleA", InDegree: 0, OutDegree: 3}}, hygieneReport.HighFanOutNotes)
+	assert.Equal(t, []ReportNote{{GUID: "B", Title: "TitleB", InDegree: 2, OutDegree: 0}}, hygieneReport.HubNotes)
+	assert.Equal(t, []ReportNoteLink{{SourceNoteGUID: "B", SourceNoteTitle: "TitleB", TargetNoteGUID: "B", TargetNoteTitle: "TitleB", Count: 1}}, hygieneReport.SelfLinks)
+	assert.Equal(t, []ReportNoteLink{{SourceNoteGUID: "A", SourceNoteTitle: "TitleA", TargetNoteGUID: "B", TargetNoteTitle: "TitleB", Count: 2}}, hygieneReport.DuplicateLinks)
+}
+
+func TestCreateHygieneReportWithDefaultThresholds(t *testing.T) {
+	hygieneReport := NewHygieneReportUtil(HygieneThresholds{FanOut: DefaultFanOutThreshold, FanIn: DefaultFanInThreshold}).CreateHygieneReport(CreateHygieneTestNoteGraph())
+
+	assert.Empty(t, hygieneReport.HighFanOutNotes)
+	assert.Empty(t, hygieneReport.HubNotes)
+}
+
+func TestSaveHygieneReport(t *testing.T) {
+	testReportFile := filepath.Join(os.TempDir(), "testHygieneReport.json")
+	defer os.Remove(testReportFile)
+
+	hygieneReportUtil := NewHygieneReportUtil(HygieneThresholds{FanOut: 3, FanIn: 2})
+	hygieneReport := hygieneReportUtil.CreateHygieneReport(CreateHygieneTestNoteGraph())
+	err := hygieneReportUtil.SaveHygieneReport(testReportFile, hygieneReport)
+	if err != nil {
+		panic(err)
+	}
+
+	content, err := ioutil.ReadFile(testReportFile)
+	if err != nil {
+		panic(err)
+	}
+
+	savedHygieneReport := &HygieneReport{}
+	err = json.Unmarshal(content, savedHygieneReport)
+	if err != nil {
+		panic(err)
+	}
+
+	assert.Equal(t, hygieneReport, savedHygieneReport)
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/sirupsen/logrus"
+)
+
+// JGFDocument is the JSON Graph Format (version 2) representation of a single graph
+type JGFDocument struct {
+	Graph JGFGraph `json:"graph"`
+}
+
+// JGFGraph is a directed graph with nodes keyed by ID
+type JGFGraph struct {
+	ID       string             `json:"id"`
+	Label    string             `json:"label"`
+	Directed bool               `json:"directed"`
+	Nodes    map[string]JGFNode `json:"nodes"`
+	Edges    []JGFEdge          `json:"edges"`
+}
+
+// JGFNode is a node with label and metadata
+type JGFNode struct {
+	Label    string                 `json:"label"`
+	Metadata map[string]interface{} `json:"metadata"`
+}
+
+// JGFEdge is a directed edge with label and metadata
+type JGFEdge struct {
+	ID       string                 `json:"id"`
+	Source   string                 `json:"source"`
+	Target   string                 `json:"target"`
+	Directed bool                   `json:"directed"`
+	Label    string                 `json:"label"`
+	Metadata map[string]interface{} `json:"metadata"`
+}
+
+// JGFUtil converts a NoteGraph to JSON Graph Format
+type JGFUtil struct {
+	ExportAttributes
+}
+
+// NewJGFUtil creates a new instance of JGFUtil
+func NewJGFUtil() *JGFUtil {
+	return &JGFUtil{}
+}
+
+// ExportNoteGraph converts the NoteGraph into JSON Graph Format and writes the JSON to the writer
+func (ju *JGFUtil) ExportNoteGraph(noteGraph *NoteGraph, allNotes bool, writer io.Writer) error {
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	encodeErr := encoder.Encode(ju.ConvertNoteGraph(noteGraph, allNotes))
+	if encodeErr != nil {
+		return fmt.Errorf("Failed to encode JSON Graph Format: %w", encodeErr)
+	}
+
+	return nil
+}
+
+// ConvertNoteGraph converts the NoteGraph into a JGFDocument
+func (ju *JGFUtil) ConvertNoteGraph(noteGraph *NoteGraph, allNotes bool) *JGFDocument {
+	notes := *noteGraph.GetLinkedNotes()
+	if allNotes {
+		notes = *noteGraph.GetNotes()
+	}
+
+	noteLinks := *noteGraph.GetValidNoteLinks()
+
+	nodes := ju.CreateNodes(notes)
+	edges := ju.CreateEdges(noteLinks)
+
+	logrus.Infof("Converting NoteGraph with [%d|%d] Notes|nodes and [%d|%d] NoteLinks|edges to JSON Graph Format", len(notes), len(nodes), len(noteLinks), len(edges))
+
+	return &JGFDocument{Graph: JGFGraph{ID: NoteGraphID, Label: NoteGraphID, Directed: true, Nodes: nodes, Edges: edges}}
+}
+
+// CreateNodes creates JGF nodes keyed by Note GUID from the Notes
+func (ju *JGFUtil) CreateNodes(notes []Note) map[string]JGFNode {
+	nodes := map[string]JGFNode{}
+	for _, note := range notes {
+		metadata := map[string]interface{}{
+			NodeDescriptionName: note.Description,
+			NodeURLName:         note.URL.String(),
+			"urlType":           note.URLType.String()}
+		for _, noteAttribute := range ju.NoteAttributes {
+			if value, found := noteAttribute.Values[note.GUID]; found {
+				metadata[noteAttribute.Name] = TypedAttributeValue(noteAttribute.Type, value)
+			}
+		}
+
+		nodes[note.GUID] = JGFNode{Label: note.Title, Metadata: metadata}
+	}
+
+	return nodes
+}
+
+// CreateEdges creates JGF edges from the NoteLinks
+func (ju *JGFUtil) CreateEdges(noteLinks []NoteLink) []JGFEdge {
+	edges := []JGFEdge{}
+	for index, edgeNoteLink := range CanonicalNoteLinks(noteLinks) {
+		edgeID, noteLink := edgeNoteLink.EdgeID, edgeNoteLink.NoteLink
+		metadata := map[string]interface{}{
+			EdgeDescriptionName: noteLink.Text,
+			"urlType":           noteLink.URLType.String()}
+		for _, noteLinkAttribute := range ju.NoteLinkAttributes {
+			if value, found := noteLinkAttribute.Value(edgeID, noteLink); found {
+				metadata[noteLinkAttribute.Name] = TypedAttributeValue(noteLinkAttribute.Type, value)
+			}
+		}
+
+		edges = append(edges, JGFEdge{ID: "edge-" + strconv.Itoa(index), Source: noteLink.SourceNoteGUID, Target: noteLink.TargetNoteGUID, Directed: true, Label: noteLink.Text, Metadata: metadata})
+	}
+
+	return edges
+}
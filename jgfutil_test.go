@@ -0,0 +1,40 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConvertNoteGraphToJGF(t *testing.T) {
+	jgfUtil := NewJGFUtil()
+	jgfUtil.AddNoteLinkAttributes(NoteLinkAttribute{ID: EdgePathID, Name: EdgePathName, Type: "boolean", Values: map[NoteLinkKey]string{{SourceNoteGUID: "A", TargetNoteGUID: "B"}: "true"}})
+	jgfDocument := jgfUtil.ConvertNoteGraph(CreateDOTTestNoteGraph(), false)
+
+	assert.True(t, jgfDocument.Graph.Directed)
+	assert.Len(t, jgfDocument.Graph.Nodes, 4)
+	assert.Equal(t, "Title \"A\"", jgfDocument.Graph.Nodes["A"].Label)
+	assert.Equal(t, "https://www.evernote.com/shard/s1/nl/1/A/", jgfDocument.Graph.Nodes["A"].Metadata[NodeURLName])
+
+	assert.Len(t, jgfDocument.Graph.Edges, 3)
+	assert.Equal(t, JGFEdge{ID: "edge-0", Source: "A", Target: "B", Directed: true, Label: "LinkAB", Metadata: map[string]interface{}{"description": "LinkAB", "urlType": "AppLink", "path": true}}, jgfDocument.Graph.Edges[0])
+}
+
+func TestExportNoteGraphToJGF(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	err := NewJGFUtil().ExportNoteGraph(CreateDOTTestNoteGraph(), true, buffer)
+	if err != nil {
+		panic(err)
+	}
+
+	jgfDocument := &JGFDocument{}
+	err = json.Unmarshal(buffer.Bytes(), jgfDocument)
+	if err != nil {
+		panic(err)
+	}
+
+	assert.Len(t, jgfDocument.Graph.Nodes, 5)
+	assert.Len(t, jgfDocument.Graph.Edges, 3)
+}
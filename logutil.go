@@ -0,0 +1,90 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Enum of all LogFormats
+const (
+	PlainLogFormat  LogFormat = iota // level, message, and fields for reading in a terminal
+	LogfmtLogFormat LogFormat = iota // logfmt key=value pairs with timestamp, level, message, and fields
+	JSONLogFormat   LogFormat = iota // one JSON object per line with timestamp, level, message, and fields
+)
+
+// LogFormat specifies how log entries are formatted
+type LogFormat int
+
+func (lf LogFormat) String() string {
+	return [...]string{"plain", "logfmt", "json"}[lf]
+}
+
+// NewLogFormat creates a LogFormat from the string value
+func NewLogFormat(value string) (*LogFormat, error) {
+	for logFormat := PlainLogFormat; logFormat <= JSONLogFormat; logFormat++ {
+		if value == logFormat.String() {
+			return &logFormat, nil
+		}
+	}
+
+	return nil, errors.New("Invalid LogFormat value [" + value + "]")
+}
+
+// Formatter returns the logrus Formatter of the LogFormat
+func (lf LogFormat) Formatter() logrus.Formatter {
+	switch lf {
+	case LogfmtLogFormat:
+		return &logrus.TextFormatter{DisableColors: true, FullTimestamp: true}
+	case JSONLogFormat:
+		return &logrus.JSONFormatter{}
+	}
+
+	return &PlainFormatter{}
+}
+
+// PlainFormatter is a simple logrus Formatter
+type PlainFormatter struct{}
+
+// Format prints log level and message followed by the fields sorted by key
+func (f *PlainFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	keys := []string{}
+	for key := range entry.Data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var line strings.Builder
+	line.WriteString(fmt.Sprintf("%s\t - %s", strings.ToUpper(entry.Level.String()), entry.Message))
+	for _, key := range keys {
+		line.WriteString(fmt.Sprintf(" %s=%s", key, f.FormatValue(entry.Data[key])))
+	}
+	line.WriteString("\n")
+
+	return []byte(line.String()), nil
+}
+
+// FormatValue formats the field value, values that are empty or contain spaces, quotes, or equal signs are quoted
+func (f *PlainFormatter) FormatValue(value interface{}) string {
+	formattedValue := fmt.Sprint(value)
+	if formattedValue == "" || strings.ContainsAny(formattedValue, " =\"") {
+		return strconv.Quote(formattedValue)
+	}
+
+	return formattedValue
+}
+
+// ReportOutput is the destination of stats, reports, and query results, which are kept apart from log output so that logs can be
+// shipped and parsed separately
+var ReportOutput io.Writer = os.Stdout
+
+// Reportf prints the formatted line to the ReportOutput
+func Reportf(format string, args ...interface{}) {
+	fmt.Fprintf(ReportOutput, format+"\n", args...)
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewLogFormat(t *testing.T) {
+	logFormat, err := NewLogFormat("logfmt")
+	assert.Nil(t, err)
+	assert.Equal(t, LogfmtLogFormat, *logFormat)
+
+	_, err = NewLogFormat("xml")
+	assert.NotNil(t, err)
+}
+
+func CreateLogTestLogger(logFormat LogFormat, output *bytes.Buffer) *logrus.Logger {
+	logger := logrus.New()
+	logger.SetFormatter(logFormat.Formatter())
+	logger.SetOutput(output)
+	return logger
+}
+
+func TestLogFormatter(t *testing.T) {
+	var output bytes.Buffer
+	fields := logrus.Fields{"note_guid": "A", "title": "Title A", "offset": 100}
+
+	CreateLogTestLogger(PlainLogFormat, &output).WithFields(fields).Info("Processing Evernote note")
+	assert.Equal(t, "INFO\t - Processing Evernote note note_guid=A offset=100 title=\"Title A\"\n", output.String())
+
+	output.Reset()
+	CreateLogTestLogger(LogfmtLogFormat, &output).WithFields(fields).Info("Processing Evernote note")
+	assert.Contains(t, output.String(), `level=info msg="Processing Evernote note" note_guid=A offset=100 title="Title A"`)
+
+	output.Reset()
+	CreateLogTestLogger(JSONLogFormat, &output).WithFields(fields).Warn("Processing Evernote note")
+	entry := map[string]interface{}{}
+	assert.Nil(t, json.Unmarshal(output.Bytes(), &entry))
+	assert.Equal(t, "warning", entry["level"])
+	assert.Equal(t, "Processing Evernote note", entry["msg"])
+	assert.Equal(t, "A", entry["note_guid"])
+	assert.Equal(t, float64(100), entry["offset"])
+}
+
+func TestReportf(t *testing.T) {
+	var output bytes.Buffer
+	reportOutput := ReportOutput
+	ReportOutput = &output
+	defer func() { ReportOutput = reportOutput }()
+
+	Reportf("   Notes: %d", 2)
+	assert.Equal(t, "   Notes: 2\n", output.String())
+}
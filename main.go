@@ -1,68 +1,86 @@
 package main
 
 import (
-	"flag"
 	"fmt"
+	"net/http"
 	"os"
 	"strings"
 
+	"github.com/freddy33/graphml"
 	"github.com/sirupsen/logrus"
 )
 
-// ParseArgs parses command line arguments
-func ParseArgs() (string, bool, URLType, bool, string, bool) {
-	edamAuthToken := flag.String("edamAuthToken", "", "Evernote API auth token")
-	sandbox := flag.Bool("sandbox", false, "Use sandbox.evernote.com")
-	noteURL := flag.String("noteURL", "WebLink", "WebLink or AppLink for Note URLs")
-	linkedNotes := flag.Bool("linkedNotes", true, "Include only linked Notes")
-	graphMLFilename := flag.String("graphMLFilename", "notegraph.graphml", "GraphML output filename")
-	verbose := flag.Bool("v", false, "Verbose output")
+// logFile is the log file opened by InitLogger, it stays open until the process exits so that failures of commands are logged
+var logFile *os.File
 
-	flag.Parse()
+// InitLogger initializes the Logrus logger with the LogFormat, logs to stderr or to the log file if specified, and initializes the
+// ReportOutput to stdout or to stderr if the NoteGraph is written to stdout, the warnings of the LogArgs are logged afterwards
+func InitLogger(logArgs LogArgs, reportToStderr bool) error {
+	if logFile != nil {
+		logFile.Close()
+		logFile = nil
+	}
+
+	logrus.SetFormatter(logArgs.LogFormat.Formatter())
+	logrus.SetOutput(os.Stderr)
+	logrus.StandardLogger().ReplaceHooks(logrus.LevelHooks{})
+	if logArgs.LogFilename != "" {
+		file, err := os.OpenFile(logArgs.LogFilename, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+		if err != nil {
+			return NewNoteGraphError(OutputError, err, "Failed to open log file [%s]", logArgs.LogFilename)
+		}
 
-	if *edamAuthToken == "" {
-		flag.Usage()
-		os.Exit(2)
+		logFile = file
+		logrus.SetOutput(logFile)
 	}
 
-	noteURLType, err := NewURLType(*noteURL)
-	if err != nil || (*noteURLType != WebLink && *noteURLType != AppLink) {
-		flag.Usage()
-		os.Exit(2)
+	logrus.SetLevel(logrus.InfoLevel)
+	if logArgs.Verbose {
+		logrus.SetLevel(logrus.DebugLevel)
+	} else if logArgs.Quiet {
+		logrus.SetLevel(logrus.WarnLevel)
 	}
 
-	return *edamAuthToken, *sandbox, *noteURLType, *linkedNotes, *graphMLFilename, *verbose
-}
+	ReportOutput = os.Stdout
+	if reportToStderr {
+		ReportOutput = os.Stderr
+	}
 
-// PlainFormatter is a simple logrus Formatter
-type PlainFormatter struct{}
+	for _, warning := range logArgs.Warnings {
+		logrus.Warn(warning)
+	}
 
-// Format print log level and message only
-func (f *PlainFormatter) Format(entry *logrus.Entry) ([]byte, error) {
-	return []byte(fmt.Sprintf("%s\t - %s\n", strings.ToUpper(entry.Level.String()), entry.Message)), nil
+	return nil
 }
 
-// InitLogger initializes the Logrus logger
-func InitLogger(verbose bool) {
-	logrus.SetFormatter(&PlainFormatter{})
-	logrus.SetOutput(os.Stdout)
+// InitEvernoteClient initializes the EvernoteClient counting retries with the FetchProgress
+func InitEvernoteClient(edamAuthToken string, sandbox bool, progress *FetchProgress) IEvernoteClient {
+	evernoteClient := NewEvernoteClient(edamAuthToken, sandbox)
+	evernoteClient.Progress = progress
+	return evernoteClient
+}
 
-	if verbose {
-		logrus.SetLevel(logrus.DebugLevel)
+// InitFetchProgress initializes the FetchProgress on stderr, counts logged errors, and clears the live bar while log entries are
+// written to stderr, returns nil if no progress is displayed
+func InitFetchProgress(progressMode ProgressMode) *FetchProgress {
+	progress := NewFetchProgress(progressMode, os.Stderr)
+	if progress.Mode == NoProgress {
+		return nil
 	}
-}
 
-// InitEvernoteClient initializes the EvernoteClient
-func InitEvernoteClient(edamAuthToken string, sandbox bool) IEvernoteClient {
-	return NewEvernoteClient(edamAuthToken, sandbox)
+	logrus.AddHook(progress)
+	if progress.Mode == BarProgress && logFile == nil {
+		logrus.SetOutput(progress.Writer(os.Stderr))
+	}
+
+	return progress
 }
 
 // InitNoteLinkParser initializes the NoteLinkParser
-func InitNoteLinkParser(evernoteClient IEvernoteClient) *NoteLinkParser {
+func InitNoteLinkParser(evernoteClient IEvernoteClient) (*NoteLinkParser, error) {
 	user, err := evernoteClient.GetUser()
 	if err != nil {
-		logrus.Errorf("Failed to retrieve user from Evernote API at [%s]: %v", evernoteClient.GetHost(), err)
-		panic(err)
+		return nil, NewNoteGraphError(ErrorKindOf(err), err, "Failed to retrieve user from Evernote API at [%s]", evernoteClient.GetHost())
 	}
 
 	evernoteHost := evernoteClient.GetHost()
@@ -70,46 +88,403 @@ func InitNoteLinkParser(evernoteClient IEvernoteClient) *NoteLinkParser {
 	shardID := user.GetShardId()
 
 	logrus.Infof("Using Evernote API endpoint at [%s] with user [%s]", evernoteClient.GetHost(), user.GetUsername())
-	return NewNoteLinkParser(evernoteHost, userID, shardID)
+	return NewNoteLinkParser(evernoteHost, userID, shardID), nil
 }
 
-// InitEvernoteNoteGraph initializes the EvernoteNoteGraph
-func InitEvernoteNoteGraph(edamAuthToken string, sandbox bool, noteURLType URLType) *EvernoteNoteGraph {
-	evernoteClient := InitEvernoteClient(edamAuthToken, sandbox)
-	noteLinkParser := InitNoteLinkParser(evernoteClient)
-	return NewEvernoteNoteGraph(evernoteClient, noteLinkParser, noteURLType)
+// InitEvernoteNoteGraph initializes the EvernoteNoteGraph displaying its progress with the FetchProgress
+func InitEvernoteNoteGraph(edamAuthToken string, sandbox bool, noteURLType URLType, noteContent bool, progress *FetchProgress) (*EvernoteNoteGraph, error) {
+	evernoteClient := InitEvernoteClient(edamAuthToken, sandbox, progress)
+	noteLinkParser, err := InitNoteLinkParser(evernoteClient)
+	if err != nil {
+		return nil, err
+	}
+
+	evernoteNoteGraph := NewEvernoteNoteGraph(evernoteClient, noteLinkParser, noteURLType)
+	evernoteNoteGraph.Progress = progress
+	evernoteNoteGraph.NoteContent = noteContent
+	return evernoteNoteGraph, nil
 }
 
 // CreateNoteGraph creates the NoteGraph from Evernote notes
-func CreateNoteGraph(evernoteNoteGraph *EvernoteNoteGraph) *NoteGraph {
+func CreateNoteGraph(evernoteNoteGraph *EvernoteNoteGraph) (*NoteGraph, error) {
 	noteGraph, noteGraphErr := evernoteNoteGraph.CreateNoteGraph()
 	if noteGraphErr != nil {
-		logrus.Errorf("Failed to create NoteGraph from Evernote API at [%s]: %v", evernoteNoteGraph.EvernoteClient.GetHost(), noteGraphErr)
-		panic(noteGraphErr)
+		return nil, NewNoteGraphError(ErrorKindOf(noteGraphErr), noteGraphErr, "Failed to create NoteGraph from Evernote API at [%s]", evernoteNoteGraph.EvernoteClient.GetHost())
 	}
 
-	return noteGraph
+	return noteGraph, nil
+}
+
+// AnalyzeNoteGraph detects connected components and communities in the NoteGraph
+func AnalyzeNoteGraph(noteGraph *NoteGraph) *NoteGraphAnalysis {
+	return NewNoteGraphAnalyzer().AnalyzeNoteGraph(noteGraph)
+}
+
+// LayoutNoteGraph computes the force-directed layout of the NoteGraph and returns the NoteAttributes with the x and y coordinates
+func LayoutNoteGraph(noteGraph *NoteGraph, linkedNotes bool, iterations int) []NoteAttribute {
+	noteGraphLayouter := NewNoteGraphLayouter(iterations)
+	return noteGraphLayouter.PositionAttributes(noteGraphLayouter.LayoutNoteGraph(noteGraph, !linkedNotes))
 }
 
-// SaveNoteGraph saves the NoteGraph as GraphML
-func SaveNoteGraph(noteGraph *NoteGraph, linkedNotes bool, graphMLFilename string) {
-	graphMLDocument := NewNoteGraphUtil().ConvertNoteGraph(noteGraph, !linkedNotes)
-	saveGraphMLErr := NewGraphMLUtil().SaveGraphMLDocument(graphMLFilename, graphMLDocument)
-	if saveGraphMLErr != nil {
-		logrus.Errorf("Failed to save NoteGraph to GraphML file [%s]: %v", graphMLFilename, saveGraphMLErr)
-		panic(saveGraphMLErr)
+// ExtractEgoNoteGraph extracts the ego network around the focal Note from the NoteGraph and the NoteAttribute marking the focal Note
+func ExtractEgoNoteGraph(noteGraph *NoteGraph, egoNote string, egoDepth int, egoDirection LinkDirection) (*NoteGraph, NoteAttribute, error) {
+	focalNote, findNoteErr := noteGraph.FindNote(egoNote)
+	if findNoteErr != nil {
+		return nil, NoteAttribute{}, fmt.Errorf("Failed to find focal Note [%s] in NoteGraph: %w", egoNote, findNoteErr)
 	}
+
+	noteGraphQuery := NewNoteGraphQuery()
+	egoNoteGraph, egoNoteGraphErr := noteGraphQuery.EgoNoteGraph(noteGraph, focalNote.GUID, egoDepth, egoDirection)
+	if egoNoteGraphErr != nil {
+		return nil, NoteAttribute{}, fmt.Errorf("Failed to extract ego network around focal Note [%s] from NoteGraph: %w", egoNote, egoNoteGraphErr)
+	}
+
+	return egoNoteGraph, noteGraphQuery.FocalNoteAttribute(egoNoteGraph, focalNote.GUID), nil
 }
 
-func main() {
-	edamAuthToken, sandbox, noteURLType, linkedNotes, graphMLFilename, verbose := ParseArgs()
+// QueryNotePaths prints the shortest directed and undirected paths, and all paths with up to pathMaxLength NoteLinks if pathMaxLength
+// is greater than zero, between two Notes and saves the Notes along the paths as GraphML if a path filename is specified
+func QueryNotePaths(noteGraph *NoteGraph, pathFrom, pathTo string, pathMaxLength int, pathFilename string) error {
+	sourceNote, sourceNoteErr := noteGraph.FindNote(pathFrom)
+	if sourceNoteErr != nil {
+		return fmt.Errorf("Failed to find source Note [%s] in NoteGraph: %w", pathFrom, sourceNoteErr)
+	}
+
+	targetNote, targetNoteErr := noteGraph.FindNote(pathTo)
+	if targetNoteErr != nil {
+		return fmt.Errorf("Failed to find target Note [%s] in NoteGraph: %w", pathTo, targetNoteErr)
+	}
+
+	noteGraphQuery := NewNoteGraphQuery()
+	noteGraphUtil := NewNoteGraphUtil()
+	notePaths := []NotePath{}
+	for _, linkDirection := range []LinkDirection{Outgoing, Both} {
+		shortestPath, shortestPathErr := noteGraphQuery.ShortestPath(noteGraph, sourceNote.GUID, targetNote.GUID, linkDirection)
+		if shortestPathErr != nil {
+			return fmt.Errorf("Failed to find shortest path from Note [%s] to Note [%s]: %w", pathFrom, pathTo, shortestPathErr)
+		}
+
+		shortestPaths := []NotePath{}
+		if shortestPath != nil {
+			shortestPaths = append(shortestPaths, *shortestPath)
+		}
 
-	InitLogger(verbose)
+		title := "Shortest directed path"
+		if linkDirection == Both {
+			title = "Shortest undirected path"
+		}
 
-	evernoteNoteGraph := InitEvernoteNoteGraph(edamAuthToken, sandbox, noteURLType)
-	noteGraph := CreateNoteGraph(evernoteNoteGraph)
-	SaveNoteGraph(noteGraph, linkedNotes, graphMLFilename)
+		noteGraphUtil.PrintNotePaths(noteGraph, title, shortestPaths)
+		notePaths = append(notePaths, shortestPaths...)
+	}
+
+	if pathMaxLength > 0 {
+		allPaths, allPathsErr := noteGraphQuery.AllPaths(noteGraph, sourceNote.GUID, targetNote.GUID, pathMaxLength, Outgoing)
+		if allPathsErr != nil {
+			return fmt.Errorf("Failed to find all paths from Note [%s] to Note [%s]: %w", pathFrom, pathTo, allPathsErr)
+		}
+
+		noteGraphUtil.PrintNotePaths(noteGraph, fmt.Sprintf("All directed paths with up to %d NoteLinks", pathMaxLength), allPaths)
+		notePaths = append(notePaths, allPaths...)
+	}
+
+	if pathFilename != "" {
+		pathNoteGraph, pathNoteLinkAttribute := noteGraphQuery.PathNoteGraph(noteGraph, notePaths)
+		noteGraphUtil.AddNoteLinkAttributes(pathNoteLinkAttribute)
+		graphMLDocument := noteGraphUtil.ConvertNoteGraph(pathNoteGraph, true)
+		saveGraphMLErr := NewGraphMLUtil().SaveGraphMLDocument(pathFilename, graphMLDocument)
+		if saveGraphMLErr != nil {
+			return NewNoteGraphError(OutputError, saveGraphMLErr, "Failed to save paths to GraphML file [%s]", pathFilename)
+		}
+	}
+
+	return nil
+}
+
+// QueryBacklinks prints the Notes linking to the Note
+func QueryBacklinks(noteGraph *NoteGraph, backlinks string) error {
+	note, findNoteErr := noteGraph.FindNote(backlinks)
+	if findNoteErr != nil {
+		return fmt.Errorf("Failed to find Note [%s] in NoteGraph: %w", backlinks, findNoteErr)
+	}
+
+	noteLinks, backlinksErr := NewNoteGraphQuery().Backlinks(noteGraph, note.GUID)
+	if backlinksErr != nil {
+		return fmt.Errorf("Failed to find backlinks to Note [%s]: %w", backlinks, backlinksErr)
+	}
+
+	NewNoteGraphUtil().PrintBacklinks(noteGraph, note.GUID, noteLinks)
+	return nil
+}
+
+// LoadNoteGraph loads the NoteGraph from a GraphML file or NoteGraph snapshot depending on the file extension, the additional
+// node and edge attributes of GraphML files are returned as NoteAttributes and NoteLinkAttributes
+func LoadNoteGraph(filename string) (*NoteGraph, []NoteAttribute, []NoteLinkAttribute, error) {
+	if strings.HasSuffix(filename, graphml.Ext) || strings.HasSuffix(filename, GraphMLZExt) {
+		graphMLDocument, loadGraphMLErr := NewGraphMLUtil().LoadGraphMLDocument(filename)
+		if loadGraphMLErr != nil {
+			return nil, nil, nil, NewNoteGraphError(ParseError, loadGraphMLErr, "Failed to load GraphML file [%s]", filename)
+		}
+
+		noteGraphUtil := NewNoteGraphUtil()
+		noteGraph, convertErr := noteGraphUtil.ConvertGraphMLDocument(graphMLDocument)
+		if convertErr != nil {
+			return nil, nil, nil, NewNoteGraphError(ParseError, convertErr, "Failed to convert GraphML file [%s] to NoteGraph", filename)
+		}
+
+		noteAttributes, noteLinkAttributes := noteGraphUtil.ConvertGraphMLAttributes(graphMLDocument)
+		return noteGraph, noteAttributes, noteLinkAttributes, nil
+	}
+
+	noteGraph, loadSnapshotErr := NewSnapshotUtil().LoadSnapshot(filename)
+	if loadSnapshotErr != nil {
+		return nil, nil, nil, NewNoteGraphError(ParseError, loadSnapshotErr, "Failed to load NoteGraph snapshot file [%s]", filename)
+	}
+
+	return noteGraph, []NoteAttribute{}, []NoteLinkAttribute{}, nil
+}
+
+// LoadFilteredNoteGraph loads the NoteGraph from the NoteGraph snapshot or GraphML file and applies the NoteGraphFilter
+func LoadFilteredNoteGraph(inputArgs InputArgs) (*NoteGraph, []NoteAttribute, []NoteLinkAttribute, error) {
+	noteGraph, noteAttributes, noteLinkAttributes, loadErr := LoadNoteGraph(inputArgs.SnapshotFilename)
+	if loadErr != nil {
+		return nil, nil, nil, loadErr
+	}
+
+	if inputArgs.NoteGraphFilter.NoteExpression != nil || inputArgs.NoteGraphFilter.NoteLinkExpression != nil {
+		noteGraph = inputArgs.NoteGraphFilter.FilterNoteGraph(noteGraph)
+	}
+
+	return noteGraph, noteAttributes, noteLinkAttributes, nil
+}
+
+// DiffNoteGraphs prints the differences between two NoteGraphs and saves the differences as GraphML if a diff filename is specified
+func DiffNoteGraphs(diffFrom, diffTo, diffFilename string) error {
+	previousNoteGraph, _, _, previousErr := LoadNoteGraph(diffFrom)
+	if previousErr != nil {
+		return previousErr
+	}
+
+	currentNoteGraph, _, _, currentErr := LoadNoteGraph(diffTo)
+	if currentErr != nil {
+		return currentErr
+	}
+
+	noteGraphDiffUtil := NewNoteGraphDiffUtil()
+	noteGraphDiff := noteGraphDiffUtil.DiffNoteGraphs(previousNoteGraph, currentNoteGraph)
+	noteGraphDiffUtil.PrintNoteGraphDiff(previousNoteGraph, currentNoteGraph, noteGraphDiff)
+
+	if diffFilename != "" {
+		diffNoteGraph, noteAttribute, noteLinkAttribute := noteGraphDiffUtil.DiffNoteGraph(previousNoteGraph, currentNoteGraph, noteGraphDiff)
+		noteGraphUtil := NewNoteGraphUtil()
+		noteGraphUtil.AddNoteAttributes(noteAttribute)
+		noteGraphUtil.AddNoteLinkAttributes(noteLinkAttribute)
+		graphMLDocument := noteGraphUtil.ConvertNoteGraph(diffNoteGraph, true)
+		saveGraphMLErr := NewGraphMLUtil().SaveGraphMLDocument(diffFilename, graphMLDocument)
+		if saveGraphMLErr != nil {
+			return NewNoteGraphError(OutputError, saveGraphMLErr, "Failed to save NoteGraph diff to GraphML file [%s]", diffFilename)
+		}
+	}
+
+	return nil
+}
+
+// SaveSnapshot saves the NoteGraph as NoteGraph snapshot
+func SaveSnapshot(noteGraph *NoteGraph, snapshotFilename string) error {
+	saveSnapshotErr := NewSnapshotUtil().SaveSnapshot(snapshotFilename, noteGraph)
+	if saveSnapshotErr != nil {
+		return NewNoteGraphError(OutputError, saveSnapshotErr, "Failed to save NoteGraph snapshot to file [%s]", snapshotFilename)
+	}
+
+	return nil
+}
+
+// SaveNoteGraph saves the NoteGraph in the OutputFormat
+func SaveNoteGraph(noteGraph *NoteGraph, noteAttributes []NoteAttribute, noteLinkAttributes []NoteLinkAttribute, linkedNotes bool, outputFormat OutputFormat, exportOptions ExportOptions, outputFilename string) error {
+	noteGraphExporter, exporterErr := NewNoteGraphExporter(outputFormat, exportOptions)
+	if exporterErr != nil {
+		return NewNoteGraphError(OutputError, exporterErr, "Failed to create NoteGraph exporter for output format [%s]", outputFormat)
+	}
+
+	noteGraphExporter.AddNoteAttributes(noteAttributes...)
+	noteGraphExporter.AddNoteLinkAttributes(noteLinkAttributes...)
+	saveErr := SaveNoteGraphExport(outputFilename, noteGraphExporter, noteGraph, !linkedNotes)
+	if saveErr != nil {
+		return NewNoteGraphError(OutputError, saveErr, "Failed to save NoteGraph to %s file [%s]", outputFormat, outputFilename)
+	}
+
+	return nil
+}
+
+// ReportNoteGraph prints the hygiene report of the NoteGraph and saves it as JSON if a report filename is specified
+func ReportNoteGraph(noteGraph *NoteGraph, thresholds HygieneThresholds, printReport bool, reportFilename string) error {
+	hygieneReportUtil := NewHygieneReportUtil(thresholds)
+	hygieneReport := hygieneReportUtil.CreateHygieneReport(noteGraph)
+	if printReport {
+		hygieneReportUtil.PrintHygieneReport(hygieneReport)
+	}
+
+	if reportFilename != "" {
+		saveReportErr := hygieneReportUtil.SaveHygieneReport(reportFilename, hygieneReport)
+		if saveReportErr != nil {
+			return NewNoteGraphError(OutputError, saveReportErr, "Failed to save hygiene report to JSON file [%s]", reportFilename)
+		}
+	}
+
+	return nil
+}
+
+// Fetch creates the NoteGraph from the Evernote API and saves it as NoteGraph snapshot
+func Fetch(args *FetchArgs) error {
+	if logErr := InitLogger(args.LogArgs, false); logErr != nil {
+		return logErr
+	}
+
+	progress := InitFetchProgress(args.ProgressMode)
+	evernoteNoteGraph, initErr := InitEvernoteNoteGraph(args.EdamAuthToken, args.Sandbox, args.NoteURLType, args.NoteContent, progress)
+	if initErr != nil {
+		return initErr
+	}
+
+	noteGraph, noteGraphErr := CreateNoteGraph(evernoteNoteGraph)
+	if noteGraphErr != nil {
+		return noteGraphErr
+	}
+
+	if saveErr := SaveSnapshot(noteGraph, args.SnapshotFilename); saveErr != nil {
+		return saveErr
+	}
 
 	NewNoteGraphUtil().PrintNoteGraphStats(noteGraph)
-	NewNoteGraphUtil().PrintBrokenNoteLinks(noteGraph)
+	return nil
+}
+
+// Export exports the NoteGraph snapshot in the OutputFormat
+func Export(args *ExportArgs) error {
+	if logErr := InitLogger(args.LogArgs, args.OutputFilename == StdoutFilename); logErr != nil {
+		return logErr
+	}
+
+	noteGraph, noteAttributes, noteLinkAttributes, loadErr := LoadFilteredNoteGraph(args.InputArgs)
+	if loadErr != nil {
+		return loadErr
+	}
+
+	if args.Analyze {
+		noteAttributes = append(noteAttributes, AnalyzeNoteGraph(noteGraph).NoteAttributes()...)
+	}
+
+	if args.Layout {
+		noteAttributes = append(noteAttributes, LayoutNoteGraph(noteGraph, args.LinkedNotes, args.ExportOptions.LayoutIterations)...)
+	}
+
+	return SaveNoteGraph(noteGraph, noteAttributes, noteLinkAttributes, args.LinkedNotes, args.OutputFormat, args.ExportOptions, args.OutputFilename)
+}
+
+// Stats prints the stats, the analysis, the broken NoteLinks, and the hygiene report of the NoteGraph snapshot
+func Stats(args *StatsArgs) error {
+	if logErr := InitLogger(args.LogArgs, false); logErr != nil {
+		return logErr
+	}
+
+	noteGraph, _, _, loadErr := LoadFilteredNoteGraph(args.InputArgs)
+	if loadErr != nil {
+		return loadErr
+	}
+
+	noteGraphUtil := NewNoteGraphUtil()
+	noteGraphUtil.PrintNoteGraphStats(noteGraph)
+	if args.Analyze {
+		noteGraphUtil.PrintNoteGraphAnalysis(noteGraph, AnalyzeNoteGraph(noteGraph))
+	}
+	noteGraphUtil.PrintBrokenNoteLinks(noteGraph)
+
+	if args.HygieneReport || args.ReportFilename != "" {
+		return ReportNoteGraph(noteGraph, args.Thresholds, args.HygieneReport, args.ReportFilename)
+	}
+
+	return nil
+}
+
+// Query prints the backlinks of and the paths between Notes of the NoteGraph snapshot and exports the ego network around the
+// focal Note in the OutputFormat if an output filename is specified
+func Query(args *QueryArgs) error {
+	if logErr := InitLogger(args.LogArgs, args.OutputFilename == StdoutFilename); logErr != nil {
+		return logErr
+	}
+
+	noteGraph, noteAttributes, noteLinkAttributes, loadErr := LoadFilteredNoteGraph(args.InputArgs)
+	if loadErr != nil {
+		return loadErr
+	}
+
+	if args.Backlinks != "" {
+		if backlinksErr := QueryBacklinks(noteGraph, args.Backlinks); backlinksErr != nil {
+			return backlinksErr
+		}
+	}
+
+	if args.PathFrom != "" {
+		if pathsErr := QueryNotePaths(noteGraph, args.PathFrom, args.PathTo, args.PathMaxLength, args.PathFilename); pathsErr != nil {
+			return pathsErr
+		}
+	}
+
+	if args.EgoNote != "" {
+		egoNoteGraph, focalNoteAttribute, egoErr := ExtractEgoNoteGraph(noteGraph, args.EgoNote, args.EgoDepth, args.EgoDirection)
+		if egoErr != nil {
+			return egoErr
+		}
+
+		NewNoteGraphUtil().PrintNoteGraphStats(egoNoteGraph)
+
+		if args.OutputFilename != "" {
+			noteAttributes = append(noteAttributes, focalNoteAttribute)
+			if args.Layout {
+				noteAttributes = append(noteAttributes, LayoutNoteGraph(egoNoteGraph, false, args.ExportOptions.LayoutIterations)...)
+			}
+
+			return SaveNoteGraph(egoNoteGraph, noteAttributes, noteLinkAttributes, false, args.OutputFormat, args.ExportOptions, args.OutputFilename)
+		}
+	}
+
+	return nil
+}
+
+// Serve serves the NoteGraph snapshot with the NoteGraphServer until the server fails
+func Serve(args *ServeArgs) error {
+	if logErr := InitLogger(args.LogArgs, false); logErr != nil {
+		return logErr
+	}
+
+	noteGraph, noteAttributes, noteLinkAttributes, loadErr := LoadFilteredNoteGraph(args.InputArgs)
+	if loadErr != nil {
+		return loadErr
+	}
+
+	if args.Analyze {
+		noteAttributes = append(noteAttributes, AnalyzeNoteGraph(noteGraph).NoteAttributes()...)
+	}
+
+	if args.Layout {
+		noteAttributes = append(noteAttributes, LayoutNoteGraph(noteGraph, args.LinkedNotes, args.ExportOptions.LayoutIterations)...)
+	}
+
+	noteGraphServer := NewNoteGraphServer(noteGraph, noteAttributes, noteLinkAttributes, !args.LinkedNotes, args.ExportOptions)
+	logrus.Infof("Serving NoteGraph at [http://%s]", args.Address)
+	serveErr := http.ListenAndServe(args.Address, noteGraphServer)
+	return NewNoteGraphError(OutputError, serveErr, "Failed to serve NoteGraph at [%s]", args.Address)
+}
+
+// Diff prints the differences between two NoteGraph snapshots or GraphML files
+func Diff(args *DiffArgs) error {
+	if logErr := InitLogger(args.LogArgs, false); logErr != nil {
+		return logErr
+	}
+
+	return DiffNoteGraphs(args.DiffFrom, args.DiffTo, args.DiffFilename)
+}
+
+func main() {
+	os.Exit(NewCLI(os.Stderr).Run(os.Args[1:]))
 }
@@ -0,0 +1,375 @@
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Neo4jNoteLabel is the Neo4j label of all Note nodes
+const Neo4jNoteLabel = "Note"
+
+// Neo4jMissingNoteLabel is the additional Neo4j label of placeholder nodes for Notes referenced by broken NoteLinks
+const Neo4jMissingNoteLabel = "MissingNote"
+
+// Neo4jLinkType is the Neo4j relationship type of NoteLinks
+const Neo4jLinkType = "LINKS_TO"
+
+// Neo4jNode is a Note or a placeholder for a missing Note referenced by a broken NoteLink
+type Neo4jNode struct {
+	Note    Note
+	Missing bool
+}
+
+// Neo4jRelationship is a NoteLink with its ordinal among all NoteLinks with the same text and URL from the same source to the same
+// target Note and its edge ID
+type Neo4jRelationship struct {
+	NoteLink NoteLink
+	Ordinal  int
+	EdgeID   string
+	Broken   bool
+}
+
+// Neo4jUtil selects the nodes and relationships to export to Neo4j, broken NoteLinks are included as relationships to
+// placeholder nodes if BrokenLinks is set
+type Neo4jUtil struct {
+	ExportAttributes
+	BrokenLinks bool
+}
+
+// CreateNodes returns the Notes to export ordered by GUID followed by the placeholders for missing Notes ordered by GUID
+func (nu *Neo4jUtil) CreateNodes(noteGraph *NoteGraph, allNotes bool) []Neo4jNode {
+	notes := map[string]Note{}
+	graphNotes := *noteGraph.GetLinkedNotes()
+	if allNotes {
+		graphNotes = *noteGraph.GetNotes()
+	}
+
+	for _, note := range graphNotes {
+		notes[note.GUID] = note
+	}
+
+	missingNoteGUIDs := map[string]bool{}
+	if nu.BrokenLinks {
+		for _, noteLink := range *noteGraph.GetBrokenNoteLinks() {
+			for _, noteGUID := range []string{noteLink.SourceNoteGUID, noteLink.TargetNoteGUID} {
+				if note := noteGraph.GetNote(noteGUID); note != nil {
+					notes[noteGUID] = *note
+				} else {
+					missingNoteGUIDs[noteGUID] = true
+				}
+			}
+		}
+	}
+
+	nodes := []Neo4jNode{}
+	for _, note := range notes {
+		nodes = append(nodes, Neo4jNode{Note: note})
+	}
+
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].Note.GUID < nodes[j].Note.GUID
+	})
+
+	missingNodes := []Neo4jNode{}
+	for noteGUID := range missingNoteGUIDs {
+		missingNodes = append(missingNodes, Neo4jNode{Note: Note{GUID: noteGUID}, Missing: true})
+	}
+
+	sort.Slice(missingNodes, func(i, j int) bool {
+		return missingNodes[i].Note.GUID < missingNodes[j].Note.GUID
+	})
+
+	return append(nodes, missingNodes...)
+}
+
+// neo4jRelationshipKey identifies the NoteLinks with the same text and URL from the same source to the same target Note
+type neo4jRelationshipKey struct {
+	SourceNoteGUID string
+	TargetNoteGUID string
+	Text           string
+	URL            string
+}
+
+// CreateRelationships returns the valid NoteLinks and, if BrokenLinks is set, the broken NoteLinks to export
+func (nu *Neo4jUtil) CreateRelationships(noteGraph *NoteGraph) []Neo4jRelationship {
+	ordinals := map[neo4jRelationshipKey]int{}
+	relationships := []Neo4jRelationship{}
+	for _, edgeNoteLink := range CanonicalNoteLinks(*noteGraph.GetNoteLinks()) {
+		noteLink := edgeNoteLink.NoteLink
+		broken := noteGraph.GetNote(noteLink.SourceNoteGUID) == nil || noteGraph.GetNote(noteLink.TargetNoteGUID) == nil
+		if broken && !nu.BrokenLinks {
+			continue
+		}
+
+		relationshipKey := neo4jRelationshipKey{noteLink.SourceNoteGUID, noteLink.TargetNoteGUID, noteLink.Text, noteLink.URL.String()}
+		relationships = append(relationships, Neo4jRelationship{NoteLink: noteLink, Ordinal: ordinals[relationshipKey], EdgeID: edgeNoteLink.EdgeID, Broken: broken})
+		ordinals[relationshipKey]++
+	}
+
+	return relationships
+}
+
+// FormatTime formats the time as ISO 8601 date time in UTC, returns an empty string for zero time
+func (nu *Neo4jUtil) FormatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+
+	return t.UTC().Format(time.RFC3339)
+}
+
+// CypherUtil converts a NoteGraph to an idempotent Cypher script that merges Notes on GUID and NoteLinks on source Note, target
+// Note, text, URL, and ordinal, and deletes the relationships of exported Notes that no longer exist
+type CypherUtil struct {
+	Neo4jUtil
+}
+
+// NewCypherUtil creates a new instance of CypherUtil
+func NewCypherUtil(brokenLinks bool) *CypherUtil {
+	return &CypherUtil{Neo4jUtil: Neo4jUtil{BrokenLinks: brokenLinks}}
+}
+
+// ExportNoteGraph converts the NoteGraph into a Cypher script and writes the Cypher script to the writer
+func (cu *CypherUtil) ExportNoteGraph(noteGraph *NoteGraph, allNotes bool, writer io.Writer) error {
+	nodes := cu.CreateNodes(noteGraph, allNotes)
+	relationships := cu.CreateRelationships(noteGraph)
+
+	logrus.Infof("Converting NoteGraph with [%d] nodes and [%d] relationships to Cypher", len(nodes), len(relationships))
+
+	bufferedWriter := bufio.NewWriter(writer)
+	fmt.Fprintln(bufferedWriter, cu.CreateConstraintStatement())
+	for _, node := range nodes {
+		fmt.Fprintln(bufferedWriter, cu.CreateNodeStatement(node))
+	}
+
+	nodeRelationships := map[string][]Neo4jRelationship{}
+	for _, relationship := range relationships {
+		nodeRelationships[relationship.NoteLink.SourceNoteGUID] = append(nodeRelationships[relationship.NoteLink.SourceNoteGUID], relationship)
+	}
+
+	for _, node := range nodes {
+		if !node.Missing {
+			fmt.Fprintln(bufferedWriter, cu.CreateDeleteStaleRelationshipsStatement(node, nodeRelationships[node.Note.GUID]))
+		}
+	}
+
+	for _, relationship := range relationships {
+		fmt.Fprintln(bufferedWriter, cu.CreateRelationshipStatement(relationship))
+	}
+
+	return bufferedWriter.Flush()
+}
+
+// CreateConstraintStatement creates the Cypher statement creating the uniqueness constraint on the GUID of Note nodes, which
+// also creates the index used to match and merge Note nodes
+func (cu *CypherUtil) CreateConstraintStatement() string {
+	return fmt.Sprintf("CREATE CONSTRAINT note_guid IF NOT EXISTS FOR (n:%s) REQUIRE n.guid IS UNIQUE;", Neo4jNoteLabel)
+}
+
+// CreateNodeStatement creates the Cypher statement merging the Note node on GUID and setting all properties
+func (cu *CypherUtil) CreateNodeStatement(node Neo4jNode) string {
+	statement := fmt.Sprintf("MERGE (n:%s {guid: %s})", Neo4jNoteLabel, cu.Literal(node.Note.GUID))
+	if node.Missing {
+		return statement + fmt.Sprintf(" SET n:%s;", Neo4jMissingNoteLabel)
+	}
+
+	properties := []string{
+		"n.title = " + cu.Literal(node.Note.Title),
+		"n.description = " + cu.Literal(node.Note.Description),
+		"n.url = " + cu.Literal(node.Note.URL.String()),
+		"n.urlType = " + cu.Literal(node.Note.URLType.String()),
+		"n.notebook = " + cu.Literal(node.Note.Notebook),
+		"n.created = " + cu.DateTime(node.Note.Created),
+		"n.updated = " + cu.DateTime(node.Note.Updated)}
+	for _, noteAttribute := range cu.NoteAttributes {
+		if value, found := noteAttribute.Values[node.Note.GUID]; found {
+			properties = append(properties, "n."+cu.Name(noteAttribute.Name)+" = "+cu.Literal(TypedAttributeValue(noteAttribute.Type, value)))
+		}
+	}
+
+	return fmt.Sprintf("%s SET %s REMOVE n:%s;", statement, strings.Join(properties, ", "), Neo4jMissingNoteLabel)
+}
+
+// CreateDeleteStaleRelationshipsStatement creates the Cypher statement deleting all relationships from the Note node except the
+// relationships to export, which are identified by target Note, text, URL, and ordinal
+func (cu *CypherUtil) CreateDeleteStaleRelationshipsStatement(node Neo4jNode, relationships []Neo4jRelationship) string {
+	keys := []string{}
+	for _, relationship := range relationships {
+		noteLink := relationship.NoteLink
+		keys = append(keys, fmt.Sprintf("[%s, %s, %s, %d]", cu.Literal(noteLink.TargetNoteGUID), cu.Literal(noteLink.Text), cu.Literal(noteLink.URL.String()), relationship.Ordinal))
+	}
+
+	return fmt.Sprintf("MATCH (s:%s {guid: %s})-[r:%s]->(t) WHERE NOT [t.guid, r.text, r.url, r.ordinal] IN [%s] DELETE r;",
+		Neo4jNoteLabel, cu.Literal(node.Note.GUID), Neo4jLinkType, strings.Join(keys, ", "))
+}
+
+// CreateRelationshipStatement creates the Cypher statement merging the NoteLink relationship on source Note, target Note, text,
+// URL, and ordinal and setting all other properties
+func (cu *CypherUtil) CreateRelationshipStatement(relationship Neo4jRelationship) string {
+	noteLink := relationship.NoteLink
+	properties := []string{
+		"r.urlType = " + cu.Literal(noteLink.URLType.String()),
+		"r.broken = " + cu.Literal(relationship.Broken)}
+	for _, noteLinkAttribute := range cu.NoteLinkAttributes {
+		if value, found := noteLinkAttribute.Value(relationship.EdgeID, noteLink); found {
+			properties = append(properties, "r."+cu.Name(noteLinkAttribute.Name)+" = "+cu.Literal(TypedAttributeValue(noteLinkAttribute.Type, value)))
+		}
+	}
+
+	return fmt.Sprintf("MATCH (s:%s {guid: %s}), (t:%s {guid: %s}) MERGE (s)-[r:%s {text: %s, url: %s, ordinal: %d}]->(t) SET %s;",
+		Neo4jNoteLabel, cu.Literal(noteLink.SourceNoteGUID), Neo4jNoteLabel, cu.Literal(noteLink.TargetNoteGUID), Neo4jLinkType,
+		cu.Literal(noteLink.Text), cu.Literal(noteLink.URL.String()), relationship.Ordinal, strings.Join(properties, ", "))
+}
+
+// DateTime creates the Cypher datetime expression for the time, or null for zero time
+func (cu *CypherUtil) DateTime(t time.Time) string {
+	if t.IsZero() {
+		return "null"
+	}
+
+	return "datetime(" + cu.Literal(cu.FormatTime(t)) + ")"
+}
+
+// Name quotes the property name with backticks
+func (cu *CypherUtil) Name(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
+
+// Literal creates the Cypher literal of a string, bool, int64, or float64 value
+func (cu *CypherUtil) Literal(value interface{}) string {
+	switch typedValue := value.(type) {
+	case bool:
+		return strconv.FormatBool(typedValue)
+	case int64:
+		return strconv.FormatInt(typedValue, 10)
+	case float64:
+		return strconv.FormatFloat(typedValue, 'g', -1, 64)
+	default:
+		replacer := strings.NewReplacer(`\`, `\\`, `'`, `\'`, "\r", `\r`, "\n", `\n`)
+		return "'" + replacer.Replace(fmt.Sprint(value)) + "'"
+	}
+}
+
+// Neo4jCSVUtil converts a NoteGraph to node and relationship CSV files for neo4j-admin import
+type Neo4jCSVUtil struct {
+	Neo4jUtil
+}
+
+// NewNeo4jCSVUtil creates a new instance of Neo4jCSVUtil
+func NewNeo4jCSVUtil(brokenLinks bool) *Neo4jCSVUtil {
+	return &Neo4jCSVUtil{Neo4jUtil: Neo4jUtil{BrokenLinks: brokenLinks}}
+}
+
+// ExportNoteGraph is not supported, node and relationship CSV files have to be written to separate files with ExportNoteGraphFiles
+func (ncu *Neo4jCSVUtil) ExportNoteGraph(noteGraph *NoteGraph, allNotes bool, writer io.Writer) error {
+	return errors.New("Neo4j CSV export writes separate node and relationship files and requires an output filename")
+}
+
+// ExportNoteGraphFiles writes the nodes to <filename>-notes.csv and the relationships to <filename>-links.csv, the extension
+// .csv is removed from the filename
+func (ncu *Neo4jCSVUtil) ExportNoteGraphFiles(noteGraph *NoteGraph, allNotes bool, filename string) error {
+	nodesFilename, relationshipsFilename := ncu.Filenames(filename)
+	nodes := ncu.CreateNodes(noteGraph, allNotes)
+	relationships := ncu.CreateRelationships(noteGraph)
+
+	logrus.Infof("Saving [%d] nodes to Neo4j CSV file [%s] and [%d] relationships to Neo4j CSV file [%s]", len(nodes), nodesFilename, len(relationships), relationshipsFilename)
+
+	nodesErr := ncu.SaveCSV(nodesFilename, ncu.CreateNodeRecords(nodes))
+	if nodesErr != nil {
+		return nodesErr
+	}
+
+	return ncu.SaveCSV(relationshipsFilename, ncu.CreateRelationshipRecords(relationships))
+}
+
+// Filenames returns the filenames of the node and relationship CSV files
+func (ncu *Neo4jCSVUtil) Filenames(filename string) (string, string) {
+	baseFilename := strings.TrimSuffix(filename, ".csv")
+	return baseFilename + "-notes.csv", baseFilename + "-links.csv"
+}
+
+// CreateNodeRecords creates the header and one record per node with typed columns
+func (ncu *Neo4jCSVUtil) CreateNodeRecords(nodes []Neo4jNode) [][]string {
+	header := []string{"guid:ID(" + Neo4jNoteLabel + ")", "title", "description", "url", "urlType", "notebook", "created:datetime", "updated:datetime"}
+	for _, noteAttribute := range ncu.NoteAttributes {
+		header = append(header, noteAttribute.Name+":"+ncu.ColumnType(noteAttribute.Type))
+	}
+	header = append(header, ":LABEL")
+
+	records := [][]string{header}
+	for _, node := range nodes {
+		if node.Missing {
+			record := make([]string, len(header))
+			record[0] = node.Note.GUID
+			record[len(record)-1] = Neo4jNoteLabel + ";" + Neo4jMissingNoteLabel
+			records = append(records, record)
+			continue
+		}
+
+		note := node.Note
+		record := []string{note.GUID, note.Title, note.Description, note.URL.String(), note.URLType.String(), note.Notebook, ncu.FormatTime(note.Created), ncu.FormatTime(note.Updated)}
+		for _, noteAttribute := range ncu.NoteAttributes {
+			record = append(record, noteAttribute.Values[note.GUID])
+		}
+		records = append(records, append(record, Neo4jNoteLabel))
+	}
+
+	return records
+}
+
+// CreateRelationshipRecords creates the header and one record per relationship with typed columns
+func (ncu *Neo4jCSVUtil) CreateRelationshipRecords(relationships []Neo4jRelationship) [][]string {
+	header := []string{":START_ID(" + Neo4jNoteLabel + ")", ":END_ID(" + Neo4jNoteLabel + ")", "ordinal:int", "text", "url", "urlType", "broken:boolean"}
+	for _, noteLinkAttribute := range ncu.NoteLinkAttributes {
+		header = append(header, noteLinkAttribute.Name+":"+ncu.ColumnType(noteLinkAttribute.Type))
+	}
+	header = append(header, ":TYPE")
+
+	records := [][]string{header}
+	for _, relationship := range relationships {
+		noteLink := relationship.NoteLink
+		record := []string{noteLink.SourceNoteGUID, noteLink.TargetNoteGUID, strconv.Itoa(relationship.Ordinal), noteLink.Text, noteLink.URL.String(), noteLink.URLType.String(), strconv.FormatBool(relationship.Broken)}
+		for _, noteLinkAttribute := range ncu.NoteLinkAttributes {
+			value, _ := noteLinkAttribute.Value(relationship.EdgeID, noteLink)
+			record = append(record, value)
+		}
+		records = append(records, append(record, Neo4jLinkType))
+	}
+
+	return records
+}
+
+// ColumnType converts a GraphML attribute type into a neo4j-admin import column type
+func (ncu *Neo4jCSVUtil) ColumnType(attributeType string) string {
+	if attributeType == "int" || attributeType == "long" || attributeType == "float" || attributeType == "double" || attributeType == "boolean" {
+		return attributeType
+	}
+
+	return "string"
+}
+
+// SaveCSV saves the records as CSV file with the specified filename on the file system
+func (ncu *Neo4jCSVUtil) SaveCSV(filename string, records [][]string) error {
+	file, fileErr := os.Create(filename)
+	if fileErr != nil {
+		return fmt.Errorf("Failed to create Neo4j CSV file [%s]: %w", filename, fileErr)
+	}
+	defer file.Close()
+
+	writeErr := csv.NewWriter(file).WriteAll(records)
+	if writeErr != nil {
+		return fmt.Errorf("Failed to write Neo4j CSV file [%s]: %w", filename, writeErr)
+	}
+
+	return nil
+}
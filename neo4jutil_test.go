@@ -0,0 +1,117 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateNeo4jNodesAndRelationships(t *testing.T) {
+	neo4jUtil := &Neo4jUtil{BrokenLinks: false}
+	nodes := neo4jUtil.CreateNodes(CreateHygieneTestNoteGraph(), false)
+	relationships := neo4jUtil.CreateRelationships(CreateHygieneTestNoteGraph())
+
+	assert.Len(t, nodes, 4)
+	assert.Len(t, relationships, 5)
+	assert.Equal(t, 0, relationships[0].Ordinal)
+	assert.Equal(t, 1, relationships[1].Ordinal)
+
+	brokenLinksNeo4jUtil := &Neo4jUtil{BrokenLinks: true}
+	brokenLinksNodes := brokenLinksNeo4jUtil.CreateNodes(CreateHygieneTestNoteGraph(), false)
+	brokenLinksRelationships := brokenLinksNeo4jUtil.CreateRelationships(CreateHygieneTestNoteGraph())
+
+	assert.Len(t, brokenLinksNodes, 5)
+	assert.Equal(t, Neo4jNode{Note: Note{GUID: "X"}, Missing: true}, brokenLinksNodes[4])
+	assert.Len(t, brokenLinksRelationships, 6)
+	assert.True(t, brokenLinksRelationships[5].Broken)
+}
+
+func TestExportNoteGraphToCypher(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	err := NewCypherUtil(true).ExportNoteGraph(CreateHygieneTestNoteGraph(), false, buffer)
+	if err != nil {
+		panic(err)
+	}
+
+	statements := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	assert.Len(t, statements, 16)
+	assert.Equal(t, "CREATE CONSTRAINT note_guid IF NOT EXISTS FOR (n:Note) REQUIRE n.guid IS UNIQUE;", statements[0])
+	assert.Equal(t, "MERGE (n:Note {guid: 'A'}) SET n.title = 'TitleA', n.description = '', n.url = '', n.urlType = 'AppLink', n.notebook = '', n.created = null, n.updated = null REMOVE n:MissingNote;", statements[1])
+	assert.Equal(t, "MERGE (n:Note {guid: 'X'}) SET n:MissingNote;", statements[5])
+	assert.Equal(t, "MATCH (s:Note {guid: 'A'})-[r:LINKS_TO]->(t) WHERE NOT [t.guid, r.text, r.url, r.ordinal] IN [['B', '', '', 0], ['B', '', '', 1], ['C', '', '', 0]] DELETE r;", statements[6])
+	assert.Equal(t, "MATCH (s:Note {guid: 'A'}), (t:Note {guid: 'B'}) MERGE (s)-[r:LINKS_TO {text: '', url: '', ordinal: 1}]->(t) SET r.urlType = 'AppLink', r.broken = false;", statements[11])
+	assert.Equal(t, "MATCH (s:Note {guid: 'D'}), (t:Note {guid: 'X'}) MERGE (s)-[r:LINKS_TO {text: '', url: '', ordinal: 0}]->(t) SET r.urlType = 'AppLink', r.broken = true;", statements[15])
+}
+
+func TestCreateNeo4jRelationshipsOrdinalByTextAndURL(t *testing.T) {
+	noteGraph := NewNoteGraph()
+	noteGraph.Add(Note{GUID: "A"}, []NoteLink{
+		{SourceNoteGUID: "A", TargetNoteGUID: "B", Text: "one"},
+		{SourceNoteGUID: "A", TargetNoteGUID: "B", Text: "two"},
+		{SourceNoteGUID: "A", TargetNoteGUID: "B", Text: "one"}})
+	noteGraph.Add(Note{GUID: "B"}, []NoteLink{})
+
+	relationships := (&Neo4jUtil{}).CreateRelationships(noteGraph)
+
+	assert.Equal(t, []string{"one", "one", "two"}, []string{relationships[0].NoteLink.Text, relationships[1].NoteLink.Text, relationships[2].NoteLink.Text})
+	assert.Equal(t, []int{0, 1, 0}, []int{relationships[0].Ordinal, relationships[1].Ordinal, relationships[2].Ordinal})
+	assert.Equal(t, []string{"A-B-1", "A-B-2", "A-B-3"}, []string{relationships[0].EdgeID, relationships[1].EdgeID, relationships[2].EdgeID})
+}
+
+func TestCypherLiteral(t *testing.T) {
+	cypherUtil := NewCypherUtil(false)
+	assert.Equal(t, `'it\'s a \\ test\n'`, cypherUtil.Literal("it's a \\ test\n"))
+	assert.Equal(t, "true", cypherUtil.Literal(true))
+	assert.Equal(t, "42", cypherUtil.Literal(int64(42)))
+	assert.Equal(t, "0.5", cypherUtil.Literal(0.5))
+	assert.Equal(t, "`my``name`", cypherUtil.Name("my`name"))
+}
+
+func TestExportNoteGraphToNeo4jCSV(t *testing.T) {
+	testFile := filepath.Join(os.TempDir(), "testNoteGraph.csv")
+	neo4jCSVUtil := NewNeo4jCSVUtil(true)
+	neo4jCSVUtil.AddNoteAttributes(NoteAttribute{ID: NodeCommunityID, Name: NodeCommunityName, Type: "int", Values: map[string]string{"A": "0"}})
+	nodesFile, relationshipsFile := neo4jCSVUtil.Filenames(testFile)
+	defer os.Remove(nodesFile)
+	defer os.Remove(relationshipsFile)
+
+	err := SaveNoteGraphExport(testFile, neo4jCSVUtil, CreateHygieneTestNoteGraph(), true)
+	if err != nil {
+		panic(err)
+	}
+
+	nodeRecords := ReadCSVRecords(nodesFile)
+	assert.Equal(t, []string{"guid:ID(Note)", "title", "description", "url", "urlType", "notebook", "created:datetime", "updated:datetime", "community:int", ":LABEL"}, nodeRecords[0])
+	assert.Equal(t, []string{"A", "TitleA", "", "", "AppLink", "", "", "", "0", "Note"}, nodeRecords[1])
+	assert.Equal(t, []string{"X", "", "", "", "", "", "", "", "", "Note;MissingNote"}, nodeRecords[6])
+
+	relationshipRecords := ReadCSVRecords(relationshipsFile)
+	assert.Equal(t, []string{":START_ID(Note)", ":END_ID(Note)", "ordinal:int", "text", "url", "urlType", "broken:boolean", ":TYPE"}, relationshipRecords[0])
+	assert.Len(t, relationshipRecords, 7)
+	assert.Equal(t, []string{"D", "X", "0", "", "", "AppLink", "true", "LINKS_TO"}, relationshipRecords[6])
+}
+
+func TestExportNoteGraphToNeo4jCSVWriter(t *testing.T) {
+	err := NewNeo4jCSVUtil(false).ExportNoteGraph(CreateHygieneTestNoteGraph(), true, &bytes.Buffer{})
+	assert.NotNil(t, err)
+}
+
+func ReadCSVRecords(filename string) [][]string {
+	file, err := os.Open(filename)
+	if err != nil {
+		panic(err)
+	}
+	defer file.Close()
+
+	records, err := csv.NewReader(file).ReadAll()
+	if err != nil {
+		panic(err)
+	}
+
+	return records
+}
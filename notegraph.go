@@ -4,6 +4,9 @@ import (
 	"errors"
 	"fmt"
 	"net/url"
+	"sort"
+	"strings"
+	"time"
 )
 
 // Enum of all URLTypes (see Evernote API documentation at https://dev.evernote.com/doc/articles/note_links.php)
@@ -47,10 +50,15 @@ type Note struct {
 	Description string
 	URL         url.URL
 	URLType     URLType
+	Notebook    string    // name of the notebook, empty if unknown
+	Tags        []string  // sorted names of the tags
+	Content     string    // note content (ENML), empty if unknown
+	Created     time.Time // zero if unknown
+	Updated     time.Time // zero if unknown
 }
 
 func (n Note) String() string {
-	return fmt.Sprintf("{GUID: %s, Title: %s, Description: %s, URL %s, URLType %s}", n.GUID, n.Title, n.Description, n.URL.String(), n.URLType.String())
+	return fmt.Sprintf("{GUID: %s, Title: %s, Description: %s, URL %s, URLType %s, Notebook %s, Tags %v, Created %s, Updated %s}", n.GUID, n.Title, n.Description, n.URL.String(), n.URLType.String(), n.Notebook, n.Tags, n.Created, n.Updated)
 }
 
 // NoteLink is an app, web, public, or shortened link that points from source Note to target Note (see Evernote API documentation at https://dev.evernote.com/doc/articles/note_links.php)
@@ -97,13 +105,55 @@ func (ng *NoteGraph) GetNote(noteGUID string) *Note {
 	return nil
 }
 
-// GetNotes returns all Notes added to the NoteGraph
+// FindNote returns the Note referenced by GUID, Evernote URL (AppLink or WebLink), or title, returns an error if no Note or
+// (for titles) more than one Note matches the noteReference
+func (ng *NoteGraph) FindNote(noteReference string) (*Note, error) {
+	if note := ng.GetNote(noteReference); note != nil {
+		return note, nil
+	}
+
+	if strings.Contains(noteReference, "://") {
+		noteURL, err := url.Parse(noteReference)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to parse Note URL [%s]: %w", noteReference, err)
+		}
+
+		for _, pathElement := range strings.Split(noteURL.Path, "/") {
+			if note := ng.GetNote(pathElement); note != nil {
+				return note, nil
+			}
+		}
+
+		return nil, errors.New("No Note found for URL [" + noteReference + "]")
+	}
+
+	matchingNotes := []Note{}
+	for _, note := range ng.Notes {
+		if note.Title == noteReference {
+			matchingNotes = append(matchingNotes, note)
+		}
+	}
+
+	if len(matchingNotes) == 0 {
+		return nil, errors.New("No Note found with GUID or title [" + noteReference + "]")
+	} else if len(matchingNotes) > 1 {
+		return nil, fmt.Errorf("Found [%d] Notes with title [%s], use GUID or URL instead", len(matchingNotes), noteReference)
+	}
+
+	return &matchingNotes[0], nil
+}
+
+// GetNotes returns all Notes added to the NoteGraph ordered by GUID
 func (ng *NoteGraph) GetNotes() *[]Note {
 	notes := []Note{}
 	for _, note := range ng.Notes {
 		notes = append(notes, note)
 	}
 
+	sort.Slice(notes, func(i, j int) bool {
+		return notes[i].GUID < notes[j].GUID
+	})
+
 	return &notes
 }
 
@@ -112,7 +162,7 @@ func (ng *NoteGraph) GetNoteLinks() *[]NoteLink {
 	return &ng.NoteLinks
 }
 
-// GetLinkedNotes returns Notes that are connected by at least one NoteLink
+// GetLinkedNotes returns Notes that are connected by at least one NoteLink ordered by GUID
 func (ng *NoteGraph) GetLinkedNotes() *[]Note {
 	linkedNotes := map[string]Note{}
 	for _, noteLink := range ng.NoteLinks {
@@ -130,6 +180,10 @@ func (ng *NoteGraph) GetLinkedNotes() *[]Note {
 		notes = append(notes, note)
 	}
 
+	sort.Slice(notes, func(i, j int) bool {
+		return notes[i].GUID < notes[j].GUID
+	})
+
 	return &notes
 }
 
@@ -156,3 +156,43 @@ func TestGetBrokenNoteLinks(t *testing.T) {
 	assert.ElementsMatch(t, *noteGraphE.GetNoteLinks(), []NoteLink{{SourceNoteGUID: "2", TargetNoteGUID: "3"}})
 	assert.ElementsMatch(t, *noteGraphE.GetBrokenNoteLinks(), []NoteLink{{SourceNoteGUID: "2", TargetNoteGUID: "3"}})
 }
+
+func TestFindNote(t *testing.T) {
+	noteA := Note{GUID: "4d971333-8b65-45d6-857b-243c850cabf5", Title: "TitleA", URL: *CreateWebLinkURL("4d971333-8b65-45d6-857b-243c850cabf5"), URLType: WebLink}
+	noteB := Note{GUID: "d72dfad0-7d58-41b5-b2c9-4ca434abd543", Title: "TitleB", URL: *CreateWebLinkURL("d72dfad0-7d58-41b5-b2c9-4ca434abd543"), URLType: WebLink}
+	noteC := Note{GUID: "C", Title: "TitleB"}
+
+	noteGraph := NewNoteGraph()
+	noteGraph.Add(noteA, []NoteLink{})
+	noteGraph.Add(noteB, []NoteLink{})
+	noteGraph.Add(noteC, []NoteLink{})
+
+	// by GUID
+	guidNote, guidErr := noteGraph.FindNote(noteA.GUID)
+	assert.Nil(t, guidErr)
+	assert.Equal(t, noteA, *guidNote)
+
+	// by title
+	titleNote, titleErr := noteGraph.FindNote("TitleA")
+	assert.Nil(t, titleErr)
+	assert.Equal(t, noteA, *titleNote)
+
+	// by WebLink and AppLink URL
+	webLinkNote, webLinkErr := noteGraph.FindNote(CreateWebLinkURL(noteB.GUID).String())
+	assert.Nil(t, webLinkErr)
+	assert.Equal(t, noteB, *webLinkNote)
+
+	appLinkNote, appLinkErr := noteGraph.FindNote(CreateAppLinkURL(noteB.GUID).String())
+	assert.Nil(t, appLinkErr)
+	assert.Equal(t, noteB, *appLinkNote)
+
+	// ambiguous title, unknown title, and unknown URL
+	_, ambiguousErr := noteGraph.FindNote("TitleB")
+	assert.NotNil(t, ambiguousErr)
+
+	_, unknownErr := noteGraph.FindNote("TitleX")
+	assert.NotNil(t, unknownErr)
+
+	_, unknownURLErr := noteGraph.FindNote(CreateWebLinkURL("X").String())
+	assert.NotNil(t, unknownURLErr)
+}
@@ -0,0 +1,442 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/sirupsen/logrus"
+)
+
+// NodeWeakComponentID is the ID of the GraphML attribute used for the weakly connected component of nodes in the graph
+const NodeWeakComponentID = "node-weak-component"
+
+// NodeWeakComponentName is the name of the GraphML attribute used for the weakly connected component of nodes in the graph
+const NodeWeakComponentName = "weakComponent"
+
+// NodeStrongComponentID is the ID of the GraphML attribute used for the strongly connected component of nodes in the graph
+const NodeStrongComponentID = "node-strong-component"
+
+// NodeStrongComponentName is the name of the GraphML attribute used for the strongly connected component of nodes in the graph
+const NodeStrongComponentName = "strongComponent"
+
+// NodeCommunityID is the ID of the GraphML attribute used for the community of nodes in the graph
+const NodeCommunityID = "node-community"
+
+// NodeCommunityName is the name of the GraphML attribute used for the community of nodes in the graph
+const NodeCommunityName = "community"
+
+// NoteCluster is a set of Notes which belong to the same connected component or community
+type NoteCluster struct {
+	ID                     int
+	NoteGUIDs              []string
+	RepresentativeNoteGUID string // Note with the most valid NoteLinks in the NoteCluster
+}
+
+// Size returns the number of Notes in the NoteCluster
+func (nc NoteCluster) Size() int {
+	return len(nc.NoteGUIDs)
+}
+
+// NoteGraphAnalysis contains the connected components and communities detected in a NoteGraph
+type NoteGraphAnalysis struct {
+	WeakComponents   []NoteCluster
+	StrongComponents []NoteCluster
+	Communities      []NoteCluster
+	Modularity       float64
+}
+
+// NoteAttributes returns the NoteAttributes with the weakly connected component, strongly connected component, and community of each Note
+func (nga *NoteGraphAnalysis) NoteAttributes() []NoteAttribute {
+	return []NoteAttribute{
+		NewNoteClusterAttribute(NodeWeakComponentID, NodeWeakComponentName, nga.WeakComponents),
+		NewNoteClusterAttribute(NodeStrongComponentID, NodeStrongComponentName, nga.StrongComponents),
+		NewNoteClusterAttribute(NodeCommunityID, NodeCommunityName, nga.Communities)}
+}
+
+// NewNoteClusterAttribute creates a NoteAttribute with the ID of the NoteCluster of each Note
+func NewNoteClusterAttribute(id, name string, noteClusters []NoteCluster) NoteAttribute {
+	values := map[string]string{}
+	for _, noteCluster := range noteClusters {
+		for _, noteGUID := range noteCluster.NoteGUIDs {
+			values[noteGUID] = fmt.Sprint(noteCluster.ID)
+		}
+	}
+
+	return NoteAttribute{ID: id, Name: name, Type: "int", Values: values}
+}
+
+// NoteGraphAnalyzer detects connected components and communities in a NoteGraph based on the valid NoteLinks
+type NoteGraphAnalyzer struct{}
+
+// NewNoteGraphAnalyzer creates a new instance of NoteGraphAnalyzer
+func NewNoteGraphAnalyzer() *NoteGraphAnalyzer {
+	return &NoteGraphAnalyzer{}
+}
+
+// AnalyzeNoteGraph detects the weakly connected components, strongly connected components, and communities of the NoteGraph
+func (nga *NoteGraphAnalyzer) AnalyzeNoteGraph(noteGraph *NoteGraph) *NoteGraphAnalysis {
+	logrus.Infof("Analyzing NoteGraph with [%d] Notes and [%d] valid NoteLinks", len(noteGraph.Notes), len(*noteGraph.GetValidNoteLinks()))
+
+	communities, modularity := nga.DetectCommunities(noteGraph)
+	return &NoteGraphAnalysis{
+		WeakComponents:   nga.WeakComponents(noteGraph),
+		StrongComponents: nga.StrongComponents(noteGraph),
+		Communities:      communities,
+		Modularity:       modularity}
+}
+
+// WeakComponents returns the weakly connected components of the NoteGraph, i.e. Notes connected by NoteLinks regardless of their direction
+func (nga *NoteGraphAnalyzer) WeakComponents(noteGraph *NoteGraph) []NoteCluster {
+	noteGUIDs := nga.SortedNoteGUIDs(noteGraph)
+	neighbours := nga.Neighbours(noteGraph, true)
+
+	visited := map[string]bool{}
+	clusters := [][]string{}
+	for _, noteGUID := range noteGUIDs {
+		if visited[noteGUID] {
+			continue
+		}
+
+		cluster := []string{}
+		queue := []string{noteGUID}
+		visited[noteGUID] = true
+		for len(queue) > 0 {
+			current := queue[0]
+			queue = queue[1:]
+			cluster = append(cluster, current)
+			for _, neighbour := range neighbours[current] {
+				if !visited[neighbour] {
+					visited[neighbour] = true
+					queue = append(queue, neighbour)
+				}
+			}
+		}
+
+		clusters = append(clusters, cluster)
+	}
+
+	return nga.CreateNoteClusters(noteGraph, clusters)
+}
+
+// StrongComponents returns the strongly connected components of the NoteGraph, i.e. Notes that can reach each other following the direction of NoteLinks
+func (nga *NoteGraphAnalyzer) StrongComponents(noteGraph *NoteGraph) []NoteCluster {
+	noteGUIDs := nga.SortedNoteGUIDs(noteGraph)
+	successors := nga.Neighbours(noteGraph, false)
+
+	// Tarjan's strongly connected components algorithm
+	index := 0
+	indices := map[string]int{}
+	lowLinks := map[string]int{}
+	onStack := map[string]bool{}
+	stack := []string{}
+	clusters := [][]string{}
+
+	var strongConnect func(noteGUID string)
+	strongConnect = func(noteGUID string) {
+		indices[noteGUID] = index
+		lowLinks[noteGUID] = index
+		index++
+		stack = append(stack, noteGUID)
+		onStack[noteGUID] = true
+
+		for _, successor := range successors[noteGUID] {
+			if _, visited := indices[successor]; !visited {
+				strongConnect(successor)
+				if lowLinks[successor] < lowLinks[noteGUID] {
+					lowLinks[noteGUID] = lowLinks[successor]
+				}
+			} else if onStack[successor] && indices[successor] < lowLinks[noteGUID] {
+				lowLinks[noteGUID] = indices[successor]
+			}
+		}
+
+		if lowLinks[noteGUID] == indices[noteGUID] {
+			cluster := []string{}
+			for {
+				member := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				onStack[member] = false
+				cluster = append(cluster, member)
+				if member == noteGUID {
+					break
+				}
+			}
+
+			clusters = append(clusters, cluster)
+		}
+	}
+
+	for _, noteGUID := range noteGUIDs {
+		if _, visited := indices[noteGUID]; !visited {
+			strongConnect(noteGUID)
+		}
+	}
+
+	return nga.CreateNoteClusters(noteGraph, clusters)
+}
+
+// DetectCommunities detects communities in the NoteGraph with the Louvain modularity optimization method treating NoteLinks as undirected
+// edges weighted by the number of NoteLinks between two Notes, returns the communities and the modularity of the partition
+func (nga *NoteGraphAnalyzer) DetectCommunities(noteGraph *NoteGraph) ([]NoteCluster, float64) {
+	noteGUIDs := nga.SortedNoteGUIDs(noteGraph)
+	noteIndices := map[string]int{}
+	for index, noteGUID := range noteGUIDs {
+		noteIndices[noteGUID] = index
+	}
+
+	graph := newLouvainGraph(len(noteGUIDs))
+	for _, noteLink := range *noteGraph.GetValidNoteLinks() {
+		graph.addEdge(noteIndices[noteLink.SourceNoteGUID], noteIndices[noteLink.TargetNoteGUID], 1)
+	}
+
+	// membership maps each Note (by index) to its community in the current (aggregated) graph
+	membership := make([]int, len(noteGUIDs))
+	for index := range membership {
+		membership[index] = index
+	}
+
+	originalGraph := graph
+	for graph.totalWeight > 0 {
+		communities, moved := graph.moveNodes()
+		if !moved {
+			break
+		}
+
+		for index := range membership {
+			membership[index] = communities[membership[index]]
+		}
+
+		graph = graph.aggregate(communities)
+	}
+
+	clusterMembers := map[int][]string{}
+	for index, community := range membership {
+		clusterMembers[community] = append(clusterMembers[community], noteGUIDs[index])
+	}
+
+	clusters := [][]string{}
+	for _, members := range clusterMembers {
+		clusters = append(clusters, members)
+	}
+
+	return nga.CreateNoteClusters(noteGraph, clusters), originalGraph.modularity(membership)
+}
+
+// CreateNoteClusters creates NoteClusters with IDs assigned in descending order of size and with a representative Note
+func (nga *NoteGraphAnalyzer) CreateNoteClusters(noteGraph *NoteGraph, clusters [][]string) []NoteCluster {
+	degrees := nga.Degrees(noteGraph)
+
+	noteClusters := []NoteCluster{}
+	for _, cluster := range clusters {
+		sort.Strings(cluster)
+
+		representativeNoteGUID := cluster[0]
+		for _, noteGUID := range cluster[1:] {
+			if degrees[noteGUID] > degrees[representativeNoteGUID] {
+				representativeNoteGUID = noteGUID
+			}
+		}
+
+		noteClusters = append(noteClusters, NoteCluster{NoteGUIDs: cluster, RepresentativeNoteGUID: representativeNoteGUID})
+	}
+
+	sort.Slice(noteClusters, func(i, j int) bool {
+		if noteClusters[i].Size() != noteClusters[j].Size() {
+			return noteClusters[i].Size() > noteClusters[j].Size()
+		}
+
+		return noteClusters[i].NoteGUIDs[0] < noteClusters[j].NoteGUIDs[0]
+	})
+
+	for index := range noteClusters {
+		noteClusters[index].ID = index
+	}
+
+	return noteClusters
+}
+
+// SortedNoteGUIDs returns the GUIDs of all Notes in the NoteGraph in ascending order
+func (nga *NoteGraphAnalyzer) SortedNoteGUIDs(noteGraph *NoteGraph) []string {
+	noteGUIDs := []string{}
+	for noteGUID := range noteGraph.Notes {
+		noteGUIDs = append(noteGUIDs, noteGUID)
+	}
+
+	sort.Strings(noteGUIDs)
+	return noteGUIDs
+}
+
+// Neighbours returns the GUIDs of the Notes linked from each Note by valid NoteLinks, including Notes linking to the Note if undirected is true
+func (nga *NoteGraphAnalyzer) Neighbours(noteGraph *NoteGraph, undirected bool) map[string][]string {
+	neighbours := map[string][]string{}
+	for _, noteLink := range *noteGraph.GetValidNoteLinks() {
+		neighbours[noteLink.SourceNoteGUID] = append(neighbours[noteLink.SourceNoteGUID], noteLink.TargetNoteGUID)
+		if undirected {
+			neighbours[noteLink.TargetNoteGUID] = append(neighbours[noteLink.TargetNoteGUID], noteLink.SourceNoteGUID)
+		}
+	}
+
+	return neighbours
+}
+
+// Degrees returns the number of valid NoteLinks from and to each Note
+func (nga *NoteGraphAnalyzer) Degrees(noteGraph *NoteGraph) map[string]int {
+	degrees := map[string]int{}
+	for _, noteLink := range *noteGraph.GetValidNoteLinks() {
+		degrees[noteLink.SourceNoteGUID]++
+		degrees[noteLink.TargetNoteGUID]++
+	}
+
+	return degrees
+}
+
+// louvainGraph is the undirected weighted graph the Louvain method operates on, self-loops count twice towards the degree of a node
+type louvainGraph struct {
+	adjacency   []map[int]float64
+	degrees     []float64
+	totalWeight float64
+}
+
+func newLouvainGraph(size int) *louvainGraph {
+	adjacency := make([]map[int]float64, size)
+	for index := range adjacency {
+		adjacency[index] = map[int]float64{}
+	}
+
+	return &louvainGraph{adjacency: adjacency, degrees: make([]float64, size)}
+}
+
+func (lg *louvainGraph) addEdge(source, target int, weight float64) {
+	lg.adjacency[source][target] += weight
+	if source != target {
+		lg.adjacency[target][source] += weight
+	}
+
+	lg.degrees[source] += weight
+	lg.degrees[target] += weight
+	lg.totalWeight += weight
+}
+
+func (lg *louvainGraph) neighbours(node int) []int {
+	neighbours := []int{}
+	for neighbour := range lg.adjacency[node] {
+		neighbours = append(neighbours, neighbour)
+	}
+
+	sort.Ints(neighbours)
+	return neighbours
+}
+
+// moveNodes repeatedly moves single nodes to the neighbouring community with the highest modularity gain until no move improves
+// modularity, returns the community of each node renumbered from zero and whether any node has been moved
+func (lg *louvainGraph) moveNodes() ([]int, bool) {
+	communities := make([]int, len(lg.degrees))
+	totals := make([]float64, len(lg.degrees))
+	for node := range communities {
+		communities[node] = node
+		totals[node] = lg.degrees[node]
+	}
+
+	moved := false
+	for improved := true; improved; {
+		improved = false
+		for node := range communities {
+			community := communities[node]
+			totals[community] -= lg.degrees[node]
+
+			links := map[int]float64{}
+			for _, neighbour := range lg.neighbours(node) {
+				if neighbour != node {
+					links[communities[neighbour]] += lg.adjacency[node][neighbour]
+				}
+			}
+
+			bestCommunity := community
+			bestGain := links[community] - totals[community]*lg.degrees[node]/(2*lg.totalWeight)
+			for _, neighbour := range lg.neighbours(node) {
+				candidate := communities[neighbour]
+				gain := links[candidate] - totals[candidate]*lg.degrees[node]/(2*lg.totalWeight)
+				if gain > bestGain {
+					bestCommunity, bestGain = candidate, gain
+				}
+			}
+
+			totals[bestCommunity] += lg.degrees[node]
+			if bestCommunity != community {
+				communities[node] = bestCommunity
+				improved, moved = true, true
+			}
+		}
+	}
+
+	renumbered := map[int]int{}
+	for node, community := range communities {
+		if _, found := renumbered[community]; !found {
+			renumbered[community] = len(renumbered)
+		}
+
+		communities[node] = renumbered[community]
+	}
+
+	return communities, moved
+}
+
+// aggregate creates a graph whose nodes are the communities of this graph
+func (lg *louvainGraph) aggregate(communities []int) *louvainGraph {
+	size := 0
+	for _, community := range communities {
+		if community >= size {
+			size = community + 1
+		}
+	}
+
+	aggregated := newLouvainGraph(size)
+	for node, neighbours := range lg.adjacency {
+		for neighbour, weight := range neighbours {
+			source, target := communities[node], communities[neighbour]
+			if node == neighbour {
+				aggregated.adjacency[source][source] += weight
+			} else if source == target {
+				// both directions of an edge within a community are visited, each contributes half of the self-loop
+				aggregated.adjacency[source][source] += weight / 2
+			} else {
+				aggregated.adjacency[source][target] += weight
+			}
+		}
+
+		aggregated.degrees[communities[node]] += lg.degrees[node]
+	}
+
+	aggregated.totalWeight = lg.totalWeight
+	return aggregated
+}
+
+// modularity returns the modularity of the partition of the graph into the supplied communities
+func (lg *louvainGraph) modularity(communities []int) float64 {
+	if lg.totalWeight == 0 {
+		return 0
+	}
+
+	internals := map[int]float64{}
+	totals := map[int]float64{}
+	for node, neighbours := range lg.adjacency {
+		for neighbour, weight := range neighbours {
+			if communities[node] == communities[neighbour] {
+				if node == neighbour {
+					internals[communities[node]] += weight
+				} else {
+					internals[communities[node]] += weight / 2
+				}
+			}
+		}
+
+		totals[communities[node]] += lg.degrees[node]
+	}
+
+	modularity := 0.0
+	for community, total := range totals {
+		modularity += internals[community]/lg.totalWeight - (total/(2*lg.totalWeight))*(total/(2*lg.totalWeight))
+	}
+
+	return modularity
+}
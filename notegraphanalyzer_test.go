@@ -0,0 +1,107 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/antchfx/xmlquery"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWeakComponents(t *testing.T) {
+	// five Notes, two weakly connected components with two and three Notes
+	noteGraph := NewNoteGraph()
+	noteGraph.Add(Note{GUID: "1"}, []NoteLink{{SourceNoteGUID: "1", TargetNoteGUID: "2"}})
+	noteGraph.Add(Note{GUID: "2"}, []NoteLink{})
+	noteGraph.Add(Note{GUID: "3"}, []NoteLink{{SourceNoteGUID: "3", TargetNoteGUID: "4"}})
+	noteGraph.Add(Note{GUID: "4"}, []NoteLink{})
+	noteGraph.Add(Note{GUID: "5"}, []NoteLink{{SourceNoteGUID: "5", TargetNoteGUID: "4"}, {SourceNoteGUID: "5", TargetNoteGUID: "6"}})
+
+	weakComponents := NewNoteGraphAnalyzer().WeakComponents(noteGraph)
+	assert.Len(t, weakComponents, 2)
+	assert.Equal(t, NoteCluster{ID: 0, NoteGUIDs: []string{"3", "4", "5"}, RepresentativeNoteGUID: "4"}, weakComponents[0])
+	assert.Equal(t, NoteCluster{ID: 1, NoteGUIDs: []string{"1", "2"}, RepresentativeNoteGUID: "1"}, weakComponents[1])
+}
+
+func TestStrongComponents(t *testing.T) {
+	// four Notes, cycle between three Notes and one Note only linked from the cycle
+	noteGraph := NewNoteGraph()
+	noteGraph.Add(Note{GUID: "1"}, []NoteLink{{SourceNoteGUID: "1", TargetNoteGUID: "2"}})
+	noteGraph.Add(Note{GUID: "2"}, []NoteLink{{SourceNoteGUID: "2", TargetNoteGUID: "3"}})
+	noteGraph.Add(Note{GUID: "3"}, []NoteLink{{SourceNoteGUID: "3", TargetNoteGUID: "1"}, {SourceNoteGUID: "3", TargetNoteGUID: "4"}})
+	noteGraph.Add(Note{GUID: "4"}, []NoteLink{})
+
+	strongComponents := NewNoteGraphAnalyzer().StrongComponents(noteGraph)
+	assert.Len(t, strongComponents, 2)
+	assert.Equal(t, []string{"1", "2", "3"}, strongComponents[0].NoteGUIDs)
+	assert.Equal(t, "3", strongComponents[0].RepresentativeNoteGUID)
+	assert.Equal(t, []string{"4"}, strongComponents[1].NoteGUIDs)
+}
+
+func TestDetectCommunities(t *testing.T) {
+	// two fully linked groups of four Notes connected by a single NoteLink
+	noteGraph := NewNoteGraph()
+	for _, group := range [][]string{{"A1", "A2", "A3", "A4"}, {"B1", "B2", "B3", "B4"}} {
+		for _, source := range group {
+			noteLinks := []NoteLink{}
+			for _, target := range group {
+				if source < target {
+					noteLinks = append(noteLinks, NoteLink{SourceNoteGUID: source, TargetNoteGUID: target})
+				}
+			}
+
+			noteGraph.Add(Note{GUID: source}, noteLinks)
+		}
+	}
+	noteGraph.Add(Note{GUID: "C"}, []NoteLink{})
+	noteGraph.NoteLinks = append(noteGraph.NoteLinks, NoteLink{SourceNoteGUID: "A4", TargetNoteGUID: "B1"})
+
+	communities, modularity := NewNoteGraphAnalyzer().DetectCommunities(noteGraph)
+	assert.Len(t, communities, 3)
+	assert.Equal(t, []string{"A1", "A2", "A3", "A4"}, communities[0].NoteGUIDs)
+	assert.Equal(t, []string{"B1", "B2", "B3", "B4"}, communities[1].NoteGUIDs)
+	assert.Equal(t, []string{"C"}, communities[2].NoteGUIDs)
+	assert.InDelta(t, 0.423, modularity, 0.001)
+}
+
+func TestDetectCommunitiesWithoutNoteLinks(t *testing.T) {
+	noteGraph := NewNoteGraph()
+	noteGraph.Add(Note{GUID: "1"}, []NoteLink{})
+	noteGraph.Add(Note{GUID: "2"}, []NoteLink{{SourceNoteGUID: "2", TargetNoteGUID: "3"}})
+
+	communities, modularity := NewNoteGraphAnalyzer().DetectCommunities(noteGraph)
+	assert.Len(t, communities, 2)
+	assert.Equal(t, 0.0, modularity)
+}
+
+func TestConvertNoteGraphWithNoteGraphAnalysis(t *testing.T) {
+	noteA := Note{GUID: "A", Title: "TitleA", Description: "DescriptionA", URL: *CreateWebLinkURL("A"), URLType: WebLink}
+	noteB := Note{GUID: "B", Title: "TitleB", Description: "DescriptionB", URL: *CreateWebLinkURL("B"), URLType: WebLink}
+	noteC := Note{GUID: "C", Title: "TitleC", Description: "DescriptionC", URL: *CreateWebLinkURL("C"), URLType: WebLink}
+
+	noteGraph := NewNoteGraph()
+	noteGraph.Add(noteA, []NoteLink{{SourceNoteGUID: "A", TargetNoteGUID: "B", URL: *CreateWebLinkURL("B"), URLType: WebLink}})
+	noteGraph.Add(noteB, []NoteLink{})
+	noteGraph.Add(noteC, []NoteLink{})
+
+	noteGraphAnalysis := NewNoteGraphAnalyzer().AnalyzeNoteGraph(noteGraph)
+	noteGraphUtil := NewNoteGraphUtil()
+	noteGraphUtil.AddNoteAttributes(noteGraphAnalysis.NoteAttributes()...)
+	graphMLDocument := noteGraphUtil.ConvertNoteGraph(noteGraph, true)
+	xmlDocument, err := xmlquery.Parse(strings.NewReader(EncodeGraphMLDocument(graphMLDocument)))
+	if err != nil {
+		panic(err)
+	}
+
+	AssertKeyEqual(t, xmlDocument, NodeWeakComponentID, "node", NodeWeakComponentName, "int")
+	AssertKeyEqual(t, xmlDocument, NodeStrongComponentID, "node", NodeStrongComponentName, "int")
+	AssertKeyEqual(t, xmlDocument, NodeCommunityID, "node", NodeCommunityName, "int")
+
+	nodeA := xmlquery.FindOne(xmlDocument, "/graphml/graph/node[@id='A']")
+	assert.Equal(t, "0", xmlquery.FindOne(nodeA, "/data[@key='"+NodeWeakComponentID+"']").InnerText())
+	assert.Equal(t, "0", xmlquery.FindOne(nodeA, "/data[@key='"+NodeCommunityID+"']").InnerText())
+
+	nodeC := xmlquery.FindOne(xmlDocument, "/graphml/graph/node[@id='C']")
+	assert.Equal(t, "1", xmlquery.FindOne(nodeC, "/data[@key='"+NodeWeakComponentID+"']").InnerText())
+	assert.Equal(t, "1", xmlquery.FindOne(nodeC, "/data[@key='"+NodeCommunityID+"']").InnerText())
+}
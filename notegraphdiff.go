@@ -0,0 +1,234 @@
+package main
+
+import (
+	"sort"
+)
+
+// NodeChangeID is the ID of the GraphML attribute used for the change status of nodes in the graph
+const NodeChangeID = "node-change"
+
+// NodeChangeName is the name of the GraphML attribute used for the change status of nodes in the graph
+const NodeChangeName = "change"
+
+// EdgeChangeID is the ID of the GraphML attribute used for the change status of edges in the graph
+const EdgeChangeID = "edge-change"
+
+// EdgeChangeName is the name of the GraphML attribute used for the change status of edges in the graph
+const EdgeChangeName = "change"
+
+// Enum of all ChangeStatuses
+const (
+	Unchanged ChangeStatus = iota // Note or NoteLink exists in both NoteGraphs
+	Added     ChangeStatus = iota // Note or NoteLink exists in the current NoteGraph only
+	Removed   ChangeStatus = iota // Note or NoteLink exists in the previous NoteGraph only
+	Renamed   ChangeStatus = iota // Note exists in both NoteGraphs with different titles
+)
+
+// ChangeStatus identifies how a Note or NoteLink has changed between two NoteGraphs
+type ChangeStatus int
+
+func (cs ChangeStatus) String() string {
+	return [...]string{"unchanged", "added", "removed", "renamed"}[cs]
+}
+
+// RenamedNote is a Note whose title has changed between two NoteGraphs
+type RenamedNote struct {
+	Note          Note
+	PreviousTitle string
+}
+
+// NoteGraphDiff contains the Notes and valid NoteLinks that have been added, removed, or renamed between two NoteGraphs
+type NoteGraphDiff struct {
+	AddedNotes       []Note
+	RemovedNotes     []Note
+	RenamedNotes     []RenamedNote
+	AddedNoteLinks   []NoteLink
+	RemovedNoteLinks []NoteLink
+}
+
+// IsEmpty returns true if the NoteGraphs are identical
+func (ngd *NoteGraphDiff) IsEmpty() bool {
+	return len(ngd.AddedNotes) == 0 && len(ngd.RemovedNotes) == 0 && len(ngd.RenamedNotes) == 0 && len(ngd.AddedNoteLinks) == 0 && len(ngd.RemovedNoteLinks) == 0
+}
+
+// noteLinkIdentity identifies equivalent NoteLinks across NoteGraphs
+type noteLinkIdentity struct {
+	SourceNoteGUID string
+	TargetNoteGUID string
+	Text           string
+}
+
+// diffNoteLinkKey identifies the NoteLinks of the diff NoteGraph that are equal in the canonical order of NoteLinks
+type diffNoteLinkKey struct {
+	noteLinkIdentity
+	URL string
+}
+
+// NoteGraphDiffUtil compares NoteGraphs and converts the differences into a NoteGraph for visual review
+type NoteGraphDiffUtil struct{}
+
+// NewNoteGraphDiffUtil creates a new instance of NoteGraphDiffUtil
+func NewNoteGraphDiffUtil() *NoteGraphDiffUtil {
+	return &NoteGraphDiffUtil{}
+}
+
+// DiffNoteGraphs compares the previous with the current NoteGraph, Notes are matched by GUID and NoteLinks by source Note GUID,
+// target Note GUID, and text (the same NoteLink may appear more than once)
+func (ngdu *NoteGraphDiffUtil) DiffNoteGraphs(previousNoteGraph, currentNoteGraph *NoteGraph) *NoteGraphDiff {
+	noteGraphDiff := &NoteGraphDiff{
+		AddedNotes:       []Note{},
+		RemovedNotes:     []Note{},
+		RenamedNotes:     []RenamedNote{},
+		AddedNoteLinks:   []NoteLink{},
+		RemovedNoteLinks: []NoteLink{}}
+
+	for _, currentNote := range *currentNoteGraph.GetNotes() {
+		previousNote := previousNoteGraph.GetNote(currentNote.GUID)
+		if previousNote == nil {
+			noteGraphDiff.AddedNotes = append(noteGraphDiff.AddedNotes, currentNote)
+		} else if previousNote.Title != currentNote.Title {
+			noteGraphDiff.RenamedNotes = append(noteGraphDiff.RenamedNotes, RenamedNote{Note: currentNote, PreviousTitle: previousNote.Title})
+		}
+	}
+
+	for _, previousNote := range *previousNoteGraph.GetNotes() {
+		if currentNoteGraph.GetNote(previousNote.GUID) == nil {
+			noteGraphDiff.RemovedNotes = append(noteGraphDiff.RemovedNotes, previousNote)
+		}
+	}
+
+	previousNoteLinkCounts := map[noteLinkIdentity]int{}
+	for _, previousNoteLink := range *previousNoteGraph.GetValidNoteLinks() {
+		previousNoteLinkCounts[noteLinkIdentity{previousNoteLink.SourceNoteGUID, previousNoteLink.TargetNoteGUID, previousNoteLink.Text}]++
+	}
+
+	for _, currentNoteLink := range *currentNoteGraph.GetValidNoteLinks() {
+		identity := noteLinkIdentity{currentNoteLink.SourceNoteGUID, currentNoteLink.TargetNoteGUID, currentNoteLink.Text}
+		if previousNoteLinkCounts[identity] > 0 {
+			previousNoteLinkCounts[identity]--
+		} else {
+			noteGraphDiff.AddedNoteLinks = append(noteGraphDiff.AddedNoteLinks, currentNoteLink)
+		}
+	}
+
+	for _, previousNoteLink := range *previousNoteGraph.GetValidNoteLinks() {
+		identity := noteLinkIdentity{previousNoteLink.SourceNoteGUID, previousNoteLink.TargetNoteGUID, previousNoteLink.Text}
+		if previousNoteLinkCounts[identity] > 0 {
+			previousNoteLinkCounts[identity]--
+			noteGraphDiff.RemovedNoteLinks = append(noteGraphDiff.RemovedNoteLinks, previousNoteLink)
+		}
+	}
+
+	ngdu.SortNotes(noteGraphDiff.AddedNotes)
+	ngdu.SortNotes(noteGraphDiff.RemovedNotes)
+	sort.Slice(noteGraphDiff.RenamedNotes, func(i, j int) bool {
+		return noteGraphDiff.RenamedNotes[i].Note.Title < noteGraphDiff.RenamedNotes[j].Note.Title
+	})
+
+	return noteGraphDiff
+}
+
+// SortNotes sorts Notes by title and GUID
+func (ngdu *NoteGraphDiffUtil) SortNotes(notes []Note) {
+	sort.Slice(notes, func(i, j int) bool {
+		if notes[i].Title != notes[j].Title {
+			return notes[i].Title < notes[j].Title
+		}
+
+		return notes[i].GUID < notes[j].GUID
+	})
+}
+
+// DiffNoteGraph returns the NoteGraph with all Notes and valid NoteLinks of the current NoteGraph and the removed Notes and NoteLinks
+// of the previous NoteGraph, and the NoteAttribute and NoteLinkAttribute with the ChangeStatus of each Note and each NoteLink, the
+// ChangeStatus of NoteLinks is keyed by edge ID so that NoteLinks between the same Notes keep their own ChangeStatus
+func (ngdu *NoteGraphDiffUtil) DiffNoteGraph(previousNoteGraph, currentNoteGraph *NoteGraph, noteGraphDiff *NoteGraphDiff) (*NoteGraph, NoteAttribute, NoteLinkAttribute) {
+	diffNoteGraph := NewNoteGraph()
+	noteValues := map[string]string{}
+	for _, currentNote := range *currentNoteGraph.GetNotes() {
+		diffNoteGraph.Add(currentNote, []NoteLink{})
+		noteValues[currentNote.GUID] = Unchanged.String()
+	}
+
+	for _, addedNote := range noteGraphDiff.AddedNotes {
+		noteValues[addedNote.GUID] = Added.String()
+	}
+
+	for _, renamedNote := range noteGraphDiff.RenamedNotes {
+		noteValues[renamedNote.Note.GUID] = Renamed.String()
+	}
+
+	for _, removedNote := range noteGraphDiff.RemovedNotes {
+		diffNoteGraph.Add(removedNote, []NoteLink{})
+		noteValues[removedNote.GUID] = Removed.String()
+	}
+
+	// current NoteLinks are matched with previous NoteLinks in the same order as in DiffNoteGraphs
+	previousNoteLinkCounts := map[noteLinkIdentity]int{}
+	for _, previousNoteLink := range *previousNoteGraph.GetValidNoteLinks() {
+		previousNoteLinkCounts[noteLinkIdentity{previousNoteLink.SourceNoteGUID, previousNoteLink.TargetNoteGUID, previousNoteLink.Text}]++
+	}
+
+	changeStatuses := map[diffNoteLinkKey][]ChangeStatus{}
+	for _, currentNoteLink := range *currentNoteGraph.GetValidNoteLinks() {
+		diffNoteGraph.NoteLinks = append(diffNoteGraph.NoteLinks, currentNoteLink)
+		identity := noteLinkIdentity{currentNoteLink.SourceNoteGUID, currentNoteLink.TargetNoteGUID, currentNoteLink.Text}
+		changeStatus := Added
+		if previousNoteLinkCounts[identity] > 0 {
+			previousNoteLinkCounts[identity]--
+			changeStatus = Unchanged
+		}
+		noteLinkKey := diffNoteLinkKey{identity, currentNoteLink.URL.String()}
+		changeStatuses[noteLinkKey] = append(changeStatuses[noteLinkKey], changeStatus)
+	}
+
+	for _, removedNoteLink := range noteGraphDiff.RemovedNoteLinks {
+		diffNoteGraph.NoteLinks = append(diffNoteGraph.NoteLinks, removedNoteLink)
+		noteLinkKey := diffNoteLinkKey{noteLinkIdentity{removedNoteLink.SourceNoteGUID, removedNoteLink.TargetNoteGUID, removedNoteLink.Text}, removedNoteLink.URL.String()}
+		changeStatuses[noteLinkKey] = append(changeStatuses[noteLinkKey], Removed)
+	}
+
+	// the ChangeStatus is keyed by the edge ID of the NoteLink in the canonical order used by all exporters, equal NoteLinks keep
+	// the order in which they were added
+	noteLinkValues := map[string]string{}
+	for _, edgeNoteLink := range CanonicalNoteLinks(diffNoteGraph.NoteLinks) {
+		noteLink := edgeNoteLink.NoteLink
+		noteLinkKey := diffNoteLinkKey{noteLinkIdentity{noteLink.SourceNoteGUID, noteLink.TargetNoteGUID, noteLink.Text}, noteLink.URL.String()}
+		noteLinkValues[edgeNoteLink.EdgeID] = changeStatuses[noteLinkKey][0].String()
+		changeStatuses[noteLinkKey] = changeStatuses[noteLinkKey][1:]
+	}
+
+	noteAttribute := NoteAttribute{ID: NodeChangeID, Name: NodeChangeName, Type: "string", Values: noteValues}
+	noteLinkAttribute := NoteLinkAttribute{ID: EdgeChangeID, Name: EdgeChangeName, Type: "string", Values: map[NoteLinkKey]string{}, EdgeValues: noteLinkValues}
+	return diffNoteGraph, noteAttribute, noteLinkAttribute
+}
+
+// PrintNoteGraphDiff prints the added, removed, and renamed Notes and the added and removed NoteLinks
+func (ngdu *NoteGraphDiffUtil) PrintNoteGraphDiff(previousNoteGraph, currentNoteGraph *NoteGraph, noteGraphDiff *NoteGraphDiff) {
+	Reportf("NoteGraph Diff")
+
+	Reportf("   Added Notes: %d", len(noteGraphDiff.AddedNotes))
+	for _, addedNote := range noteGraphDiff.AddedNotes {
+		Reportf("      Note [%s] with GUID [%s]", addedNote.Title, addedNote.GUID)
+	}
+
+	Reportf("   Removed Notes: %d", len(noteGraphDiff.RemovedNotes))
+	for _, removedNote := range noteGraphDiff.RemovedNotes {
+		Reportf("      Note [%s] with GUID [%s]", removedNote.Title, removedNote.GUID)
+	}
+
+	Reportf("   Renamed Notes: %d", len(noteGraphDiff.RenamedNotes))
+	for _, renamedNote := range noteGraphDiff.RenamedNotes {
+		Reportf("      Note [%s] renamed to [%s] with GUID [%s]", renamedNote.PreviousTitle, renamedNote.Note.Title, renamedNote.Note.GUID)
+	}
+
+	Reportf("   Added Note Links: %d", len(noteGraphDiff.AddedNoteLinks))
+	for _, addedNoteLink := range noteGraphDiff.AddedNoteLinks {
+		Reportf("      NoteLink [%s] from Note [%s] to Note [%s]", addedNoteLink.Text, currentNoteGraph.Notes[addedNoteLink.SourceNoteGUID].Title, currentNoteGraph.Notes[addedNoteLink.TargetNoteGUID].Title)
+	}
+
+	Reportf("   Removed Note Links: %d", len(noteGraphDiff.RemovedNoteLinks))
+	for _, removedNoteLink := range noteGraphDiff.RemovedNoteLinks {
+		Reportf("      NoteLink [%s] from Note [%s] to Note [%s]", removedNoteLink.Text, previousNoteGraph.Notes[removedNoteLink.SourceNoteGUID].Title, previousNoteGraph.Notes[removedNoteLink.TargetNoteGUID].Title)
+	}
+}
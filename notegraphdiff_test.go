@@ -0,0 +1,157 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/antchfx/xmlquery"
+	"github.com/stretchr/testify/assert"
+)
+
+func CreateDiffTestNoteGraphs() (*NoteGraph, *NoteGraph) {
+	// A renamed, B removed, D added, NoteLink A->B removed, NoteLink C->A unchanged, NoteLink C->D added
+	previousNoteGraph := NewNoteGraph()
+	previousNoteGraph.Add(Note{GUID: "A", Title: "TitleA"}, []NoteLink{{SourceNoteGUID: "A", TargetNoteGUID: "B", Text: "A->B"}})
+	previousNoteGraph.Add(Note{GUID: "B", Title: "TitleB"}, []NoteLink{})
+	previousNoteGraph.Add(Note{GUID: "C", Title: "TitleC"}, []NoteLink{{SourceNoteGUID: "C", TargetNoteGUID: "A", Text: "C->A"}})
+
+	currentNoteGraph := NewNoteGraph()
+	currentNoteGraph.Add(Note{GUID: "A", Title: "RenamedA"}, []NoteLink{})
+	currentNoteGraph.Add(Note{GUID: "C", Title: "TitleC"}, []NoteLink{{SourceNoteGUID: "C", TargetNoteGUID: "A", Text: "C->A"}, {SourceNoteGUID: "C", TargetNoteGUID: "D", Text: "C->D"}})
+	currentNoteGraph.Add(Note{GUID: "D", Title: "TitleD"}, []NoteLink{})
+
+	return previousNoteGraph, currentNoteGraph
+}
+
+func TestNewChangeStatus(t *testing.T) {
+	assert.Equal(t, "unchanged", Unchanged.String())
+	assert.Equal(t, "added", Added.String())
+	assert.Equal(t, "removed", Removed.String())
+	assert.Equal(t, "renamed", Renamed.String())
+}
+
+func TestDiffNoteGraphs(t *testing.T) {
+	previousNoteGraph, currentNoteGraph := CreateDiffTestNoteGraphs()
+	noteGraphDiff := NewNoteGraphDiffUtil().DiffNoteGraphs(previousNoteGraph, currentNoteGraph)
+
+	assert.Equal(t, []Note{{GUID: "D", Title: "TitleD"}}, noteGraphDiff.AddedNotes)
+	assert.Equal(t, []Note{{GUID: "B", Title: "TitleB"}}, noteGraphDiff.RemovedNotes)
+	assert.Equal(t, []RenamedNote{{Note: Note{GUID: "A", Title: "RenamedA"}, PreviousTitle: "TitleA"}}, noteGraphDiff.RenamedNotes)
+	assert.Equal(t, []NoteLink{{SourceNoteGUID: "C", TargetNoteGUID: "D", Text: "C->D"}}, noteGraphDiff.AddedNoteLinks)
+	assert.Equal(t, []NoteLink{{SourceNoteGUID: "A", TargetNoteGUID: "B", Text: "A->B"}}, noteGraphDiff.RemovedNoteLinks)
+	assert.False(t, noteGraphDiff.IsEmpty())
+
+	assert.True(t, NewNoteGraphDiffUtil().DiffNoteGraphs(currentNoteGraph, currentNoteGraph).IsEmpty())
+}
+
+func TestDiffNoteGraphsWithDuplicateNoteLinks(t *testing.T) {
+	previousNoteGraph := NewNoteGraph()
+	previousNoteGraph.Add(Note{GUID: "A"}, []NoteLink{{SourceNoteGUID: "A", TargetNoteGUID: "B", Text: "A->B"}})
+	previousNoteGraph.Add(Note{GUID: "B"}, []NoteLink{})
+
+	currentNoteGraph := NewNoteGraph()
+	currentNoteGraph.Add(Note{GUID: "A"}, []NoteLink{{SourceNoteGUID: "A", TargetNoteGUID: "B", Text: "A->B"}, {SourceNoteGUID: "A", TargetNoteGUID: "B", Text: "A->B"}})
+	currentNoteGraph.Add(Note{GUID: "B"}, []NoteLink{})
+
+	noteGraphDiff := NewNoteGraphDiffUtil().DiffNoteGraphs(previousNoteGraph, currentNoteGraph)
+	assert.Len(t, noteGraphDiff.AddedNoteLinks, 1)
+	assert.Empty(t, noteGraphDiff.RemovedNoteLinks)
+}
+
+func TestConvertDiffNoteGraph(t *testing.T) {
+	previousNoteGraph, currentNoteGraph := CreateDiffTestNoteGraphs()
+	noteGraphDiffUtil := NewNoteGraphDiffUtil()
+	noteGraphDiff := noteGraphDiffUtil.DiffNoteGraphs(previousNoteGraph, currentNoteGraph)
+	diffNoteGraph, noteAttribute, noteLinkAttribute := noteGraphDiffUtil.DiffNoteGraph(previousNoteGraph, currentNoteGraph, noteGraphDiff)
+
+	noteGraphUtil := NewNoteGraphUtil()
+	noteGraphUtil.AddNoteAttributes(noteAttribute)
+	noteGraphUtil.AddNoteLinkAttributes(noteLinkAttribute)
+	graphMLDocument := noteGraphUtil.ConvertNoteGraph(diffNoteGraph, true)
+	xmlDocument, err := xmlquery.Parse(strings.NewReader(EncodeGraphMLDocument(graphMLDocument)))
+	if err != nil {
+		panic(err)
+	}
+
+	AssertKeyEqual(t, xmlDocument, NodeChangeID, "node", NodeChangeName, "string")
+	AssertKeyEqual(t, xmlDocument, EdgeChangeID, "edge", EdgeChangeName, "string")
+	AssertNodeCount(t, xmlDocument, 4)
+	AssertEdgeCount(t, xmlDocument, 3)
+
+	nodeChange := func(nodeID string) string {
+		return xmlquery.FindOne(xmlDocument, "/graphml/graph/node[@id='"+nodeID+"']/data[@key='"+NodeChangeID+"']").InnerText()
+	}
+	assert.Equal(t, "renamed", nodeChange("A"))
+	assert.Equal(t, "removed", nodeChange("B"))
+	assert.Equal(t, "unchanged", nodeChange("C"))
+	assert.Equal(t, "added", nodeChange("D"))
+
+	edgeChange := func(sourceNodeID, targetNodeID string) string {
+		return xmlquery.FindOne(xmlDocument, "/graphml/graph/edge[@source='"+sourceNodeID+"' and @target='"+targetNodeID+"']/data[@key='"+EdgeChangeID+"']").InnerText()
+	}
+	assert.Equal(t, "removed", edgeChange("A", "B"))
+	assert.Equal(t, "unchanged", edgeChange("C", "A"))
+	assert.Equal(t, "added", edgeChange("C", "D"))
+}
+
+func TestDiffNoteGraphParallelNoteLinks(t *testing.T) {
+	// A links to B three times, "kept" is unchanged, "old" is removed, and "new" is added
+	previousNoteGraph := NewNoteGraph()
+	previousNoteGraph.Add(Note{GUID: "A", Title: "TitleA"}, []NoteLink{{SourceNoteGUID: "A", TargetNoteGUID: "B", Text: "kept"}, {SourceNoteGUID: "A", TargetNoteGUID: "B", Text: "old"}})
+	previousNoteGraph.Add(Note{GUID: "B", Title: "TitleB"}, []NoteLink{})
+
+	currentNoteGraph := NewNoteGraph()
+	currentNoteGraph.Add(Note{GUID: "A", Title: "TitleA"}, []NoteLink{{SourceNoteGUID: "A", TargetNoteGUID: "B", Text: "kept"}, {SourceNoteGUID: "A", TargetNoteGUID: "B", Text: "new"}})
+	currentNoteGraph.Add(Note{GUID: "B", Title: "TitleB"}, []NoteLink{})
+
+	noteGraphDiffUtil := NewNoteGraphDiffUtil()
+	noteGraphDiff := noteGraphDiffUtil.DiffNoteGraphs(previousNoteGraph, currentNoteGraph)
+	diffNoteGraph, _, noteLinkAttribute := noteGraphDiffUtil.DiffNoteGraph(previousNoteGraph, currentNoteGraph, noteGraphDiff)
+
+	assert.Equal(t, []string{"kept", "new", "old"}, []string{diffNoteGraph.NoteLinks[0].Text, diffNoteGraph.NoteLinks[1].Text, diffNoteGraph.NoteLinks[2].Text})
+	assert.Equal(t, map[string]string{"A-B-1": "unchanged", "A-B-2": "added", "A-B-3": "removed"}, noteLinkAttribute.EdgeValues)
+
+	noteGraphUtil := NewNoteGraphUtil()
+	noteGraphUtil.AddNoteLinkAttributes(noteLinkAttribute)
+	xmlDocument, err := xmlquery.Parse(strings.NewReader(EncodeGraphMLDocument(noteGraphUtil.ConvertNoteGraph(diffNoteGraph, true))))
+	if err != nil {
+		panic(err)
+	}
+
+	edgeChanges := []string{}
+	for _, data := range xmlquery.Find(xmlDocument, "/graphml/graph/edge/data[@key='"+EdgeChangeID+"']") {
+		edgeChanges = append(edgeChanges, data.InnerText())
+	}
+	assert.Equal(t, []string{"unchanged", "added", "removed"}, edgeChanges)
+}
+
+func TestDiffNoteGraphParallelNoteLinksNotAlphabetical(t *testing.T) {
+	// A links to B twice, "zeta" is unchanged and "alpha" is removed, the GraphML export sorts "alpha" first
+	previousNoteGraph := NewNoteGraph()
+	previousNoteGraph.Add(Note{GUID: "A", Title: "TitleA"}, []NoteLink{{SourceNoteGUID: "A", TargetNoteGUID: "B", Text: "zeta"}, {SourceNoteGUID: "A", TargetNoteGUID: "B", Text: "alpha"}})
+	previousNoteGraph.Add(Note{GUID: "B", Title: "TitleB"}, []NoteLink{})
+
+	currentNoteGraph := NewNoteGraph()
+	currentNoteGraph.Add(Note{GUID: "A", Title: "TitleA"}, []NoteLink{{SourceNoteGUID: "A", TargetNoteGUID: "B", Text: "zeta"}})
+	currentNoteGraph.Add(Note{GUID: "B", Title: "TitleB"}, []NoteLink{})
+
+	noteGraphDiffUtil := NewNoteGraphDiffUtil()
+	noteGraphDiff := noteGraphDiffUtil.DiffNoteGraphs(previousNoteGraph, currentNoteGraph)
+	diffNoteGraph, _, noteLinkAttribute := noteGraphDiffUtil.DiffNoteGraph(previousNoteGraph, currentNoteGraph, noteGraphDiff)
+
+	assert.Equal(t, map[string]string{"A-B-1": "removed", "A-B-2": "unchanged"}, noteLinkAttribute.EdgeValues)
+
+	noteGraphUtil := NewNoteGraphUtil()
+	noteGraphUtil.AddNoteLinkAttributes(noteLinkAttribute)
+	xmlDocument, err := xmlquery.Parse(strings.NewReader(EncodeGraphMLDocument(noteGraphUtil.ConvertNoteGraph(diffNoteGraph, true))))
+	if err != nil {
+		panic(err)
+	}
+
+	edges := xmlquery.Find(xmlDocument, "/graphml/graph/edge")
+	assert.Equal(t, 2, len(edges))
+	assert.Equal(t, "alpha", xmlquery.FindOne(edges[0], "data[@key='"+EdgeLabelID+"']").InnerText())
+	assert.Equal(t, "removed", xmlquery.FindOne(edges[0], "data[@key='"+EdgeChangeID+"']").InnerText())
+	assert.Equal(t, "zeta", xmlquery.FindOne(edges[1], "data[@key='"+EdgeLabelID+"']").InnerText())
+	assert.Equal(t, "unchanged", xmlquery.FindOne(edges[1], "data[@key='"+EdgeChangeID+"']").InnerText())
+}
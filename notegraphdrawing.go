@@ -0,0 +1,152 @@
+package main
+
+import (
+	"math"
+	"sort"
+)
+
+// DrawingWidth is the width of the larger side of NoteGraphDrawings in pixels
+const DrawingWidth = 1600.0
+
+// DrawingMargin is the margin around the Notes of NoteGraphDrawings in pixels
+const DrawingMargin = 40.0
+
+// MinNodeRadius is the radius of Notes without NoteLinks in NoteGraphDrawings in pixels
+const MinNodeRadius = 4.0
+
+// MaxNodeRadius is the radius of the Notes with the most NoteLinks in NoteGraphDrawings in pixels
+const MaxNodeRadius = 16.0
+
+// DrawingNode is a Note in a NoteGraphDrawing drawn as circle
+type DrawingNode struct {
+	Note   Note
+	X      float64
+	Y      float64
+	Radius float64
+	Color  string
+}
+
+// DrawingEdge is a NoteLink in a NoteGraphDrawing drawn as arrow from the border of the source circle to the border of the target circle
+type DrawingEdge struct {
+	NoteLink NoteLink
+	X1       float64
+	Y1       float64
+	X2       float64
+	Y2       float64
+}
+
+// NoteGraphDrawing is a NoteGraph with pixel coordinates of all Notes and NoteLinks for rendering
+type NoteGraphDrawing struct {
+	Width  float64
+	Height float64
+	Labels bool
+	Nodes  []DrawingNode
+	Edges  []DrawingEdge
+}
+
+// DrawingUtil creates NoteGraphDrawings from the force-directed layout of a NoteGraph with Notes sized by number of NoteLinks and
+// coloured by notebook, the x and y NoteAttributes are used as layout if available for all Notes
+type DrawingUtil struct {
+	ExportAttributes
+	Labels     bool
+	Iterations int
+}
+
+// CreateDrawing creates the NoteGraphDrawing of the NoteGraph scaled to DrawingWidth
+func (du *DrawingUtil) CreateDrawing(noteGraph *NoteGraph, allNotes bool) *NoteGraphDrawing {
+	notes := *noteGraph.GetLinkedNotes()
+	if allNotes {
+		notes = *noteGraph.GetNotes()
+	}
+
+	sort.Slice(notes, func(i, j int) bool {
+		return notes[i].GUID < notes[j].GUID
+	})
+
+	noteLinks := *noteGraph.GetValidNoteLinks()
+
+	noteGraphLayouter := NewNoteGraphLayouter(du.Iterations)
+	positions := noteGraphLayouter.NotePositions(du.NoteAttributes)
+	for _, note := range notes {
+		if _, found := positions[note.GUID]; !found {
+			positions = noteGraphLayouter.LayoutNoteGraph(noteGraph, allNotes)
+			break
+		}
+	}
+
+	minX, minY, maxX, maxY := math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1)
+	for _, note := range notes {
+		minX = math.Min(minX, positions[note.GUID].X)
+		minY = math.Min(minY, positions[note.GUID].Y)
+		maxX = math.Max(maxX, positions[note.GUID].X)
+		maxY = math.Max(maxY, positions[note.GUID].Y)
+	}
+
+	scale := 1.0
+	if extent := math.Max(maxX-minX, maxY-minY); extent > 0 {
+		scale = (DrawingWidth - 2*DrawingMargin) / extent
+	}
+
+	drawing := &NoteGraphDrawing{Width: DrawingWidth, Height: DrawingWidth, Labels: du.Labels}
+	if len(notes) > 0 {
+		drawing.Width = math.Ceil((maxX-minX)*scale + 2*DrawingMargin)
+		drawing.Height = math.Ceil((maxY-minY)*scale + 2*DrawingMargin)
+	}
+
+	degrees := map[string]int{}
+	maxDegree := 0
+	for _, noteLink := range noteLinks {
+		for _, noteGUID := range []string{noteLink.SourceNoteGUID, noteLink.TargetNoteGUID} {
+			degrees[noteGUID]++
+			if degrees[noteGUID] > maxDegree {
+				maxDegree = degrees[noteGUID]
+			}
+		}
+	}
+
+	groups := map[string]string{}
+	for _, note := range notes {
+		groups[note.GUID] = note.Notebook
+	}
+
+	groupColors := GroupColors(groups)
+	drawingNodes := map[string]DrawingNode{}
+	for _, note := range notes {
+		radius := MinNodeRadius
+		if maxDegree > 0 {
+			radius += (MaxNodeRadius - MinNodeRadius) * float64(degrees[note.GUID]) / float64(maxDegree)
+		}
+
+		drawingNode := DrawingNode{
+			Note:   note,
+			X:      (positions[note.GUID].X-minX)*scale + DrawingMargin,
+			Y:      (positions[note.GUID].Y-minY)*scale + DrawingMargin,
+			Radius: radius,
+			Color:  groupColors[note.Notebook]}
+		drawingNodes[note.GUID] = drawingNode
+		drawing.Nodes = append(drawing.Nodes, drawingNode)
+	}
+
+	for _, noteLink := range noteLinks {
+		sourceNode, sourceFound := drawingNodes[noteLink.SourceNoteGUID]
+		targetNode, targetFound := drawingNodes[noteLink.TargetNoteGUID]
+		if !sourceFound || !targetFound {
+			continue
+		}
+
+		dx, dy := targetNode.X-sourceNode.X, targetNode.Y-sourceNode.Y
+		distance := math.Hypot(dx, dy)
+		if distance <= sourceNode.Radius+targetNode.Radius {
+			continue
+		}
+
+		drawing.Edges = append(drawing.Edges, DrawingEdge{
+			NoteLink: noteLink,
+			X1:       sourceNode.X + dx/distance*sourceNode.Radius,
+			Y1:       sourceNode.Y + dy/distance*sourceNode.Radius,
+			X2:       targetNode.X - dx/distance*targetNode.Radius,
+			Y2:       targetNode.Y - dy/distance*targetNode.Radius})
+	}
+
+	return drawing
+}
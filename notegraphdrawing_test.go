@@ -0,0 +1,45 @@
+package main
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateDrawing(t *testing.T) {
+	drawingUtil := DrawingUtil{Iterations: 50}
+	drawing := drawingUtil.CreateDrawing(CreateDOTTestNoteGraph(), true)
+
+	assert.Equal(t, 5, len(drawing.Nodes))
+	assert.Equal(t, DrawingWidth, math.Max(drawing.Width, drawing.Height))
+	for _, node := range drawing.Nodes {
+		assert.True(t, node.X >= DrawingMargin && node.X <= drawing.Width-DrawingMargin)
+		assert.True(t, node.Y >= DrawingMargin && node.Y <= drawing.Height-DrawingMargin)
+		assert.True(t, node.Radius >= MinNodeRadius && node.Radius <= MaxNodeRadius)
+	}
+
+	assert.Equal(t, "E", drawing.Nodes[4].Note.GUID)
+	assert.Equal(t, MinNodeRadius, drawing.Nodes[4].Radius)
+	assert.Equal(t, DefaultNodeColor, drawing.Nodes[3].Color)
+	assert.Equal(t, drawing.Nodes[2].Color, drawing.Nodes[4].Color)
+	assert.Equal(t, drawing.Nodes[0].Color, drawing.Nodes[1].Color)
+	assert.NotEqual(t, drawing.Nodes[0].Color, drawing.Nodes[2].Color)
+}
+
+func TestCreateDrawingWithLayoutAttributes(t *testing.T) {
+	drawingUtil := DrawingUtil{}
+	drawingUtil.AddNoteAttributes(NewNoteGraphLayouter(0).PositionAttributes(map[string]NotePosition{"A": {X: 0, Y: 0}, "B": {X: 100, Y: 50}})...)
+
+	noteGraph := NewNoteGraph()
+	noteGraph.Add(Note{GUID: "A"}, []NoteLink{{SourceNoteGUID: "A", TargetNoteGUID: "B"}})
+	noteGraph.Add(Note{GUID: "B"}, []NoteLink{})
+	drawing := drawingUtil.CreateDrawing(noteGraph, true)
+
+	assert.Equal(t, DrawingWidth, drawing.Width)
+	assert.Equal(t, (DrawingWidth-2*DrawingMargin)/2+2*DrawingMargin, drawing.Height)
+	assert.Equal(t, DrawingMargin, drawing.Nodes[0].X)
+	assert.Equal(t, DrawingWidth-DrawingMargin, drawing.Nodes[1].X)
+	assert.Equal(t, 1, len(drawing.Edges))
+	assert.InDelta(t, drawing.Nodes[1].X-drawing.Nodes[1].Radius*2/math.Sqrt(5), drawing.Edges[0].X2, 0.001)
+}
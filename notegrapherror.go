@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"time"
+
+	"github.com/apache/thrift/lib/go/thrift"
+	"github.com/dreampuf/evernote-sdk-golang/edam"
+)
+
+// Enum of all ErrorKinds
+const (
+	UnknownError        ErrorKind = iota // unexpected error without a more specific kind
+	AuthenticationError ErrorKind = iota // Evernote API auth token is invalid, expired, or lacks permissions
+	NetworkError        ErrorKind = iota // Evernote API is unreachable or fails temporarily
+	RateLimitError      ErrorKind = iota // Evernote API rate limit is reached
+	ParseError          ErrorKind = iota // input file or note content cannot be read or parsed
+	OutputError         ErrorKind = iota // output file cannot be written or the server cannot listen
+)
+
+// ErrorKind classifies errors by their cause so that callers can react differently to each ErrorKind
+type ErrorKind int
+
+func (ek ErrorKind) String() string {
+	return [...]string{"unknown", "authentication", "network", "rate-limited", "parse", "output"}[ek]
+}
+
+// ExitCode returns the exit code of the command line interface for errors of the ErrorKind
+func (ek ErrorKind) ExitCode() int {
+	return [...]int{ExitError, ExitAuthentication, ExitNetwork, ExitRateLimit, ExitParse, ExitOutput}[ek]
+}
+
+// Hint returns advice on how to resolve errors of the ErrorKind
+func (ek ErrorKind) Hint() string {
+	return [...]string{
+		"",
+		"Check that the Evernote API auth token is valid and not expired, developer tokens can be created at https://www.evernote.com/api/DeveloperToken.action",
+		"Check the network connection and try again later",
+		"Try again after the rate limit duration",
+		"Check that the input file is a NoteGraph snapshot or GraphML file created by evernote-note-graph",
+		"Check that the output path exists and is writable"}[ek]
+}
+
+// NoteGraphError is an error with an ErrorKind and a message describing the failed operation, wrapping the error causing it
+type NoteGraphError struct {
+	Kind       ErrorKind
+	Message    string
+	RetryAfter time.Duration // RateLimitError only: time until the Evernote API accepts calls again
+	Err        error
+}
+
+// NewNoteGraphError creates a new instance of NoteGraphError with the formatted message
+func NewNoteGraphError(kind ErrorKind, err error, format string, args ...interface{}) *NoteGraphError {
+	return &NoteGraphError{Kind: kind, Message: fmt.Sprintf(format, args...), Err: err}
+}
+
+// Error returns the message followed by the message of the wrapped error
+func (nge *NoteGraphError) Error() string {
+	if nge.Err == nil {
+		return nge.Message
+	}
+
+	return nge.Message + ": " + nge.Err.Error()
+}
+
+// Unwrap returns the wrapped error
+func (nge *NoteGraphError) Unwrap() error {
+	return nge.Err
+}
+
+// ErrorKindOf returns the ErrorKind of the outermost NoteGraphError wrapped by the error, or UnknownError if there is none
+func ErrorKindOf(err error) ErrorKind {
+	var noteGraphError *NoteGraphError
+	if errors.As(err, &noteGraphError) {
+		return noteGraphError.Kind
+	}
+
+	return UnknownError
+}
+
+// ClassifyAPIError converts an error returned by a call to the Evernote API into a NoteGraphError with the ErrorKind of its cause
+func ClassifyAPIError(err error) *NoteGraphError {
+	var noteGraphError *NoteGraphError
+	if errors.As(err, &noteGraphError) {
+		return noteGraphError
+	}
+
+	var userException *edam.EDAMUserException
+	if errors.As(err, &userException) {
+		errorCode := userException.GetErrorCode()
+		if errorCode == edam.EDAMErrorCode_AUTH_EXPIRED || errorCode == edam.EDAMErrorCode_INVALID_AUTH || errorCode == edam.EDAMErrorCode_PERMISSION_DENIED {
+			return NewNoteGraphError(AuthenticationError, err, "Evernote API rejected the auth token with error code [%s]", errorCode)
+		}
+
+		return NewNoteGraphError(UnknownError, err, "Evernote API rejected the request with error code [%s]", errorCode)
+	}
+
+	var systemException *edam.EDAMSystemException
+	if errors.As(err, &systemException) {
+		if systemException.GetErrorCode() == edam.EDAMErrorCode_RATE_LIMIT_REACHED {
+			noteGraphError = NewNoteGraphError(RateLimitError, err, "Evernote API rate limit reached, retry after [%d] seconds", systemException.GetRateLimitDuration())
+			noteGraphError.RetryAfter = time.Duration(systemException.GetRateLimitDuration()) * time.Second
+			return noteGraphError
+		}
+
+		return NewNoteGraphError(NetworkError, err, "Evernote API failed with error code [%s]", systemException.GetErrorCode())
+	}
+
+	var notFoundException *edam.EDAMNotFoundException
+	if errors.As(err, &notFoundException) {
+		return NewNoteGraphError(UnknownError, err, "Evernote API could not find [%s]", notFoundException.GetIdentifier())
+	}
+
+	var netError net.Error
+	var urlError *url.Error
+	var transportException thrift.TTransportException
+	if errors.As(err, &netError) || errors.As(err, &urlError) || errors.As(err, &transportException) || errors.Is(err, context.DeadlineExceeded) {
+		return NewNoteGraphError(NetworkError, err, "Evernote API is not reachable")
+	}
+
+	return NewNoteGraphError(UnknownError, err, "Evernote API call failed")
+}
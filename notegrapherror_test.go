@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/dreampuf/evernote-sdk-golang/edam"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClassifyAPIError(t *testing.T) {
+	authExpired := edam.EDAMErrorCode_AUTH_EXPIRED
+	authErr := ClassifyAPIError(&edam.EDAMUserException{ErrorCode: authExpired})
+	assert.Equal(t, AuthenticationError, authErr.Kind)
+	assert.Contains(t, authErr.Error(), "AUTH_EXPIRED")
+
+	badDataFormat := edam.EDAMErrorCode_BAD_DATA_FORMAT
+	assert.Equal(t, UnknownError, ClassifyAPIError(&edam.EDAMUserException{ErrorCode: badDataFormat}).Kind)
+
+	rateLimitDuration := int32(300)
+	rateLimitErr := ClassifyAPIError(&edam.EDAMSystemException{ErrorCode: edam.EDAMErrorCode_RATE_LIMIT_REACHED, RateLimitDuration: &rateLimitDuration})
+	assert.Equal(t, RateLimitError, rateLimitErr.Kind)
+	assert.Equal(t, 5*time.Minute, rateLimitErr.RetryAfter)
+
+	assert.Equal(t, NetworkError, ClassifyAPIError(&edam.EDAMSystemException{ErrorCode: edam.EDAMErrorCode_INTERNAL_ERROR}).Kind)
+	assert.Equal(t, UnknownError, ClassifyAPIError(&edam.EDAMNotFoundException{}).Kind)
+	assert.Equal(t, NetworkError, ClassifyAPIError(&net.OpError{Op: "dial", Err: errors.New("connection refused")}).Kind)
+	assert.Equal(t, NetworkError, ClassifyAPIError(fmt.Errorf("call failed: %w", context.DeadlineExceeded)).Kind)
+	assert.Equal(t, UnknownError, ClassifyAPIError(errors.New("unexpected")).Kind)
+
+	assert.True(t, authErr == ClassifyAPIError(fmt.Errorf("Failed to retrieve user: %w", authErr)))
+}
+
+func TestErrorKindOf(t *testing.T) {
+	parseErr := NewNoteGraphError(ParseError, errors.New("unexpected EOF"), "Failed to load NoteGraph snapshot file [%s]", "notegraph.json")
+	assert.Equal(t, "Failed to load NoteGraph snapshot file [notegraph.json]: unexpected EOF", parseErr.Error())
+	assert.Equal(t, ParseError, ErrorKindOf(fmt.Errorf("wrapped: %w", parseErr)))
+	assert.Equal(t, UnknownError, ErrorKindOf(errors.New("unexpected")))
+
+	assert.Equal(t, ExitError, UnknownError.ExitCode())
+	assert.Equal(t, ExitAuthentication, AuthenticationError.ExitCode())
+	assert.Equal(t, ExitNetwork, NetworkError.ExitCode())
+	assert.Equal(t, ExitRateLimit, RateLimitError.ExitCode())
+	assert.Equal(t, ExitParse, ParseError.ExitCode())
+	assert.Equal(t, ExitOutput, OutputError.ExitCode())
+	assert.Equal(t, "rate-limited", RateLimitError.String())
+}
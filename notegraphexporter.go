@@ -0,0 +1,283 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/sirupsen/logrus"
+)
+
+// StdoutFilename is the output filename used to write the NoteGraph to stdout
+const StdoutFilename = "-"
+
+// Enum of all OutputFormats
+const (
+	GraphML   OutputFormat = iota // GraphML for yEd, Gephi, and Cytoscape
+	GEXF      OutputFormat = iota // GEXF for Gephi with node and edge lifetimes
+	DOT       OutputFormat = iota // DOT for Graphviz
+	Cytoscape OutputFormat = iota // Cytoscape.js elements JSON for web views
+	JGF       OutputFormat = iota // JSON Graph Format
+	Cypher    OutputFormat = iota // Cypher script for Neo4j
+	Neo4jCSV  OutputFormat = iota // node and relationship CSV files for neo4j-admin import
+	SQLite    OutputFormat = iota // SQLite database for ad-hoc SQL queries
+	Vault     OutputFormat = iota // directory of Markdown files with wiki links for Obsidian
+	HTML      OutputFormat = iota // self-contained interactive HTML graph viewer
+	SVG       OutputFormat = iota // SVG image of the force-directed layout with clickable Notes
+	PNG       OutputFormat = iota // PNG image of the force-directed layout
+)
+
+// OutputFormat identifies the file format a NoteGraph is exported to
+type OutputFormat int
+
+func (of OutputFormat) String() string {
+	return [...]string{"GraphML", "GEXF", "DOT", "Cytoscape", "JGF", "Cypher", "Neo4jCSV", "SQLite", "Vault", "HTML", "SVG", "PNG"}[of]
+}
+
+// Extension returns the default file extension of the OutputFormat
+func (of OutputFormat) Extension() string {
+	return [...]string{".graphml", ".gexf", ".dot", ".cyjs", ".json", ".cypher", ".csv", ".sqlite", "", ".html", ".svg", ".png"}[of]
+}
+
+// ContentType returns the media type of the OutputFormat, OutputFormats that write more than one file have no media type
+func (of OutputFormat) ContentType() string {
+	return [...]string{"application/xml", "application/xml", "text/vnd.graphviz", "application/json", "application/json", "text/plain; charset=utf-8", "", "", "", "text/html; charset=utf-8", "image/svg+xml", "image/png"}[of]
+}
+
+// NewOutputFormat create an OutputFormat instance from the string
+func NewOutputFormat(value string) (*OutputFormat, error) {
+	if value == GraphML.String() {
+		outputFormat := GraphML
+		return &outputFormat, nil
+	} else if value == GEXF.String() {
+		outputFormat := GEXF
+		return &outputFormat, nil
+	} else if value == DOT.String() {
+		outputFormat := DOT
+		return &outputFormat, nil
+	} else if value == Cytoscape.String() {
+		outputFormat := Cytoscape
+		return &outputFormat, nil
+	} else if value == JGF.String() {
+		outputFormat := JGF
+		return &outputFormat, nil
+	} else if value == Cypher.String() {
+		outputFormat := Cypher
+		return &outputFormat, nil
+	} else if value == Neo4jCSV.String() {
+		outputFormat := Neo4jCSV
+		return &outputFormat, nil
+	} else if value == SQLite.String() {
+		outputFormat := SQLite
+		return &outputFormat, nil
+	} else if value == Vault.String() {
+		outputFormat := Vault
+		return &outputFormat, nil
+	} else if value == HTML.String() {
+		outputFormat := HTML
+		return &outputFormat, nil
+	} else if value == SVG.String() {
+		outputFormat := SVG
+		return &outputFormat, nil
+	} else if value == PNG.String() {
+		outputFormat := PNG
+		return &outputFormat, nil
+	}
+
+	return nil, errors.New("Invalid OutputFormat [" + value + "]")
+}
+
+// INoteGraphExporter is an interface that exposes all functions required to export a NoteGraph in one of the OutputFormats
+type INoteGraphExporter interface {
+	AddNoteAttributes(noteAttributes ...NoteAttribute)
+	AddNoteLinkAttributes(noteLinkAttributes ...NoteLinkAttribute)
+	ExportNoteGraph(noteGraph *NoteGraph, allNotes bool, writer io.Writer) error
+}
+
+// INoteGraphFilesExporter is implemented by INoteGraphExporters that export a NoteGraph to more than one file
+type INoteGraphFilesExporter interface {
+	ExportNoteGraphFiles(noteGraph *NoteGraph, allNotes bool, filename string) error
+}
+
+// ExportOptions contains the options of the OutputFormats, options not supported by an OutputFormat are ignored
+type ExportOptions struct {
+	ClusterNotebooks bool        // DOT only: group Notes in one subgraph cluster per notebook
+	BrokenLinks      bool        // Cypher and Neo4jCSV only: include broken NoteLinks as relationships to placeholder nodes
+	YFiles           bool        // GraphML only: add yFiles node and edge graphics so that yEd renders the graph immediately
+	NodeColorBy      NodeColorBy // GraphML with YFiles only: fill colour nodes by notebook or community
+	AggregateLinks   bool        // GraphML only: collapse parallel NoteLinks into a single weighted edge
+	DropSelfLoops    bool        // GraphML only: omit NoteLinks from a Note to itself
+	Undirected       bool        // GraphML only: merge NoteLinks in both directions into a single undirected edge
+	Reciprocal       bool        // GraphML only: annotate edges with NoteLinks in both directions as reciprocal
+	Labels           bool        // SVG and PNG only: draw Note titles as node labels
+	LayoutIterations int         // SVG and PNG only: iterations of the force-directed layout if not already computed
+}
+
+// NewNoteGraphExporter creates the INoteGraphExporter for the OutputFormat
+func NewNoteGraphExporter(outputFormat OutputFormat, exportOptions ExportOptions) (INoteGraphExporter, error) {
+	if outputFormat == GraphML {
+		noteGraphUtil := NewNoteGraphUtil()
+		noteGraphUtil.YFiles = exportOptions.YFiles
+		noteGraphUtil.NodeColorBy = exportOptions.NodeColorBy
+		noteGraphUtil.AggregateLinks = exportOptions.AggregateLinks
+		noteGraphUtil.DropSelfLoops = exportOptions.DropSelfLoops
+		noteGraphUtil.Undirected = exportOptions.Undirected
+		noteGraphUtil.Reciprocal = exportOptions.Reciprocal
+		return noteGraphUtil, nil
+	} else if outputFormat == GEXF {
+		return NewGEXFUtil(), nil
+	} else if outputFormat == DOT {
+		return NewDOTUtil(exportOptions.ClusterNotebooks), nil
+	} else if outputFormat == Cytoscape {
+		return NewCytoscapeUtil(), nil
+	} else if outputFormat == JGF {
+		return NewJGFUtil(), nil
+	} else if outputFormat == Cypher {
+		return NewCypherUtil(exportOptions.BrokenLinks), nil
+	} else if outputFormat == Neo4jCSV {
+		return NewNeo4jCSVUtil(exportOptions.BrokenLinks), nil
+	} else if outputFormat == SQLite {
+		return NewSQLiteUtil(), nil
+	} else if outputFormat == Vault {
+		return NewVaultUtil(), nil
+	} else if outputFormat == HTML {
+		return NewHTMLUtil(), nil
+	} else if outputFormat == SVG {
+		return NewSVGUtil(exportOptions.Labels, exportOptions.LayoutIterations), nil
+	} else if outputFormat == PNG {
+		return NewPNGUtil(exportOptions.Labels, exportOptions.LayoutIterations), nil
+	}
+
+	return nil, errors.New("Unsupported OutputFormat [" + outputFormat.String() + "]")
+}
+
+// NoteAttribute is an additional attribute of the nodes with a value for some or all Notes
+type NoteAttribute struct {
+	ID     string
+	Name   string
+	Type   string            // GraphML attribute type (boolean, int, long, float, double, or string)
+	Values map[string]string // attribute values by Note GUID
+}
+
+// NoteLinkKey identifies all NoteLinks from the source Note to the target Note
+type NoteLinkKey struct {
+	SourceNoteGUID string
+	TargetNoteGUID string
+}
+
+// NoteLinkAttribute is an additional attribute of the edges with a value for some or all pairs of linked Notes or for individual edges
+type NoteLinkAttribute struct {
+	ID         string
+	Name       string
+	Type       string                 // GraphML attribute type (boolean, int, long, float, double, or string)
+	Values     map[NoteLinkKey]string // attribute values by source and target Note GUID
+	EdgeValues map[string]string      // attribute values by edge ID (see CanonicalNoteLinks), take precedence over Values
+}
+
+// Value returns the attribute value of the NoteLink with the edge ID, returns false if the NoteLink has no value
+func (nla NoteLinkAttribute) Value(edgeID string, noteLink NoteLink) (string, bool) {
+	if value, found := nla.EdgeValues[edgeID]; found {
+		return value, true
+	}
+
+	value, found := nla.Values[NoteLinkKey{noteLink.SourceNoteGUID, noteLink.TargetNoteGUID}]
+	return value, found
+}
+
+// ExportAttributes contains the additional NoteAttributes and NoteLinkAttributes to include when exporting a NoteGraph
+type ExportAttributes struct {
+	NoteAttributes     []NoteAttribute
+	NoteLinkAttributes []NoteLinkAttribute
+}
+
+// AddNoteAttributes adds NoteAttributes to include as attributes of the nodes when exporting the NoteGraph
+func (ea *ExportAttributes) AddNoteAttributes(noteAttributes ...NoteAttribute) {
+	for _, noteAttribute := range noteAttributes {
+		replaced := false
+		for index := range ea.NoteAttributes {
+			if ea.NoteAttributes[index].ID == noteAttribute.ID {
+				ea.NoteAttributes[index] = noteAttribute
+				replaced = true
+			}
+		}
+
+		if !replaced {
+			ea.NoteAttributes = append(ea.NoteAttributes, noteAttribute)
+		}
+	}
+}
+
+// AddNoteLinkAttributes adds NoteLinkAttributes to include as attributes of the edges when exporting the NoteGraph
+func (ea *ExportAttributes) AddNoteLinkAttributes(noteLinkAttributes ...NoteLinkAttribute) {
+	for _, noteLinkAttribute := range noteLinkAttributes {
+		replaced := false
+		for index := range ea.NoteLinkAttributes {
+			if ea.NoteLinkAttributes[index].ID == noteLinkAttribute.ID {
+				ea.NoteLinkAttributes[index] = noteLinkAttribute
+				replaced = true
+			}
+		}
+
+		if !replaced {
+			ea.NoteLinkAttributes = append(ea.NoteLinkAttributes, noteLinkAttribute)
+		}
+	}
+}
+
+// TypedAttributeValue converts the string value of a NoteAttribute or NoteLinkAttribute into a bool, int64, or float64 value
+// according to the attribute type, values that cannot be converted are returned as string
+func TypedAttributeValue(attributeType, value string) interface{} {
+	if attributeType == "boolean" {
+		if boolValue, err := strconv.ParseBool(value); err == nil {
+			return boolValue
+		}
+	} else if attributeType == "int" || attributeType == "long" {
+		if intValue, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return intValue
+		}
+	} else if attributeType == "float" || attributeType == "double" {
+		if floatValue, err := strconv.ParseFloat(value, 64); err == nil {
+			return floatValue
+		}
+	}
+
+	return value
+}
+
+// SaveNoteGraphExport exports the NoteGraph with the INoteGraphExporter to the file with the specified filename on the file system,
+// or to stdout if the filename is StdoutFilename, INoteGraphFilesExporters derive their filenames from the filename, GraphML files
+// with GraphMLZExt extension are gzip compressed
+func SaveNoteGraphExport(filename string, noteGraphExporter INoteGraphExporter, noteGraph *NoteGraph, allNotes bool) error {
+	if noteGraphFilesExporter, ok := noteGraphExporter.(INoteGraphFilesExporter); ok && filename != StdoutFilename {
+		return noteGraphFilesExporter.ExportNoteGraphFiles(noteGraph, allNotes, filename)
+	}
+
+	if filename == StdoutFilename {
+		logrus.Info("Writing NoteGraph to stdout")
+		return noteGraphExporter.ExportNoteGraph(noteGraph, allNotes, os.Stdout)
+	}
+
+	logrus.WithField("filename", filename).Info("Saving NoteGraph to file")
+
+	file, fileErr := os.Create(filename)
+	if fileErr != nil {
+		return fmt.Errorf("Failed to create NoteGraph file [%s]: %w", filename, fileErr)
+	}
+	defer file.Close()
+
+	var exportErr error
+	if _, graphML := noteGraphExporter.(*NoteGraphUtil); graphML {
+		exportErr = WriteGraphMLFile(filename, file, func(writer io.Writer) error {
+			return noteGraphExporter.ExportNoteGraph(noteGraph, allNotes, writer)
+		})
+	} else {
+		exportErr = noteGraphExporter.ExportNoteGraph(noteGraph, allNotes, file)
+	}
+	if exportErr != nil {
+		return fmt.Errorf("Failed to export NoteGraph to file [%s]: %w", filename, exportErr)
+	}
+
+	return nil
+}
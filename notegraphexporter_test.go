@@ -0,0 +1,170 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewOutputFormat(t *testing.T) {
+	graphML, err := NewOutputFormat("GraphML")
+	assert.Nil(t, err)
+	assert.Equal(t, GraphML, *graphML)
+	assert.Equal(t, ".graphml", graphML.Extension())
+	assert.Equal(t, "application/xml", graphML.ContentType())
+
+	gexf, err := NewOutputFormat("GEXF")
+	assert.Nil(t, err)
+	assert.Equal(t, GEXF, *gexf)
+	assert.Equal(t, ".gexf", gexf.Extension())
+
+	dot, err := NewOutputFormat("DOT")
+	assert.Nil(t, err)
+	assert.Equal(t, DOT, *dot)
+	assert.Equal(t, ".dot", dot.Extension())
+
+	cytoscape, err := NewOutputFormat("Cytoscape")
+	assert.Nil(t, err)
+	assert.Equal(t, Cytoscape, *cytoscape)
+
+	jgf, err := NewOutputFormat("JGF")
+	assert.Nil(t, err)
+	assert.Equal(t, JGF, *jgf)
+
+	cypher, err := NewOutputFormat("Cypher")
+	assert.Nil(t, err)
+	assert.Equal(t, Cypher, *cypher)
+
+	neo4jCSV, err := NewOutputFormat("Neo4jCSV")
+	assert.Nil(t, err)
+	assert.Equal(t, Neo4jCSV, *neo4jCSV)
+
+	sqlite, err := NewOutputFormat("SQLite")
+	assert.Nil(t, err)
+	assert.Equal(t, SQLite, *sqlite)
+
+	vault, err := NewOutputFormat("Vault")
+	assert.Nil(t, err)
+	assert.Equal(t, Vault, *vault)
+	assert.Equal(t, "", vault.ContentType())
+
+	html, err := NewOutputFormat("HTML")
+	assert.Nil(t, err)
+	assert.Equal(t, HTML, *html)
+
+	svg, err := NewOutputFormat("SVG")
+	assert.Nil(t, err)
+	assert.Equal(t, SVG, *svg)
+
+	png, err := NewOutputFormat("PNG")
+	assert.Nil(t, err)
+	assert.Equal(t, PNG, *png)
+
+	_, err = NewOutputFormat("PDF")
+	assert.NotNil(t, err)
+}
+
+func TestNewNoteGraphExporter(t *testing.T) {
+	graphMLExporter, err := NewNoteGraphExporter(GraphML, ExportOptions{})
+	assert.Nil(t, err)
+	assert.IsType(t, &NoteGraphUtil{}, graphMLExporter)
+
+	yFilesExporter, err := NewNoteGraphExporter(GraphML, ExportOptions{YFiles: true, NodeColorBy: CommunityColor})
+	assert.Nil(t, err)
+	assert.True(t, yFilesExporter.(*NoteGraphUtil).YFiles)
+	assert.Equal(t, CommunityColor, yFilesExporter.(*NoteGraphUtil).NodeColorBy)
+
+	gexfExporter, err := NewNoteGraphExporter(GEXF, ExportOptions{})
+	assert.Nil(t, err)
+	assert.IsType(t, &GEXFUtil{}, gexfExporter)
+
+	dotExporter, err := NewNoteGraphExporter(DOT, ExportOptions{ClusterNotebooks: true})
+	assert.Nil(t, err)
+	assert.Equal(t, NewDOTUtil(true), dotExporter)
+
+	svgExporter, err := NewNoteGraphExporter(SVG, ExportOptions{Labels: true, LayoutIterations: 50})
+	assert.Nil(t, err)
+	assert.Equal(t, NewSVGUtil(true, 50), svgExporter)
+}
+
+func TestAddNoteAttributes(t *testing.T) {
+	exportAttributes := ExportAttributes{}
+	exportAttributes.AddNoteAttributes(NoteAttribute{ID: "node-a", Values: map[string]string{"A": "1"}}, NoteAttribute{ID: "node-b"})
+	exportAttributes.AddNoteAttributes(NoteAttribute{ID: "node-a", Values: map[string]string{"A": "2"}})
+	exportAttributes.AddNoteLinkAttributes(NoteLinkAttribute{ID: "edge-a"}, NoteLinkAttribute{ID: "edge-a", Name: "a"})
+
+	assert.Equal(t, []NoteAttribute{{ID: "node-a", Values: map[string]string{"A": "2"}}, {ID: "node-b"}}, exportAttributes.NoteAttributes)
+	assert.Equal(t, []NoteLinkAttribute{{ID: "edge-a", Name: "a"}}, exportAttributes.NoteLinkAttributes)
+}
+
+func TestTypedAttributeValue(t *testing.T) {
+	assert.Equal(t, true, TypedAttributeValue("boolean", "true"))
+	assert.Equal(t, int64(42), TypedAttributeValue("int", "42"))
+	assert.Equal(t, 0.5, TypedAttributeValue("double", "0.5"))
+	assert.Equal(t, "abc", TypedAttributeValue("int", "abc"))
+	assert.Equal(t, "42", TypedAttributeValue("string", "42"))
+}
+
+func TestSaveNoteGraphExport(t *testing.T) {
+	testFile := filepath.Join(os.TempDir(), "testNoteGraph.gexf")
+	defer os.Remove(testFile)
+
+	err := SaveNoteGraphExport(testFile, NewGEXFUtil(), CreateGEXFTestNoteGraph(), true)
+	if err != nil {
+		panic(err)
+	}
+
+	content, err := ioutil.ReadFile(testFile)
+	if err != nil {
+		panic(err)
+	}
+
+	assert.True(t, strings.Contains(string(content), `<node id="A" label="TitleA">`))
+}
+
+func TestSaveNoteGraphExportGraphMLZOnlyGraphML(t *testing.T) {
+	testFile := filepath.Join(os.TempDir(), "testNoteGraphGEXF"+GraphMLZExt)
+	defer os.Remove(testFile)
+
+	err := SaveNoteGraphExport(testFile, NewGEXFUtil(), CreateGEXFTestNoteGraph(), true)
+	if err != nil {
+		panic(err)
+	}
+
+	content, err := ioutil.ReadFile(testFile)
+	if err != nil {
+		panic(err)
+	}
+
+	assert.True(t, strings.Contains(string(content), `<node id="A" label="TitleA">`))
+}
+
+func TestSaveNoteGraphExportGraphMLZ(t *testing.T) {
+	testFile := filepath.Join(os.TempDir(), "testNoteGraph"+GraphMLZExt)
+	defer os.Remove(testFile)
+
+	noteGraph := NewNoteGraph()
+	noteGraph.Add(Note{GUID: "A", Title: "TitleA", URL: *CreateWebLinkURL("A"), URLType: WebLink}, []NoteLink{{SourceNoteGUID: "A", TargetNoteGUID: "B", Text: "A->B", URL: *CreateWebLinkURL("B"), URLType: WebLink}})
+	noteGraph.Add(Note{GUID: "B", Title: "TitleB", URL: *CreateWebLinkURL("B"), URLType: WebLink}, []NoteLink{})
+
+	err := SaveNoteGraphExport(testFile, NewNoteGraphUtil(), noteGraph, true)
+	if err != nil {
+		panic(err)
+	}
+
+	graphMLDocument, err := NewGraphMLUtil().LoadGraphMLDocument(testFile)
+	if err != nil {
+		panic(err)
+	}
+
+	loadedNoteGraph, err := NewNoteGraphUtil().ConvertGraphMLDocument(graphMLDocument)
+	if err != nil {
+		panic(err)
+	}
+
+	assert.Equal(t, noteGraph, loadedNoteGraph)
+}
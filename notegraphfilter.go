@@ -0,0 +1,611 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Enum of all FilterFieldTypes
+const (
+	StringField  FilterFieldType = iota // compared with ==, !=, in, and contains (case-insensitive substring)
+	NumberField  FilterFieldType = iota // compared with ==, !=, <, <=, >, >=, and in
+	TimeField    FilterFieldType = iota // compared with ==, !=, <, <=, >, >= against whole days (2006-01-02) or RFC3339 times
+	ListField    FilterFieldType = iota // compared with contains (one value) and in (any of the values), case-insensitive
+	URLTypeField FilterFieldType = iota // compared with ==, !=, and in against URLType names
+)
+
+// FilterFieldType identifies the type of a field in filter expressions and the operators supported for the field
+type FilterFieldType int
+
+func (fft FilterFieldType) String() string {
+	return [...]string{"string", "number", "time", "list", "URLType"}[fft]
+}
+
+// NoteFilterFields are the fields of Notes available in filter expressions, degrees are based on valid NoteLinks
+var NoteFilterFields = map[string]FilterFieldType{
+	"guid":      StringField,
+	"title":     StringField,
+	"notebook":  StringField,
+	"url":       StringField,
+	"tags":      ListField,
+	"created":   TimeField,
+	"updated":   TimeField,
+	"degree":    NumberField,
+	"indegree":  NumberField,
+	"outdegree": NumberField,
+}
+
+// NoteLinkFilterFields are the fields of NoteLinks available in filter expressions, the fields of the source and target Note are
+// available with source. and target. prefix
+var NoteLinkFilterFields = map[string]FilterFieldType{
+	"link.type": URLTypeField,
+	"link.text": StringField,
+	"link.url":  StringField,
+}
+
+func init() {
+	for field, fieldType := range NoteFilterFields {
+		NoteLinkFilterFields["source."+field] = fieldType
+		NoteLinkFilterFields["target."+field] = fieldType
+	}
+}
+
+// FilterValues are the values of the fields of a Note or NoteLink a filter expression is evaluated against
+type FilterValues map[string]interface{}
+
+// FilterExpression is a parsed filter expression that matches Notes or NoteLinks
+type FilterExpression interface {
+	Matches(filterValues FilterValues) bool
+}
+
+// andExpression matches if both expressions match
+type andExpression struct {
+	left  FilterExpression
+	right FilterExpression
+}
+
+// Matches returns true if both expressions match
+func (ae andExpression) Matches(filterValues FilterValues) bool {
+	return ae.left.Matches(filterValues) && ae.right.Matches(filterValues)
+}
+
+// orExpression matches if either expression matches
+type orExpression struct {
+	left  FilterExpression
+	right FilterExpression
+}
+
+// Matches returns true if either expression matches
+func (oe orExpression) Matches(filterValues FilterValues) bool {
+	return oe.left.Matches(filterValues) || oe.right.Matches(filterValues)
+}
+
+// notExpression matches if the expression does not match
+type notExpression struct {
+	expression FilterExpression
+}
+
+// Matches returns true if the expression does not match
+func (ne notExpression) Matches(filterValues FilterValues) bool {
+	return !ne.expression.Matches(filterValues)
+}
+
+// comparisonExpression compares the value of a field with one or more operands of the type of the field
+type comparisonExpression struct {
+	field     string
+	fieldType FilterFieldType
+	operator  string
+	operands  []interface{}
+}
+
+// Matches returns true if the value of the field satisfies the comparison, comparisons with missing values never match
+func (ce comparisonExpression) Matches(filterValues FilterValues) bool {
+	value, found := filterValues[ce.field]
+	if !found {
+		return false
+	}
+
+	switch ce.operator {
+	case "in":
+		for _, operand := range ce.operands {
+			if ce.compare(value, operand, "==") {
+				return true
+			}
+		}
+
+		return false
+	default:
+		return ce.compare(value, ce.operands[0], ce.operator)
+	}
+}
+
+// compare compares the value with the operand using the operator
+func (ce comparisonExpression) compare(value, operand interface{}, operator string) bool {
+	switch ce.fieldType {
+	case StringField, URLTypeField:
+		if operator == "contains" {
+			return strings.Contains(strings.ToLower(value.(string)), strings.ToLower(operand.(string)))
+		}
+
+		return ce.compareOrder(strings.Compare(value.(string), operand.(string)), operator)
+	case NumberField:
+		return ce.compareOrder(value.(int)-operand.(int), operator)
+	case TimeField:
+		if value.(time.Time).IsZero() {
+			return false
+		}
+
+		if day, isDay := operand.(filterDay); isDay {
+			return ce.compareOrder(day.Compare(value.(time.Time)), operator)
+		}
+
+		return ce.compareOrder(value.(time.Time).Compare(operand.(time.Time)), operator)
+	case ListField:
+		for _, element := range value.([]string) {
+			if strings.EqualFold(element, operand.(string)) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// filterDay is a date operand of a TimeField comparison which covers the whole day from midnight UTC
+type filterDay struct {
+	start time.Time
+}
+
+// Compare returns -1 if the time is before the day, 0 if it is during the day, and +1 if it is on or after the next day
+func (fd filterDay) Compare(value time.Time) int {
+	if value.Before(fd.start) {
+		return -1
+	} else if value.Before(fd.start.AddDate(0, 0, 1)) {
+		return 0
+	}
+
+	return 1
+}
+
+// compareOrder returns true if the order of value and operand (negative, zero, or positive) satisfies the operator
+func (ce comparisonExpression) compareOrder(order int, operator string) bool {
+	switch operator {
+	case "==":
+		return order == 0
+	case "!=":
+		return order != 0
+	case "<":
+		return order < 0
+	case "<=":
+		return order <= 0
+	case ">":
+		return order > 0
+	case ">=":
+		return order >= 0
+	}
+
+	return false
+}
+
+// filterOperators are the operators supported for each FilterFieldType
+var filterOperators = map[FilterFieldType][]string{
+	StringField:  {"==", "!=", "in", "contains"},
+	NumberField:  {"==", "!=", "<", "<=", ">", ">=", "in"},
+	TimeField:    {"==", "!=", "<", "<=", ">", ">="},
+	ListField:    {"in", "contains"},
+	URLTypeField: {"==", "!=", "in"},
+}
+
+// filterToken is a lexical token of a filter expression with its position (starting at 1)
+type filterToken struct {
+	text     string
+	quoted   bool
+	position int
+}
+
+// filterParser is a recursive descent parser for filter expressions
+type filterParser struct {
+	expression string
+	fields     map[string]FilterFieldType
+	tokens     []filterToken
+	index      int
+}
+
+// ParseFilterExpression parses the filter expression with the fields available for filtering, filter expressions combine
+// comparisons such as notebook == "Work", updated > 2024-01-01, or link.type in [AppLink, WebLink] with &&, ||, !, and parentheses
+func ParseFilterExpression(expression string, fields map[string]FilterFieldType) (FilterExpression, error) {
+	filterParser := &filterParser{expression: expression, fields: fields}
+	tokenizeErr := filterParser.tokenize()
+	if tokenizeErr != nil {
+		return nil, tokenizeErr
+	}
+
+	filterExpression, parseErr := filterParser.parseOr()
+	if parseErr != nil {
+		return nil, parseErr
+	}
+
+	if token := filterParser.peek(); token != nil {
+		return nil, filterParser.errorf(token.position, "unexpected [%s], expected && or ||", token.text)
+	}
+
+	return filterExpression, nil
+}
+
+// errorf creates the error for the filter expression at the position
+func (fp *filterParser) errorf(position int, format string, args ...interface{}) error {
+	return fmt.Errorf("Invalid filter expression [%s] at position %d: %s", fp.expression, position, fmt.Sprintf(format, args...))
+}
+
+// tokenize splits the filter expression into operators, punctuation, quoted strings, and words
+func (fp *filterParser) tokenize() error {
+	runes := []rune(fp.expression)
+	for index := 0; index < len(runes); {
+		character := runes[index]
+		if unicode.IsSpace(character) {
+			index++
+			continue
+		}
+
+		if index+1 < len(runes) {
+			twoCharacters := string(runes[index : index+2])
+			if twoCharacters == "&&" || twoCharacters == "||" || twoCharacters == "==" || twoCharacters == "!=" || twoCharacters == "<=" || twoCharacters == ">=" {
+				fp.tokens = append(fp.tokens, filterToken{text: twoCharacters, position: index + 1})
+				index += 2
+				continue
+			}
+		}
+
+		if strings.ContainsRune("()[],!<>", character) {
+			fp.tokens = append(fp.tokens, filterToken{text: string(character), position: index + 1})
+			index++
+			continue
+		}
+
+		if character == '"' {
+			end := index + 1
+			for end < len(runes) && runes[end] != '"' {
+				if runes[end] == '\\' {
+					end++
+				}
+				end++
+			}
+
+			if end >= len(runes) {
+				return fp.errorf(index+1, "unterminated string")
+			}
+
+			text, unquoteErr := strconv.Unquote(string(runes[index : end+1]))
+			if unquoteErr != nil {
+				return fp.errorf(index+1, "invalid string %s", string(runes[index:end+1]))
+			}
+
+			fp.tokens = append(fp.tokens, filterToken{text: text, quoted: true, position: index + 1})
+			index = end + 1
+			continue
+		}
+
+		end := index
+		for end < len(runes) && (unicode.IsLetter(runes[end]) || unicode.IsDigit(runes[end]) || strings.ContainsRune("_.:+-", runes[end])) {
+			end++
+		}
+
+		if end == index {
+			return fp.errorf(index+1, "unexpected character [%c]", character)
+		}
+
+		fp.tokens = append(fp.tokens, filterToken{text: string(runes[index:end]), position: index + 1})
+		index = end
+	}
+
+	return nil
+}
+
+// peek returns the next token without consuming it, nil at the end of the filter expression
+func (fp *filterParser) peek() *filterToken {
+	if fp.index < len(fp.tokens) {
+		return &fp.tokens[fp.index]
+	}
+
+	return nil
+}
+
+// next consumes the next token, returns an error describing what was expected at the end of the filter expression
+func (fp *filterParser) next(expected string) (*filterToken, error) {
+	token := fp.peek()
+	if token == nil {
+		return nil, fp.errorf(len([]rune(fp.expression))+1, "unexpected end of expression, expected %s", expected)
+	}
+
+	fp.index++
+	return token, nil
+}
+
+// isOperator returns true if the next token is the unquoted operator
+func (fp *filterParser) isOperator(operator string) bool {
+	token := fp.peek()
+	return token != nil && !token.quoted && token.text == operator
+}
+
+// parseOr parses comparisons combined with ||
+func (fp *filterParser) parseOr() (FilterExpression, error) {
+	left, err := fp.parseAnd()
+	for err == nil && fp.isOperator("||") {
+		fp.index++
+		var right FilterExpression
+		right, err = fp.parseAnd()
+		left = orExpression{left: left, right: right}
+	}
+
+	return left, err
+}
+
+// parseAnd parses comparisons combined with &&
+func (fp *filterParser) parseAnd() (FilterExpression, error) {
+	left, err := fp.parseNot()
+	for err == nil && fp.isOperator("&&") {
+		fp.index++
+		var right FilterExpression
+		right, err = fp.parseNot()
+		left = andExpression{left: left, right: right}
+	}
+
+	return left, err
+}
+
+// parseNot parses negated expressions, expressions in parentheses, and comparisons
+func (fp *filterParser) parseNot() (FilterExpression, error) {
+	if fp.isOperator("!") {
+		fp.index++
+		expression, err := fp.parseNot()
+		return notExpression{expression: expression}, err
+	}
+
+	if fp.isOperator("(") {
+		fp.index++
+		expression, err := fp.parseOr()
+		if err != nil {
+			return nil, err
+		}
+
+		token, err := fp.next(")")
+		if err != nil {
+			return nil, err
+		} else if token.quoted || token.text != ")" {
+			return nil, fp.errorf(token.position, "unexpected [%s], expected )", token.text)
+		}
+
+		return expression, nil
+	}
+
+	return fp.parseComparison()
+}
+
+// parseComparison parses the comparison of a field with an operand or a list of operands
+func (fp *filterParser) parseComparison() (FilterExpression, error) {
+	fieldToken, err := fp.next("field")
+	if err != nil {
+		return nil, err
+	}
+
+	fieldType, found := fp.fields[fieldToken.text]
+	if fieldToken.quoted || !found {
+		return nil, fp.errorf(fieldToken.position, "unknown field [%s], expected one of %s", fieldToken.text, strings.Join(fp.fieldNames(), ", "))
+	}
+
+	operatorToken, err := fp.next("operator")
+	if err != nil {
+		return nil, err
+	}
+
+	if operatorToken.quoted || !fp.supportsOperator(fieldType, operatorToken.text) {
+		return nil, fp.errorf(operatorToken.position, "unsupported operator [%s] for %s field [%s], expected one of %s", operatorToken.text, fieldType, fieldToken.text, strings.Join(filterOperators[fieldType], " "))
+	}
+
+	comparison := comparisonExpression{field: fieldToken.text, fieldType: fieldType, operator: operatorToken.text}
+	if operatorToken.text != "in" {
+		operand, err := fp.parseOperand(fieldType)
+		if err != nil {
+			return nil, err
+		}
+
+		comparison.operands = []interface{}{operand}
+		return comparison, nil
+	}
+
+	token, err := fp.next("[")
+	if err != nil {
+		return nil, err
+	} else if token.quoted || token.text != "[" {
+		return nil, fp.errorf(token.position, "unexpected [%s], expected [", token.text)
+	}
+
+	for {
+		operand, err := fp.parseOperand(fieldType)
+		if err != nil {
+			return nil, err
+		}
+		comparison.operands = append(comparison.operands, operand)
+
+		token, err := fp.next(", or ]")
+		if err != nil {
+			return nil, err
+		} else if !token.quoted && token.text == "]" {
+			return comparison, nil
+		} else if token.quoted || token.text != "," {
+			return nil, fp.errorf(token.position, "unexpected [%s], expected , or ]", token.text)
+		}
+	}
+}
+
+// parseOperand parses an operand of the FilterFieldType
+func (fp *filterParser) parseOperand(fieldType FilterFieldType) (interface{}, error) {
+	token, err := fp.next(fieldType.String() + " value")
+	if err != nil {
+		return nil, err
+	}
+
+	if !token.quoted && strings.ContainsAny(token.text, "()[],!<>=&|") {
+		return nil, fp.errorf(token.position, "unexpected [%s], expected %s value", token.text, fieldType)
+	}
+
+	switch fieldType {
+	case NumberField:
+		number, parseErr := strconv.Atoi(token.text)
+		if parseErr != nil {
+			return nil, fp.errorf(token.position, "invalid number [%s]", token.text)
+		}
+
+		return number, nil
+	case TimeField:
+		if value, parseErr := time.Parse("2006-01-02", token.text); parseErr == nil {
+			return filterDay{start: value}, nil
+		} else if value, parseErr := time.Parse(time.RFC3339, token.text); parseErr == nil {
+			return value, nil
+		}
+
+		return nil, fp.errorf(token.position, "invalid time [%s], expected 2006-01-02 or 2006-01-02T15:04:05Z07:00", token.text)
+	case URLTypeField:
+		urlType, parseErr := NewURLType(token.text)
+		if parseErr != nil {
+			return nil, fp.errorf(token.position, "invalid URLType [%s], expected AppLink, WebLink, PublicLink, or ShortenedLink", token.text)
+		}
+
+		return urlType.String(), nil
+	}
+
+	return token.text, nil
+}
+
+// supportsOperator returns true if the operator is supported for the FilterFieldType
+func (fp *filterParser) supportsOperator(fieldType FilterFieldType, operator string) bool {
+	for _, supportedOperator := range filterOperators[fieldType] {
+		if supportedOperator == operator {
+			return true
+		}
+	}
+
+	return false
+}
+
+// fieldNames returns the sorted names of the fields available for filtering
+func (fp *filterParser) fieldNames() []string {
+	fieldNames := []string{}
+	for field := range fp.fields {
+		fieldNames = append(fieldNames, field)
+	}
+
+	sort.Strings(fieldNames)
+	return fieldNames
+}
+
+// NoteGraphFilter reduces a NoteGraph to the Notes and NoteLinks matching filter expressions
+type NoteGraphFilter struct {
+	NoteExpression     FilterExpression // nil matches all Notes
+	NoteLinkExpression FilterExpression // nil matches all NoteLinks
+}
+
+// NewNoteGraphFilter creates a new instance of NoteGraphFilter from the Note and NoteLink filter expressions, empty filter
+// expressions match all Notes or NoteLinks
+func NewNoteGraphFilter(noteFilter, noteLinkFilter string) (*NoteGraphFilter, error) {
+	noteGraphFilter := &NoteGraphFilter{}
+	if strings.TrimSpace(noteFilter) != "" {
+		noteExpression, err := ParseFilterExpression(noteFilter, NoteFilterFields)
+		if err != nil {
+			return nil, err
+		}
+		noteGraphFilter.NoteExpression = noteExpression
+	}
+
+	if strings.TrimSpace(noteLinkFilter) != "" {
+		noteLinkExpression, err := ParseFilterExpression(noteLinkFilter, NoteLinkFilterFields)
+		if err != nil {
+			return nil, err
+		}
+		noteGraphFilter.NoteLinkExpression = noteLinkExpression
+	}
+
+	return noteGraphFilter, nil
+}
+
+// FilterNoteGraph returns the NoteGraph with the Notes matching the Note filter expression and the NoteLinks matching the NoteLink
+// filter expression, NoteLinks from or to Notes that do not match are removed, broken NoteLinks are kept if they match
+func (ngf *NoteGraphFilter) FilterNoteGraph(noteGraph *NoteGraph) *NoteGraph {
+	inDegrees := map[string]int{}
+	outDegrees := map[string]int{}
+	for _, noteLink := range *noteGraph.GetValidNoteLinks() {
+		outDegrees[noteLink.SourceNoteGUID]++
+		inDegrees[noteLink.TargetNoteGUID]++
+	}
+
+	noteValues := map[string]FilterValues{}
+	for _, note := range *noteGraph.GetNotes() {
+		noteValues[note.GUID] = ngf.NoteFilterValues(note, inDegrees[note.GUID], outDegrees[note.GUID])
+	}
+
+	matchingNoteGUIDs := map[string]bool{}
+	for noteGUID, filterValues := range noteValues {
+		matchingNoteGUIDs[noteGUID] = ngf.NoteExpression == nil || ngf.NoteExpression.Matches(filterValues)
+	}
+
+	filteredNoteGraph := NewNoteGraph()
+	for _, note := range *noteGraph.GetNotes() {
+		if matchingNoteGUIDs[note.GUID] {
+			filteredNoteGraph.Add(note, []NoteLink{})
+		}
+	}
+
+	for _, noteLink := range *noteGraph.GetNoteLinks() {
+		if matches, found := matchingNoteGUIDs[noteLink.SourceNoteGUID]; found && !matches {
+			continue
+		} else if matches, found := matchingNoteGUIDs[noteLink.TargetNoteGUID]; found && !matches {
+			continue
+		}
+
+		if ngf.NoteLinkExpression == nil || ngf.NoteLinkExpression.Matches(ngf.NoteLinkFilterValues(noteLink, noteValues)) {
+			filteredNoteGraph.NoteLinks = append(filteredNoteGraph.NoteLinks, noteLink)
+		}
+	}
+
+	logrus.Infof("Filtered NoteGraph with [%d] Notes and [%d] NoteLinks to [%d] Notes and [%d] NoteLinks", len(noteGraph.Notes), len(noteGraph.NoteLinks), len(filteredNoteGraph.Notes), len(filteredNoteGraph.NoteLinks))
+	return filteredNoteGraph
+}
+
+// NoteFilterValues returns the FilterValues of the Note with the number of incoming and outgoing valid NoteLinks
+func (ngf *NoteGraphFilter) NoteFilterValues(note Note, inDegree, outDegree int) FilterValues {
+	return FilterValues{
+		"guid":      note.GUID,
+		"title":     note.Title,
+		"notebook":  note.Notebook,
+		"url":       note.URL.String(),
+		"tags":      note.Tags,
+		"created":   note.Created,
+		"updated":   note.Updated,
+		"degree":    inDegree + outDegree,
+		"indegree":  inDegree,
+		"outdegree": outDegree,
+	}
+}
+
+// NoteLinkFilterValues returns the FilterValues of the NoteLink with the FilterValues of the source and target Note if they exist
+func (ngf *NoteGraphFilter) NoteLinkFilterValues(noteLink NoteLink, noteValues map[string]FilterValues) FilterValues {
+	filterValues := FilterValues{
+		"link.type": noteLink.URLType.String(),
+		"link.text": noteLink.Text,
+		"link.url":  noteLink.URL.String(),
+	}
+
+	for prefix, noteGUID := range map[string]string{"source.": noteLink.SourceNoteGUID, "target.": noteLink.TargetNoteGUID} {
+		for field, value := range noteValues[noteGUID] {
+			filterValues[prefix+field] = value
+		}
+	}
+
+	return filterValues
+}
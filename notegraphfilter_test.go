@@ -0,0 +1,130 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func CreateFilterTestNoteGraph() *NoteGraph {
+	// A (Work) links to B (Work) twice and to C (Home), B links to C, C links to a missing Note
+	noteGraph := NewNoteGraph()
+	noteGraph.Add(Note{GUID: "A", Title: "Project Plan", Notebook: "Work", Tags: []string{"project"}, Updated: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)}, []NoteLink{
+		{SourceNoteGUID: "A", TargetNoteGUID: "B", Text: "tasks", URLType: AppLink},
+		{SourceNoteGUID: "A", TargetNoteGUID: "B", Text: "more tasks", URLType: WebLink},
+		{SourceNoteGUID: "A", TargetNoteGUID: "C", Text: "shopping", URLType: PublicLink}})
+	noteGraph.Add(Note{GUID: "B", Title: "Tasks", Notebook: "Work", Updated: time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)}, []NoteLink{
+		{SourceNoteGUID: "B", TargetNoteGUID: "C", Text: "groceries", URLType: AppLink}})
+	noteGraph.Add(Note{GUID: "C", Title: "Groceries", Notebook: "Home", Tags: []string{"home", "shopping"}}, []NoteLink{
+		{SourceNoteGUID: "C", TargetNoteGUID: "X", Text: "missing", URLType: AppLink}})
+	noteGraph.Add(Note{GUID: "D", Title: "Unlinked", Notebook: "Work"}, []NoteLink{})
+
+	return noteGraph
+}
+
+func FilterNoteGUIDs(t *testing.T, noteFilter string) []string {
+	noteGraphFilter, err := NewNoteGraphFilter(noteFilter, "")
+	assert.Nil(t, err)
+
+	noteGUIDs := []string{}
+	for _, note := range *noteGraphFilter.FilterNoteGraph(CreateFilterTestNoteGraph()).GetNotes() {
+		noteGUIDs = append(noteGUIDs, note.GUID)
+	}
+
+	return noteGUIDs
+}
+
+func TestFilterNoteGraphNotes(t *testing.T) {
+	assert.Equal(t, []string{"A", "B", "C", "D"}, FilterNoteGUIDs(t, ""))
+	assert.Equal(t, []string{"A", "B", "D"}, FilterNoteGUIDs(t, `notebook == "Work"`))
+	assert.Equal(t, []string{"A", "B"}, FilterNoteGUIDs(t, `notebook == "Work" && degree >= 2`))
+	assert.Equal(t, []string{"A"}, FilterNoteGUIDs(t, `notebook == "Work" && updated > 2024-01-01 && degree >= 2`))
+	assert.Equal(t, []string{"B", "C"}, FilterNoteGUIDs(t, `indegree >= 1 && !(title contains "plan")`))
+	assert.Equal(t, []string{"A", "C"}, FilterNoteGUIDs(t, `tags in [project, "shopping"]`))
+	assert.Equal(t, []string{"C", "D"}, FilterNoteGUIDs(t, `notebook == Home || outdegree == 0 && guid != "C"`))
+	assert.Equal(t, []string{"B"}, FilterNoteGUIDs(t, `updated < 2024-01-01T00:00:00Z`))
+}
+
+func TestFilterNoteGraphNotesListsCaseInsensitive(t *testing.T) {
+	assert.Equal(t, []string{"A"}, FilterNoteGUIDs(t, `tags contains "Project"`))
+	assert.Equal(t, []string{"A", "C"}, FilterNoteGUIDs(t, `tags in [PROJECT, "Shopping"]`))
+}
+
+func TestFilterNoteGraphNotesDates(t *testing.T) {
+	noteGraph := NewNoteGraph()
+	noteGraph.Add(Note{GUID: "A", Updated: time.Date(2023, 12, 31, 23, 59, 59, 0, time.UTC)}, []NoteLink{})
+	noteGraph.Add(Note{GUID: "B", Updated: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}, []NoteLink{})
+	noteGraph.Add(Note{GUID: "C", Updated: time.Date(2024, 1, 1, 15, 30, 0, 0, time.UTC)}, []NoteLink{})
+	noteGraph.Add(Note{GUID: "D", Updated: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)}, []NoteLink{})
+
+	for noteFilter, expectedNoteGUIDs := range map[string][]string{
+		"updated == 2024-01-01":           {"B", "C"},
+		"updated != 2024-01-01":           {"A", "D"},
+		"updated > 2024-01-01":            {"D"},
+		"updated >= 2024-01-01":           {"B", "C", "D"},
+		"updated < 2024-01-01":            {"A"},
+		"updated <= 2024-01-01":           {"A", "B", "C"},
+		"updated > 2024-01-01T00:00:00Z":  {"C", "D"},
+		"updated == 2024-01-01T15:30:00Z": {"C"},
+	} {
+		noteGraphFilter, err := NewNoteGraphFilter(noteFilter, "")
+		assert.Nil(t, err)
+
+		noteGUIDs := []string{}
+		for _, note := range *noteGraphFilter.FilterNoteGraph(noteGraph).GetNotes() {
+			noteGUIDs = append(noteGUIDs, note.GUID)
+		}
+
+		assert.Equal(t, expectedNoteGUIDs, noteGUIDs, noteFilter)
+	}
+}
+
+func TestFilterNoteGraphNoteLinks(t *testing.T) {
+	noteGraphFilter, err := NewNoteGraphFilter(`notebook != "Home"`, "link.type in [AppLink, WebLink]")
+	assert.Nil(t, err)
+
+	filteredNoteGraph := noteGraphFilter.FilterNoteGraph(CreateFilterTestNoteGraph())
+
+	assert.Equal(t, 3, len(filteredNoteGraph.Notes))
+	assert.Equal(t, []NoteLink{
+		{SourceNoteGUID: "A", TargetNoteGUID: "B", Text: "tasks", URLType: AppLink},
+		{SourceNoteGUID: "A", TargetNoteGUID: "B", Text: "more tasks", URLType: WebLink}}, filteredNoteGraph.NoteLinks)
+
+	noteGraphFilter, err = NewNoteGraphFilter("", `target.notebook == "Home" || link.text contains "MISS"`)
+	assert.Nil(t, err)
+
+	filteredNoteGraph = noteGraphFilter.FilterNoteGraph(CreateFilterTestNoteGraph())
+
+	assert.Equal(t, 4, len(filteredNoteGraph.Notes))
+	assert.Equal(t, []string{"shopping", "groceries", "missing"}, []string{filteredNoteGraph.NoteLinks[0].Text, filteredNoteGraph.NoteLinks[1].Text, filteredNoteGraph.NoteLinks[2].Text})
+}
+
+func TestParseFilterExpressionErrors(t *testing.T) {
+	for expression, message := range map[string]string{
+		`notebok == "Work"`:           `Invalid filter expression [notebok == "Work"] at position 1: unknown field [notebok], expected one of created, degree, guid,`,
+		`degree >= two`:               `Invalid filter expression [degree >= two] at position 11: invalid number [two]`,
+		`updated > 2024-13-01`:        `Invalid filter expression [updated > 2024-13-01] at position 11: invalid time [2024-13-01]`,
+		`title < "A"`:                 `Invalid filter expression [title < "A"] at position 7: unsupported operator [<] for string field [title], expected one of == != in contains`,
+		`notebook == "Work`:           `Invalid filter expression [notebook == "Work] at position 13: unterminated string`,
+		`notebook ==`:                 `Invalid filter expression [notebook ==] at position 12: unexpected end of expression, expected string value`,
+		`(degree > 1`:                 `Invalid filter expression [(degree > 1] at position 12: unexpected end of expression, expected )`,
+		`degree > 1 degree < 3`:       `Invalid filter expression [degree > 1 degree < 3] at position 12: unexpected [degree], expected && or ||`,
+		`degree in [1, 2`:             `Invalid filter expression [degree in [1, 2] at position 16: unexpected end of expression, expected , or ]`,
+		`notebook == "Work" & x`:      `Invalid filter expression [notebook == "Work" & x] at position 20: unexpected character [&]`,
+		`notebook == && degree > 1`:   `Invalid filter expression [notebook == && degree > 1] at position 13: unexpected [&&], expected string value`,
+		`link.type == "Wrong"`:        `Invalid filter expression [link.type == "Wrong"] at position 1: unknown field [link.type]`,
+		`source.degree > 1 && x == 1`: ``,
+	} {
+		_, err := ParseFilterExpression(expression, NoteFilterFields)
+		if assert.NotNil(t, err, expression) && message != "" {
+			assert.Contains(t, err.Error(), message)
+		}
+	}
+
+	_, err := ParseFilterExpression(`link.type == Wrong`, NoteLinkFilterFields)
+	assert.Contains(t, err.Error(), `at position 14: invalid URLType [Wrong]`)
+
+	_, err = ParseFilterExpression(`source.degree > 1 && link.type == AppLink`, NoteLinkFilterFields)
+	assert.Nil(t, err)
+}
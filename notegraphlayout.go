@@ -0,0 +1,294 @@
+package main
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"strconv"
+
+	"github.com/sirupsen/logrus"
+)
+
+// NodeXID is the ID of the GraphML attribute used for the x coordinate of the layout of nodes in the graph
+const NodeXID = "node-x"
+
+// NodeXName is the name of the GraphML attribute used for the x coordinate of the layout of nodes in the graph
+const NodeXName = "x"
+
+// NodeYID is the ID of the GraphML attribute used for the y coordinate of the layout of nodes in the graph
+const NodeYID = "node-y"
+
+// NodeYName is the name of the GraphML attribute used for the y coordinate of the layout of nodes in the graph
+const NodeYName = "y"
+
+// DefaultLayoutIterations is the default number of iterations of the force-directed layout
+const DefaultLayoutIterations = 300
+
+// LayoutNodeDistance is the optimal distance between Notes of the force-directed layout
+const LayoutNodeDistance = 100.0
+
+// LayoutGravity is the strength of the force pulling all Notes to the center to keep disconnected Notes close together
+const LayoutGravity = 1.0
+
+// LayoutTheta is the accuracy of the Barnes-Hut approximation of the repulsion between Notes, all Notes of a quad repel a Note as a
+// single Note at their centre of mass if the size of the quad is less than LayoutTheta times the distance to the centre of mass
+const LayoutTheta = 1.0
+
+// layoutQuadMaxDepth limits the depth of the quadtree for Notes at the same position
+const layoutQuadMaxDepth = 32
+
+// NotePosition is the position of a Note in the layout of a NoteGraph
+type NotePosition struct {
+	X float64
+	Y float64
+}
+
+// NoteGraphLayouter computes a force-directed layout of a NoteGraph with the Fruchterman-Reingold algorithm, the repulsion between
+// all Notes is approximated with a Barnes-Hut quadtree so that each iteration takes O(n log n) instead of O(n^2) time
+type NoteGraphLayouter struct {
+	Iterations int
+	Seed       int64
+	Theta      float64
+}
+
+// NewNoteGraphLayouter creates a new instance of NoteGraphLayouter, DefaultLayoutIterations are used if iterations is not positive
+func NewNoteGraphLayouter(iterations int) *NoteGraphLayouter {
+	if iterations <= 0 {
+		iterations = DefaultLayoutIterations
+	}
+
+	return &NoteGraphLayouter{Iterations: iterations, Seed: 1, Theta: LayoutTheta}
+}
+
+// LayoutNoteGraph computes the positions of the Notes of the NoteGraph based on the valid NoteLinks, the layout is deterministic
+// for the same NoteGraph
+func (ngl *NoteGraphLayouter) LayoutNoteGraph(noteGraph *NoteGraph, allNotes bool) map[string]NotePosition {
+	notes := *noteGraph.GetLinkedNotes()
+	if allNotes {
+		notes = *noteGraph.GetNotes()
+	}
+
+	sort.Slice(notes, func(i, j int) bool {
+		return notes[i].GUID < notes[j].GUID
+	})
+
+	noteLinks := *noteGraph.GetValidNoteLinks()
+
+	logrus.Infof("Computing force-directed layout of NoteGraph with [%d] Notes and [%d] NoteLinks in [%d] iterations", len(notes), len(noteLinks), ngl.Iterations)
+
+	indexes := map[string]int{}
+	for index, note := range notes {
+		indexes[note.GUID] = index
+	}
+
+	edges := [][2]int{}
+	for _, noteLink := range noteLinks {
+		sourceIndex, sourceFound := indexes[noteLink.SourceNoteGUID]
+		targetIndex, targetFound := indexes[noteLink.TargetNoteGUID]
+		if sourceFound && targetFound && sourceIndex != targetIndex {
+			edges = append(edges, [2]int{sourceIndex, targetIndex})
+		}
+	}
+
+	size := LayoutNodeDistance * math.Sqrt(float64(len(notes)+1))
+	random := rand.New(rand.NewSource(ngl.Seed))
+	xs := make([]float64, len(notes))
+	ys := make([]float64, len(notes))
+	for index := range notes {
+		xs[index] = (random.Float64() - 0.5) * size
+		ys[index] = (random.Float64() - 0.5) * size
+	}
+
+	k := LayoutNodeDistance
+	dxs := make([]float64, len(notes))
+	dys := make([]float64, len(notes))
+	for iteration := 0; iteration < ngl.Iterations; iteration++ {
+		temperature := size / 10 * (1 - float64(iteration)/float64(ngl.Iterations))
+		for index := range notes {
+			dxs[index] = -LayoutGravity * xs[index]
+			dys[index] = -LayoutGravity * ys[index]
+		}
+
+		quad := ngl.CreateQuadtree(xs, ys)
+		for index := range notes {
+			dx, dy := ngl.Repulsion(quad, index, xs, ys, k, random)
+			dxs[index] += dx
+			dys[index] += dy
+		}
+
+		for _, edge := range edges {
+			dx, dy, distance := ngl.Distance(xs[edge[0]]-xs[edge[1]], ys[edge[0]]-ys[edge[1]], random)
+			force := distance * distance / k
+			dxs[edge[0]] -= dx / distance * force
+			dys[edge[0]] -= dy / distance * force
+			dxs[edge[1]] += dx / distance * force
+			dys[edge[1]] += dy / distance * force
+		}
+
+		for index := range notes {
+			displacement := math.Hypot(dxs[index], dys[index])
+			if displacement > 0 {
+				limited := math.Min(displacement, temperature)
+				xs[index] += dxs[index] / displacement * limited
+				ys[index] += dys[index] / displacement * limited
+			}
+		}
+	}
+
+	positions := map[string]NotePosition{}
+	for index, note := range notes {
+		positions[note.GUID] = NotePosition{X: xs[index], Y: ys[index]}
+	}
+
+	return positions
+}
+
+// LayoutQuad is a square of the Barnes-Hut quadtree with the number and the centre of mass of the Notes within the square, leaf
+// quads contain the indexes of their Notes
+type LayoutQuad struct {
+	Left     float64
+	Top      float64
+	Size     float64
+	X        float64
+	Y        float64
+	Count    int
+	Indexes  []int
+	Children []*LayoutQuad
+}
+
+// CreateQuadtree creates the Barnes-Hut quadtree of the Notes at the positions
+func (ngl *NoteGraphLayouter) CreateQuadtree(xs, ys []float64) *LayoutQuad {
+	left, top, right, bottom := math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1)
+	indexes := make([]int, len(xs))
+	for index := range xs {
+		left, right = math.Min(left, xs[index]), math.Max(right, xs[index])
+		top, bottom = math.Min(top, ys[index]), math.Max(bottom, ys[index])
+		indexes[index] = index
+	}
+
+	return ngl.createQuad(indexes, xs, ys, left, top, math.Max(right-left, bottom-top)+1, 0)
+}
+
+// createQuad creates the quad with the Notes with the indexes and its child quads with the Notes in each quarter of the quad
+func (ngl *NoteGraphLayouter) createQuad(indexes []int, xs, ys []float64, left, top, size float64, depth int) *LayoutQuad {
+	quad := &LayoutQuad{Left: left, Top: top, Size: size, Count: len(indexes)}
+	for _, index := range indexes {
+		quad.X += xs[index]
+		quad.Y += ys[index]
+	}
+	quad.X /= float64(quad.Count)
+	quad.Y /= float64(quad.Count)
+
+	if quad.Count == 1 || depth >= layoutQuadMaxDepth {
+		quad.Indexes = indexes
+		return quad
+	}
+
+	half := size / 2
+	quarters := [4][]int{}
+	for _, index := range indexes {
+		quarter := 0
+		if xs[index] >= left+half {
+			quarter++
+		}
+		if ys[index] >= top+half {
+			quarter += 2
+		}
+		quarters[quarter] = append(quarters[quarter], index)
+	}
+
+	for quarter, quarterIndexes := range quarters {
+		if len(quarterIndexes) > 0 {
+			quad.Children = append(quad.Children, ngl.createQuad(quarterIndexes, xs, ys, left+float64(quarter%2)*half, top+float64(quarter/2)*half, half, depth+1))
+		}
+	}
+
+	return quad
+}
+
+// Contains returns true if the position is within the quad
+func (lq *LayoutQuad) Contains(x, y float64) bool {
+	return x >= lq.Left && x < lq.Left+lq.Size && y >= lq.Top && y < lq.Top+lq.Size
+}
+
+// Repulsion returns the displacement of the Note with the index by the repulsive force k * k / distance of all other Notes in
+// the quad, distant quads are approximated by their centre of mass
+func (ngl *NoteGraphLayouter) Repulsion(quad *LayoutQuad, index int, xs, ys []float64, k float64, random *rand.Rand) (float64, float64) {
+	if quad.Children == nil {
+		repulsionX, repulsionY := 0.0, 0.0
+		for _, otherIndex := range quad.Indexes {
+			if otherIndex != index {
+				dx, dy, distance := ngl.Distance(xs[index]-xs[otherIndex], ys[index]-ys[otherIndex], random)
+				repulsionX += dx / distance * k * k / distance
+				repulsionY += dy / distance * k * k / distance
+			}
+		}
+
+		return repulsionX, repulsionY
+	}
+
+	dx, dy := xs[index]-quad.X, ys[index]-quad.Y
+	if distance := math.Sqrt(dx*dx + dy*dy); quad.Size < ngl.Theta*distance && !quad.Contains(xs[index], ys[index]) {
+		force := float64(quad.Count) * k * k / distance
+		return dx / distance * force, dy / distance * force
+	}
+
+	repulsionX, repulsionY := 0.0, 0.0
+	for _, child := range quad.Children {
+		childX, childY := ngl.Repulsion(child, index, xs, ys, k, random)
+		repulsionX += childX
+		repulsionY += childY
+	}
+
+	return repulsionX, repulsionY
+}
+
+// Distance returns the vector and distance between two positions, positions at the same location are moved apart randomly
+func (ngl *NoteGraphLayouter) Distance(dx, dy float64, random *rand.Rand) (float64, float64, float64) {
+	distance := math.Sqrt(dx*dx + dy*dy)
+	for distance < 0.01 {
+		dx = random.Float64() - 0.5
+		dy = random.Float64() - 0.5
+		distance = math.Sqrt(dx*dx + dy*dy)
+	}
+
+	return dx, dy, distance
+}
+
+// PositionAttributes returns the NoteAttributes with the x and y coordinate of each Note
+func (ngl *NoteGraphLayouter) PositionAttributes(positions map[string]NotePosition) []NoteAttribute {
+	xValues := map[string]string{}
+	yValues := map[string]string{}
+	for noteGUID, position := range positions {
+		xValues[noteGUID] = strconv.FormatFloat(position.X, 'f', 2, 64)
+		yValues[noteGUID] = strconv.FormatFloat(position.Y, 'f', 2, 64)
+	}
+
+	return []NoteAttribute{
+		{ID: NodeXID, Name: NodeXName, Type: "double", Values: xValues},
+		{ID: NodeYID, Name: NodeYName, Type: "double", Values: yValues}}
+}
+
+// NotePositions returns the positions of all Notes with x and y coordinate in the NoteAttributes
+func (ngl *NoteGraphLayouter) NotePositions(noteAttributes []NoteAttribute) map[string]NotePosition {
+	xValues := map[string]string{}
+	yValues := map[string]string{}
+	for _, noteAttribute := range noteAttributes {
+		if noteAttribute.ID == NodeXID {
+			xValues = noteAttribute.Values
+		} else if noteAttribute.ID == NodeYID {
+			yValues = noteAttribute.Values
+		}
+	}
+
+	positions := map[string]NotePosition{}
+	for noteGUID, xValue := range xValues {
+		x, xErr := strconv.ParseFloat(xValue, 64)
+		y, yErr := strconv.ParseFloat(yValues[noteGUID], 64)
+		if xErr == nil && yErr == nil {
+			positions[noteGUID] = NotePosition{X: x, Y: y}
+		}
+	}
+
+	return positions
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLayoutNoteGraph(t *testing.T) {
+	noteGraphLayouter := NewNoteGraphLayouter(100)
+	positions := noteGraphLayouter.LayoutNoteGraph(CreateHygieneTestNoteGraph(), true)
+
+	assert.Equal(t, 5, len(positions))
+	assert.Equal(t, positions, noteGraphLayouter.LayoutNoteGraph(CreateHygieneTestNoteGraph(), true))
+
+	distance := func(a, b string) float64 {
+		return math.Hypot(positions[a].X-positions[b].X, positions[a].Y-positions[b].Y)
+	}
+	assert.True(t, distance("A", "B") < distance("A", "E"))
+	assert.True(t, distance("C", "D") < distance("C", "E"))
+	for _, position := range positions {
+		assert.False(t, math.IsNaN(position.X) || math.IsNaN(position.Y))
+	}
+}
+
+func TestLayoutNoteGraphLinkedNotes(t *testing.T) {
+	positions := NewNoteGraphLayouter(0).LayoutNoteGraph(CreateHygieneTestNoteGraph(), false)
+
+	assert.Equal(t, 4, len(positions))
+	assert.NotContains(t, positions, "E")
+}
+
+func TestLayoutRepulsion(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	xs := make([]float64, 500)
+	ys := make([]float64, 500)
+	for index := range xs {
+		xs[index] = random.NormFloat64() * 1000
+		ys[index] = random.NormFloat64() * 1000
+	}
+
+	// Theta 0 never approximates quads and results in the exact repulsion
+	exactLayouter := &NoteGraphLayouter{Theta: 0}
+	exactQuadtree := exactLayouter.CreateQuadtree(xs, ys)
+	noteGraphLayouter := NewNoteGraphLayouter(0)
+	quadtree := noteGraphLayouter.CreateQuadtree(xs, ys)
+	assert.Equal(t, len(xs), quadtree.Count)
+
+	for _, index := range []int{0, 100, 499} {
+		exactX, exactY := 0.0, 0.0
+		for otherIndex := range xs {
+			if otherIndex != index {
+				dx, dy := xs[index]-xs[otherIndex], ys[index]-ys[otherIndex]
+				distance := math.Hypot(dx, dy)
+				exactX += dx / distance * LayoutNodeDistance * LayoutNodeDistance / distance
+				exactY += dy / distance * LayoutNodeDistance * LayoutNodeDistance / distance
+			}
+		}
+
+		quadtreeX, quadtreeY := exactLayouter.Repulsion(exactQuadtree, index, xs, ys, LayoutNodeDistance, random)
+		assert.InDelta(t, exactX, quadtreeX, 1e-6)
+		assert.InDelta(t, exactY, quadtreeY, 1e-6)
+
+		approximateX, approximateY := noteGraphLayouter.Repulsion(quadtree, index, xs, ys, LayoutNodeDistance, random)
+		assert.InDelta(t, 0, math.Hypot(approximateX-exactX, approximateY-exactY)/math.Hypot(exactX, exactY), 0.05)
+	}
+}
+
+func TestPositionAttributes(t *testing.T) {
+	noteGraphLayouter := NewNoteGraphLayouter(0)
+	noteAttributes := noteGraphLayouter.PositionAttributes(map[string]NotePosition{"A": {X: 1.5, Y: -2}})
+
+	assert.Equal(t, []NoteAttribute{
+		{ID: NodeXID, Name: NodeXName, Type: "double", Values: map[string]string{"A": "1.50"}},
+		{ID: NodeYID, Name: NodeYName, Type: "double", Values: map[string]string{"A": "-2.00"}}}, noteAttributes)
+	assert.Equal(t, map[string]NotePosition{"A": {X: 1.5, Y: -2}}, noteGraphLayouter.NotePositions(noteAttributes))
+}
+
+func TestNewNoteGraphLayouter(t *testing.T) {
+	assert.Equal(t, DefaultLayoutIterations, NewNoteGraphLayouter(0).Iterations)
+	assert.Equal(t, 10, NewNoteGraphLayouter(10).Iterations)
+}
@@ -0,0 +1,329 @@
+package main
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/sirupsen/logrus"
+)
+
+// NodeFocalID is the ID of the GraphML attribute used to mark the focal node of an ego network
+const NodeFocalID = "node-focal"
+
+// NodeFocalName is the name of the GraphML attribute used to mark the focal node of an ego network
+const NodeFocalName = "focal"
+
+// EdgePathID is the ID of the GraphML attribute used to highlight edges along a path
+const EdgePathID = "edge-path"
+
+// EdgePathName is the name of the GraphML attribute used to highlight edges along a path
+const EdgePathName = "path"
+
+// Enum of all LinkDirections
+const (
+	Outgoing LinkDirection = iota // follow NoteLinks from source to target Note
+	Incoming LinkDirection = iota // follow NoteLinks from target to source Note
+	Both     LinkDirection = iota // follow NoteLinks in both directions
+)
+
+// LinkDirection identifies the direction in which NoteLinks are followed when traversing the NoteGraph
+type LinkDirection int
+
+func (ld LinkDirection) String() string {
+	return [...]string{"out", "in", "both"}[ld]
+}
+
+// NewLinkDirection create a LinkDirection instance from the string
+func NewLinkDirection(value string) (*LinkDirection, error) {
+	if value == Outgoing.String() {
+		linkDirection := Outgoing
+		return &linkDirection, nil
+	} else if value == Incoming.String() {
+		linkDirection := Incoming
+		return &linkDirection, nil
+	} else if value == Both.String() {
+		linkDirection := Both
+		return &linkDirection, nil
+	}
+
+	return nil, errors.New("Invalid LinkDirection [" + value + "]")
+}
+
+// NotePath is a sequence of Notes connected by NoteLinks, NoteLinks[i] connects NoteGUIDs[i] and NoteGUIDs[i+1] in either direction
+type NotePath struct {
+	NoteGUIDs []string
+	NoteLinks []NoteLink
+}
+
+// Length returns the number of NoteLinks of the NotePath
+func (np NotePath) Length() int {
+	return len(np.NoteLinks)
+}
+
+// pathStep is a NoteLink followed from one Note to an adjacent Note
+type pathStep struct {
+	NoteGUID string
+	NoteLink NoteLink
+}
+
+// NoteGraphQuery answers questions about how Notes in a NoteGraph are connected by valid NoteLinks
+type NoteGraphQuery struct{}
+
+// NewNoteGraphQuery creates a new instance of NoteGraphQuery
+func NewNoteGraphQuery() *NoteGraphQuery {
+	return &NoteGraphQuery{}
+}
+
+// EgoNoteGraph returns the NoteGraph with all Notes reachable from the focal Note in up to depth NoteLinks following NoteLinks in the
+// specified direction, and all valid NoteLinks between these Notes
+func (ngq *NoteGraphQuery) EgoNoteGraph(noteGraph *NoteGraph, focalNoteGUID string, depth int, linkDirection LinkDirection) (*NoteGraph, error) {
+	if noteGraph.GetNote(focalNoteGUID) == nil {
+		return nil, errors.New("Failed to create ego NoteGraph: focal Note with GUID [" + focalNoteGUID + "] does not exist")
+	}
+
+	neighbours := ngq.Neighbours(noteGraph, linkDirection)
+
+	distances := map[string]int{focalNoteGUID: 0}
+	queue := []string{focalNoteGUID}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if distances[current] == depth {
+			continue
+		}
+
+		for _, neighbour := range neighbours[current] {
+			if _, visited := distances[neighbour]; !visited {
+				distances[neighbour] = distances[current] + 1
+				queue = append(queue, neighbour)
+			}
+		}
+	}
+
+	egoNoteGraph := NewNoteGraph()
+	for noteGUID := range distances {
+		egoNoteGraph.Add(noteGraph.Notes[noteGUID], []NoteLink{})
+	}
+
+	for _, noteLink := range *noteGraph.GetValidNoteLinks() {
+		_, sourceNoteFound := distances[noteLink.SourceNoteGUID]
+		_, targetNoteFound := distances[noteLink.TargetNoteGUID]
+		if sourceNoteFound && targetNoteFound {
+			egoNoteGraph.NoteLinks = append(egoNoteGraph.NoteLinks, noteLink)
+		}
+	}
+
+	logrus.Infof("Created ego NoteGraph with [%d] Notes and [%d] NoteLinks around Note with GUID [%s] with depth [%d] and direction [%s]", len(egoNoteGraph.Notes), len(egoNoteGraph.NoteLinks), focalNoteGUID, depth, linkDirection)
+	return egoNoteGraph, nil
+}
+
+// FocalNoteAttribute creates the NoteAttribute which marks the focal Note of an ego NoteGraph
+func (ngq *NoteGraphQuery) FocalNoteAttribute(noteGraph *NoteGraph, focalNoteGUID string) NoteAttribute {
+	values := map[string]string{}
+	for noteGUID := range noteGraph.Notes {
+		values[noteGUID] = "false"
+	}
+	values[focalNoteGUID] = "true"
+
+	return NoteAttribute{ID: NodeFocalID, Name: NodeFocalName, Type: "boolean", Values: values}
+}
+
+// Backlinks returns the valid NoteLinks pointing to the Note, ordered by source Note GUID
+func (ngq *NoteGraphQuery) Backlinks(noteGraph *NoteGraph, noteGUID string) ([]NoteLink, error) {
+	if noteGraph.GetNote(noteGUID) == nil {
+		return nil, errors.New("Failed to find backlinks: Note with GUID [" + noteGUID + "] does not exist")
+	}
+
+	backlinks := []NoteLink{}
+	for _, noteLink := range *noteGraph.GetValidNoteLinks() {
+		if noteLink.TargetNoteGUID == noteGUID {
+			backlinks = append(backlinks, noteLink)
+		}
+	}
+
+	sort.SliceStable(backlinks, func(i, j int) bool {
+		return backlinks[i].SourceNoteGUID < backlinks[j].SourceNoteGUID
+	})
+
+	return backlinks, nil
+}
+
+// Neighbours returns the GUIDs of the Notes adjacent to each Note by valid NoteLinks in the specified direction
+func (ngq *NoteGraphQuery) Neighbours(noteGraph *NoteGraph, linkDirection LinkDirection) map[string][]string {
+	neighbours := map[string][]string{}
+	for _, noteLink := range *noteGraph.GetValidNoteLinks() {
+		if linkDirection == Outgoing || linkDirection == Both {
+			neighbours[noteLink.SourceNoteGUID] = append(neighbours[noteLink.SourceNoteGUID], noteLink.TargetNoteGUID)
+		}
+
+		if linkDirection == Incoming || linkDirection == Both {
+			neighbours[noteLink.TargetNoteGUID] = append(neighbours[noteLink.TargetNoteGUID], noteLink.SourceNoteGUID)
+		}
+	}
+
+	return neighbours
+}
+
+// ShortestPath returns the NotePath with the fewest NoteLinks from the source Note to the target Note following NoteLinks in the
+// specified direction, returns nil if the target Note is not reachable
+func (ngq *NoteGraphQuery) ShortestPath(noteGraph *NoteGraph, sourceNoteGUID, targetNoteGUID string, linkDirection LinkDirection) (*NotePath, error) {
+	if err := ngq.ValidatePathNotes(noteGraph, sourceNoteGUID, targetNoteGUID); err != nil {
+		return nil, err
+	}
+
+	pathSteps := ngq.PathSteps(noteGraph, linkDirection)
+
+	previousSteps := map[string]*pathStep{sourceNoteGUID: nil}
+	queue := []string{sourceNoteGUID}
+	for len(queue) > 0 && targetNoteGUID != sourceNoteGUID {
+		current := queue[0]
+		queue = queue[1:]
+		if current == targetNoteGUID {
+			break
+		}
+
+		for _, step := range pathSteps[current] {
+			if _, visited := previousSteps[step.NoteGUID]; !visited {
+				previousSteps[step.NoteGUID] = &pathStep{NoteGUID: current, NoteLink: step.NoteLink}
+				queue = append(queue, step.NoteGUID)
+			}
+		}
+	}
+
+	if _, reached := previousSteps[targetNoteGUID]; !reached {
+		return nil, nil
+	}
+
+	notePath := &NotePath{NoteGUIDs: []string{targetNoteGUID}, NoteLinks: []NoteLink{}}
+	for previousStep := previousSteps[targetNoteGUID]; previousStep != nil; previousStep = previousSteps[previousStep.NoteGUID] {
+		notePath.NoteGUIDs = append([]string{previousStep.NoteGUID}, notePath.NoteGUIDs...)
+		notePath.NoteLinks = append([]NoteLink{previousStep.NoteLink}, notePath.NoteLinks...)
+	}
+
+	return notePath, nil
+}
+
+// AllPaths returns all simple NotePaths (visiting each Note at most once) with up to maxLength NoteLinks from the source Note to the
+// target Note following NoteLinks in the specified direction, ordered by length
+func (ngq *NoteGraphQuery) AllPaths(noteGraph *NoteGraph, sourceNoteGUID, targetNoteGUID string, maxLength int, linkDirection LinkDirection) ([]NotePath, error) {
+	if err := ngq.ValidatePathNotes(noteGraph, sourceNoteGUID, targetNoteGUID); err != nil {
+		return nil, err
+	}
+
+	pathSteps := ngq.PathSteps(noteGraph, linkDirection)
+
+	notePaths := []NotePath{}
+	visited := map[string]bool{sourceNoteGUID: true}
+	noteGUIDs := []string{sourceNoteGUID}
+	noteLinks := []NoteLink{}
+
+	var findPaths func(current string)
+	findPaths = func(current string) {
+		if current == targetNoteGUID {
+			notePaths = append(notePaths, NotePath{NoteGUIDs: append([]string{}, noteGUIDs...), NoteLinks: append([]NoteLink{}, noteLinks...)})
+			return
+		}
+
+		if len(noteLinks) == maxLength {
+			return
+		}
+
+		for _, step := range pathSteps[current] {
+			if !visited[step.NoteGUID] {
+				visited[step.NoteGUID] = true
+				noteGUIDs = append(noteGUIDs, step.NoteGUID)
+				noteLinks = append(noteLinks, step.NoteLink)
+
+				findPaths(step.NoteGUID)
+
+				visited[step.NoteGUID] = false
+				noteGUIDs = noteGUIDs[:len(noteGUIDs)-1]
+				noteLinks = noteLinks[:len(noteLinks)-1]
+			}
+		}
+	}
+
+	findPaths(sourceNoteGUID)
+
+	sort.SliceStable(notePaths, func(i, j int) bool {
+		return notePaths[i].Length() < notePaths[j].Length()
+	})
+
+	return notePaths, nil
+}
+
+// PathNoteGraph returns the NoteGraph with all Notes along the NotePaths and all valid NoteLinks between these Notes, and the
+// NoteLinkAttribute highlighting the NoteLinks along the NotePaths
+func (ngq *NoteGraphQuery) PathNoteGraph(noteGraph *NoteGraph, notePaths []NotePath) (*NoteGraph, NoteLinkAttribute) {
+	pathNoteGraph := NewNoteGraph()
+	pathNoteLinks := map[NoteLinkKey]bool{}
+	for _, notePath := range notePaths {
+		for _, noteGUID := range notePath.NoteGUIDs {
+			pathNoteGraph.Add(noteGraph.Notes[noteGUID], []NoteLink{})
+		}
+
+		for _, noteLink := range notePath.NoteLinks {
+			pathNoteLinks[NoteLinkKey{noteLink.SourceNoteGUID, noteLink.TargetNoteGUID}] = true
+		}
+	}
+
+	values := map[NoteLinkKey]string{}
+	for _, noteLink := range *noteGraph.GetValidNoteLinks() {
+		if pathNoteGraph.GetNote(noteLink.SourceNoteGUID) != nil && pathNoteGraph.GetNote(noteLink.TargetNoteGUID) != nil {
+			pathNoteGraph.NoteLinks = append(pathNoteGraph.NoteLinks, noteLink)
+
+			noteLinkKey := NoteLinkKey{noteLink.SourceNoteGUID, noteLink.TargetNoteGUID}
+			if pathNoteLinks[noteLinkKey] {
+				values[noteLinkKey] = "true"
+			} else {
+				values[noteLinkKey] = "false"
+			}
+		}
+	}
+
+	return pathNoteGraph, NoteLinkAttribute{ID: EdgePathID, Name: EdgePathName, Type: "boolean", Values: values}
+}
+
+// ValidatePathNotes returns an error if the source or target Note of a path does not exist
+func (ngq *NoteGraphQuery) ValidatePathNotes(noteGraph *NoteGraph, sourceNoteGUID, targetNoteGUID string) error {
+	if noteGraph.GetNote(sourceNoteGUID) == nil {
+		return errors.New("Failed to find path: source Note with GUID [" + sourceNoteGUID + "] does not exist")
+	}
+
+	if noteGraph.GetNote(targetNoteGUID) == nil {
+		return errors.New("Failed to find path: target Note with GUID [" + targetNoteGUID + "] does not exist")
+	}
+
+	return nil
+}
+
+// PathSteps returns the valid NoteLinks that can be followed from each Note in the specified direction, with a single NoteLink per
+// pair of adjacent Notes, ordered by the GUID of the adjacent Note
+func (ngq *NoteGraphQuery) PathSteps(noteGraph *NoteGraph, linkDirection LinkDirection) map[string][]pathStep {
+	pathSteps := map[string][]pathStep{}
+	adjacent := map[NoteLinkKey]bool{}
+	addPathStep := func(fromNoteGUID, toNoteGUID string, noteLink NoteLink) {
+		if !adjacent[NoteLinkKey{fromNoteGUID, toNoteGUID}] {
+			adjacent[NoteLinkKey{fromNoteGUID, toNoteGUID}] = true
+			pathSteps[fromNoteGUID] = append(pathSteps[fromNoteGUID], pathStep{NoteGUID: toNoteGUID, NoteLink: noteLink})
+		}
+	}
+
+	for _, noteLink := range *noteGraph.GetValidNoteLinks() {
+		if linkDirection == Outgoing || linkDirection == Both {
+			addPathStep(noteLink.SourceNoteGUID, noteLink.TargetNoteGUID, noteLink)
+		}
+
+		if linkDirection == Incoming || linkDirection == Both {
+			addPathStep(noteLink.TargetNoteGUID, noteLink.SourceNoteGUID, noteLink)
+		}
+	}
+
+	for _, steps := range pathSteps {
+		sort.SliceStable(steps, func(i, j int) bool {
+			return steps[i].NoteGUID < steps[j].NoteGUID
+		})
+	}
+
+	return pathSteps
+}
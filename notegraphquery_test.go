@@ -0,0 +1,183 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/antchfx/xmlquery"
+	"github.com/stretchr/testify/assert"
+)
+
+func CreateQueryTestNoteGraph() *NoteGraph {
+	// A -> B -> C -> D, E -> B, F is not linked, C has a broken NoteLink
+	noteGraph := NewNoteGraph()
+	noteGraph.Add(Note{GUID: "A", Title: "TitleA"}, []NoteLink{{SourceNoteGUID: "A", TargetNoteGUID: "B", Text: "A->B"}})
+	noteGraph.Add(Note{GUID: "B", Title: "TitleB"}, []NoteLink{{SourceNoteGUID: "B", TargetNoteGUID: "C", Text: "B->C"}})
+	noteGraph.Add(Note{GUID: "C", Title: "TitleC"}, []NoteLink{{SourceNoteGUID: "C", TargetNoteGUID: "D", Text: "C->D"}, {SourceNoteGUID: "C", TargetNoteGUID: "X", Text: "C->X"}})
+	noteGraph.Add(Note{GUID: "D", Title: "TitleD"}, []NoteLink{})
+	noteGraph.Add(Note{GUID: "E", Title: "TitleE"}, []NoteLink{{SourceNoteGUID: "E", TargetNoteGUID: "B", Text: "E->B"}})
+	noteGraph.Add(Note{GUID: "F", Title: "TitleF"}, []NoteLink{})
+	return noteGraph
+}
+
+func TestNewLinkDirection(t *testing.T) {
+	outgoing, outgoingErr := NewLinkDirection("out")
+	assert.Nil(t, outgoingErr)
+	assert.Equal(t, Outgoing, *outgoing)
+
+	incoming, incomingErr := NewLinkDirection("in")
+	assert.Nil(t, incomingErr)
+	assert.Equal(t, Incoming, *incoming)
+
+	both, bothErr := NewLinkDirection("both")
+	assert.Nil(t, bothErr)
+	assert.Equal(t, Both, *both)
+
+	_, unknownErr := NewLinkDirection("sideways")
+	assert.NotNil(t, unknownErr)
+}
+
+func TestEgoNoteGraph(t *testing.T) {
+	noteGraph := CreateQueryTestNoteGraph()
+	noteGraphQuery := NewNoteGraphQuery()
+
+	outgoing, err := noteGraphQuery.EgoNoteGraph(noteGraph, "B", 1, Outgoing)
+	assert.Nil(t, err)
+	assert.ElementsMatch(t, []Note{{GUID: "B", Title: "TitleB"}, {GUID: "C", Title: "TitleC"}}, *outgoing.GetNotes())
+	assert.ElementsMatch(t, []NoteLink{{SourceNoteGUID: "B", TargetNoteGUID: "C", Text: "B->C"}}, *outgoing.GetNoteLinks())
+
+	incoming, err := noteGraphQuery.EgoNoteGraph(noteGraph, "B", 1, Incoming)
+	assert.Nil(t, err)
+	assert.ElementsMatch(t, []Note{{GUID: "A", Title: "TitleA"}, {GUID: "B", Title: "TitleB"}, {GUID: "E", Title: "TitleE"}}, *incoming.GetNotes())
+	assert.Len(t, *incoming.GetNoteLinks(), 2)
+
+	both, err := noteGraphQuery.EgoNoteGraph(noteGraph, "B", 2, Both)
+	assert.Nil(t, err)
+	assert.ElementsMatch(t, []Note{{GUID: "A", Title: "TitleA"}, {GUID: "B", Title: "TitleB"}, {GUID: "C", Title: "TitleC"}, {GUID: "D", Title: "TitleD"}, {GUID: "E", Title: "TitleE"}}, *both.GetNotes())
+	assert.Len(t, *both.GetNoteLinks(), 4)
+	assert.Empty(t, *both.GetBrokenNoteLinks())
+
+	focalOnly, err := noteGraphQuery.EgoNoteGraph(noteGraph, "F", 3, Both)
+	assert.Nil(t, err)
+	assert.ElementsMatch(t, []Note{{GUID: "F", Title: "TitleF"}}, *focalOnly.GetNotes())
+	assert.Empty(t, *focalOnly.GetNoteLinks())
+
+	_, unknownErr := noteGraphQuery.EgoNoteGraph(noteGraph, "X", 1, Both)
+	assert.NotNil(t, unknownErr)
+}
+
+func TestBacklinks(t *testing.T) {
+	noteGraph := CreateQueryTestNoteGraph()
+	noteGraphQuery := NewNoteGraphQuery()
+
+	backlinks, err := noteGraphQuery.Backlinks(noteGraph, "B")
+	assert.Nil(t, err)
+	assert.Equal(t, []NoteLink{{SourceNoteGUID: "A", TargetNoteGUID: "B", Text: "A->B"}, {SourceNoteGUID: "E", TargetNoteGUID: "B", Text: "E->B"}}, backlinks)
+
+	noBacklinks, err := noteGraphQuery.Backlinks(noteGraph, "A")
+	assert.Nil(t, err)
+	assert.Empty(t, noBacklinks)
+
+	_, unknownErr := noteGraphQuery.Backlinks(noteGraph, "X")
+	assert.NotNil(t, unknownErr)
+}
+
+func TestConvertEgoNoteGraph(t *testing.T) {
+	noteGraphQuery := NewNoteGraphQuery()
+	egoNoteGraph, err := noteGraphQuery.EgoNoteGraph(CreateQueryTestNoteGraph(), "C", 1, Outgoing)
+	if err != nil {
+		panic(err)
+	}
+
+	noteGraphUtil := NewNoteGraphUtil()
+	noteGraphUtil.AddNoteAttributes(noteGraphQuery.FocalNoteAttribute(egoNoteGraph, "C"))
+	graphMLDocument := noteGraphUtil.ConvertNoteGraph(egoNoteGraph, true)
+	xmlDocument, err := xmlquery.Parse(strings.NewReader(EncodeGraphMLDocument(graphMLDocument)))
+	if err != nil {
+		panic(err)
+	}
+
+	AssertKeyEqual(t, xmlDocument, NodeFocalID, "node", NodeFocalName, "boolean")
+	AssertNodeCount(t, xmlDocument, 2)
+	AssertEdgeCount(t, xmlDocument, 1)
+	assert.Equal(t, "true", xmlquery.FindOne(xmlDocument, "/graphml/graph/node[@id='C']/data[@key='"+NodeFocalID+"']").InnerText())
+	assert.Equal(t, "false", xmlquery.FindOne(xmlDocument, "/graphml/graph/node[@id='D']/data[@key='"+NodeFocalID+"']").InnerText())
+}
+
+func TestShortestPath(t *testing.T) {
+	noteGraph := CreateQueryTestNoteGraph()
+	noteGraphQuery := NewNoteGraphQuery()
+
+	directed, err := noteGraphQuery.ShortestPath(noteGraph, "A", "D", Outgoing)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"A", "B", "C", "D"}, directed.NoteGUIDs)
+	assert.Equal(t, []string{"A->B", "B->C", "C->D"}, []string{directed.NoteLinks[0].Text, directed.NoteLinks[1].Text, directed.NoteLinks[2].Text})
+
+	unreachable, err := noteGraphQuery.ShortestPath(noteGraph, "A", "E", Outgoing)
+	assert.Nil(t, err)
+	assert.Nil(t, unreachable)
+
+	undirected, err := noteGraphQuery.ShortestPath(noteGraph, "A", "E", Both)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"A", "B", "E"}, undirected.NoteGUIDs)
+	assert.Equal(t, 2, undirected.Length())
+
+	same, err := noteGraphQuery.ShortestPath(noteGraph, "A", "A", Outgoing)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"A"}, same.NoteGUIDs)
+	assert.Equal(t, 0, same.Length())
+
+	_, unknownErr := noteGraphQuery.ShortestPath(noteGraph, "A", "X", Outgoing)
+	assert.NotNil(t, unknownErr)
+}
+
+func TestAllPaths(t *testing.T) {
+	// A -> B -> D, A -> C -> D, A -> D, B -> C
+	noteGraph := NewNoteGraph()
+	noteGraph.Add(Note{GUID: "A"}, []NoteLink{{SourceNoteGUID: "A", TargetNoteGUID: "B"}, {SourceNoteGUID: "A", TargetNoteGUID: "C"}, {SourceNoteGUID: "A", TargetNoteGUID: "D"}})
+	noteGraph.Add(Note{GUID: "B"}, []NoteLink{{SourceNoteGUID: "B", TargetNoteGUID: "C"}, {SourceNoteGUID: "B", TargetNoteGUID: "D"}})
+	noteGraph.Add(Note{GUID: "C"}, []NoteLink{{SourceNoteGUID: "C", TargetNoteGUID: "D"}})
+	noteGraph.Add(Note{GUID: "D"}, []NoteLink{})
+
+	noteGraphQuery := NewNoteGraphQuery()
+	twoLinkPaths, err := noteGraphQuery.AllPaths(noteGraph, "A", "D", 2, Outgoing)
+	assert.Nil(t, err)
+	assert.Len(t, twoLinkPaths, 3)
+	assert.Equal(t, []string{"A", "D"}, twoLinkPaths[0].NoteGUIDs)
+	assert.Equal(t, []string{"A", "B", "D"}, twoLinkPaths[1].NoteGUIDs)
+	assert.Equal(t, []string{"A", "C", "D"}, twoLinkPaths[2].NoteGUIDs)
+
+	allPaths, err := noteGraphQuery.AllPaths(noteGraph, "A", "D", 5, Outgoing)
+	assert.Nil(t, err)
+	assert.Len(t, allPaths, 4)
+	assert.Equal(t, []string{"A", "B", "C", "D"}, allPaths[3].NoteGUIDs)
+
+	reversePaths, err := noteGraphQuery.AllPaths(noteGraph, "D", "A", 5, Outgoing)
+	assert.Nil(t, err)
+	assert.Empty(t, reversePaths)
+}
+
+func TestConvertPathNoteGraph(t *testing.T) {
+	noteGraph := CreateQueryTestNoteGraph()
+	noteGraphQuery := NewNoteGraphQuery()
+	notePath, err := noteGraphQuery.ShortestPath(noteGraph, "E", "C", Outgoing)
+	if err != nil {
+		panic(err)
+	}
+
+	pathNoteGraph, pathNoteLinkAttribute := noteGraphQuery.PathNoteGraph(noteGraph, []NotePath{*notePath})
+	assert.ElementsMatch(t, []Note{{GUID: "B", Title: "TitleB"}, {GUID: "C", Title: "TitleC"}, {GUID: "E", Title: "TitleE"}}, *pathNoteGraph.GetNotes())
+
+	noteGraphUtil := NewNoteGraphUtil()
+	noteGraphUtil.AddNoteLinkAttributes(pathNoteLinkAttribute)
+	graphMLDocument := noteGraphUtil.ConvertNoteGraph(pathNoteGraph, true)
+	xmlDocument, err := xmlquery.Parse(strings.NewReader(EncodeGraphMLDocument(graphMLDocument)))
+	if err != nil {
+		panic(err)
+	}
+
+	AssertKeyEqual(t, xmlDocument, EdgePathID, "edge", EdgePathName, "boolean")
+	AssertEdgeCount(t, xmlDocument, 2)
+	assert.Equal(t, "true", xmlquery.FindOne(xmlDocument, "/graphml/graph/edge[@source='E']/data[@key='"+EdgePathID+"']").InnerText())
+	assert.Equal(t, "true", xmlquery.FindOne(xmlDocument, "/graphml/graph/edge[@source='B']/data[@key='"+EdgePathID+"']").InnerText())
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// NoteGraphPath is the URL path of the NoteGraph downloads without the extension of the OutputFormat
+const NoteGraphPath = "/notegraph"
+
+// NoteGraphServer serves the NoteGraph as interactive HTML graph viewer at / and in every OutputFormat that writes a single file
+// at NoteGraphPath with the extension of the OutputFormat, e.g. /notegraph.graphml
+type NoteGraphServer struct {
+	NoteGraph          *NoteGraph
+	NoteAttributes     []NoteAttribute
+	NoteLinkAttributes []NoteLinkAttribute
+	AllNotes           bool
+	ExportOptions      ExportOptions
+	serveMux           *http.ServeMux
+	layoutOnce         sync.Once
+	layoutAttributes   []NoteAttribute
+}
+
+// NewNoteGraphServer creates a new instance of NoteGraphServer
+func NewNoteGraphServer(noteGraph *NoteGraph, noteAttributes []NoteAttribute, noteLinkAttributes []NoteLinkAttribute, allNotes bool, exportOptions ExportOptions) *NoteGraphServer {
+	noteGraphServer := &NoteGraphServer{NoteGraph: noteGraph, NoteAttributes: noteAttributes, NoteLinkAttributes: noteLinkAttributes, AllNotes: allNotes, ExportOptions: exportOptions, serveMux: http.NewServeMux()}
+	noteGraphServer.serveMux.HandleFunc("/", func(writer http.ResponseWriter, request *http.Request) {
+		if request.URL.Path != "/" {
+			http.NotFound(writer, request)
+			return
+		}
+
+		noteGraphServer.ServeNoteGraph(writer, request, HTML)
+	})
+
+	for _, outputFormat := range noteGraphServer.OutputFormats() {
+		outputFormat := outputFormat
+		noteGraphServer.serveMux.HandleFunc(NoteGraphPath+outputFormat.Extension(), func(writer http.ResponseWriter, request *http.Request) {
+			noteGraphServer.ServeNoteGraph(writer, request, outputFormat)
+		})
+	}
+
+	return noteGraphServer
+}
+
+// OutputFormats returns the OutputFormats the NoteGraph is served in, OutputFormats that write more than one file are not served
+func (ngs *NoteGraphServer) OutputFormats() []OutputFormat {
+	outputFormats := []OutputFormat{}
+	for outputFormat := GraphML; outputFormat <= PNG; outputFormat++ {
+		if outputFormat.ContentType() != "" {
+			outputFormats = append(outputFormats, outputFormat)
+		}
+	}
+
+	return outputFormats
+}
+
+// ServeHTTP dispatches the request to the handler of the requested path
+func (ngs *NoteGraphServer) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	logrus.Debugf("Serving [%s %s]", request.Method, request.URL.Path)
+	ngs.serveMux.ServeHTTP(writer, request)
+}
+
+// ServeNoteGraph exports the NoteGraph in the OutputFormat and writes it to the response, the NoteGraph is exported to a buffer
+// first so that export errors are reported with status 500 instead of a truncated response
+func (ngs *NoteGraphServer) ServeNoteGraph(writer http.ResponseWriter, request *http.Request, outputFormat OutputFormat) {
+	if request.Method != http.MethodGet && request.Method != http.MethodHead {
+		writer.Header().Set("Allow", "GET, HEAD")
+		http.Error(writer, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	noteGraphExporter, exporterErr := NewNoteGraphExporter(outputFormat, ngs.ExportOptions)
+	if exporterErr != nil {
+		logrus.Errorf("Failed to create NoteGraph exporter for output format [%s]: %v", outputFormat, exporterErr)
+		http.Error(writer, exporterErr.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	noteGraphExporter.AddNoteAttributes(ngs.NoteAttributes...)
+	noteGraphExporter.AddNoteLinkAttributes(ngs.NoteLinkAttributes...)
+	if outputFormat == SVG || outputFormat == PNG {
+		noteGraphExporter.AddNoteAttributes(ngs.LayoutAttributes()...)
+	}
+
+	var buffer bytes.Buffer
+	exportErr := noteGraphExporter.ExportNoteGraph(ngs.NoteGraph, ngs.AllNotes, &buffer)
+	if exportErr != nil {
+		logrus.Errorf("Failed to export NoteGraph to output format [%s]: %v", outputFormat, exportErr)
+		http.Error(writer, exportErr.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	writer.Header().Set("Content-Type", outputFormat.ContentType())
+	writer.Write(buffer.Bytes())
+}
+
+// LayoutAttributes returns the NoteAttributes with the layout of the NoteGraph drawn in the SVG and PNG OutputFormats, the layout
+// is computed on the first request and reused for all further requests unless the NoteAttributes contain the positions of all
+// Notes already
+func (ngs *NoteGraphServer) LayoutAttributes() []NoteAttribute {
+	ngs.layoutOnce.Do(func() {
+		notes := *ngs.NoteGraph.GetLinkedNotes()
+		if ngs.AllNotes {
+			notes = *ngs.NoteGraph.GetNotes()
+		}
+
+		noteGraphLayouter := NewNoteGraphLayouter(ngs.ExportOptions.LayoutIterations)
+		positions := noteGraphLayouter.NotePositions(ngs.NoteAttributes)
+		for _, note := range notes {
+			if _, found := positions[note.GUID]; !found {
+				ngs.layoutAttributes = noteGraphLayouter.PositionAttributes(noteGraphLayouter.LayoutNoteGraph(ngs.NoteGraph, ngs.AllNotes))
+				return
+			}
+		}
+	})
+
+	return ngs.layoutAttributes
+}
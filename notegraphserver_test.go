@@ -0,0 +1,72 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func CreateServerTestNoteGraphServer() *NoteGraphServer {
+	noteGraph := NewNoteGraph()
+	noteGraph.Add(Note{GUID: "A", Title: "TitleA", URL: *CreateWebLinkURL("A"), URLType: WebLink}, []NoteLink{{SourceNoteGUID: "A", TargetNoteGUID: "B", Text: "A->B", URL: *CreateWebLinkURL("B"), URLType: WebLink}})
+	noteGraph.Add(Note{GUID: "B", Title: "TitleB", URL: *CreateWebLinkURL("B"), URLType: WebLink}, []NoteLink{})
+
+	noteAttributes := []NoteAttribute{{ID: "node-rank", Name: "rank", Type: "int", Values: map[string]string{"A": "1", "B": "2"}}}
+	return NewNoteGraphServer(noteGraph, noteAttributes, []NoteLinkAttribute{}, true, ExportOptions{LayoutIterations: 10})
+}
+
+func ServeTestRequest(noteGraphServer *NoteGraphServer, method, path string) *httptest.ResponseRecorder {
+	responseRecorder := httptest.NewRecorder()
+	noteGraphServer.ServeHTTP(responseRecorder, httptest.NewRequest(method, path, nil))
+	return responseRecorder
+}
+
+func TestNoteGraphServerOutputFormats(t *testing.T) {
+	assert.Equal(t, []OutputFormat{GraphML, GEXF, DOT, Cytoscape, JGF, Cypher, HTML, SVG, PNG}, CreateServerTestNoteGraphServer().OutputFormats())
+}
+
+func TestNoteGraphServerServeNoteGraph(t *testing.T) {
+	noteGraphServer := CreateServerTestNoteGraphServer()
+
+	index := ServeTestRequest(noteGraphServer, http.MethodGet, "/")
+	assert.Equal(t, http.StatusOK, index.Code)
+	assert.Equal(t, "text/html; charset=utf-8", index.Header().Get("Content-Type"))
+	assert.Contains(t, index.Body.String(), "TitleA")
+
+	graphML := ServeTestRequest(noteGraphServer, http.MethodGet, "/notegraph.graphml")
+	assert.Equal(t, http.StatusOK, graphML.Code)
+	assert.Equal(t, "application/xml", graphML.Header().Get("Content-Type"))
+	assert.Contains(t, graphML.Body.String(), `<key id="node-rank" for="node" attr.name="rank" attr.type="int"></key>`)
+	assert.Contains(t, graphML.Body.String(), `<edge id="A-B-1" source="A" target="B">`)
+
+	png := ServeTestRequest(noteGraphServer, http.MethodGet, "/notegraph.png")
+	assert.Equal(t, http.StatusOK, png.Code)
+	assert.True(t, strings.HasPrefix(png.Body.String(), "\x89PNG"))
+}
+
+func TestNoteGraphServerLayoutAttributes(t *testing.T) {
+	noteGraphServer := CreateServerTestNoteGraphServer()
+
+	layoutAttributes := noteGraphServer.LayoutAttributes()
+	assert.Len(t, layoutAttributes, 2)
+	assert.Len(t, NewNoteGraphLayouter(0).NotePositions(layoutAttributes), 2)
+	assert.True(t, &layoutAttributes[0] == &noteGraphServer.LayoutAttributes()[0])
+
+	noteGraphServer = CreateServerTestNoteGraphServer()
+	noteGraphServer.NoteAttributes = append(noteGraphServer.NoteAttributes, layoutAttributes...)
+	assert.Empty(t, noteGraphServer.LayoutAttributes())
+}
+
+func TestNoteGraphServerErrors(t *testing.T) {
+	noteGraphServer := CreateServerTestNoteGraphServer()
+
+	assert.Equal(t, http.StatusNotFound, ServeTestRequest(noteGraphServer, http.MethodGet, "/notegraph.sqlite").Code)
+	assert.Equal(t, http.StatusNotFound, ServeTestRequest(noteGraphServer, http.MethodGet, "/notes").Code)
+
+	post := ServeTestRequest(noteGraphServer, http.MethodPost, "/notegraph.dot")
+	assert.Equal(t, http.StatusMethodNotAllowed, post.Code)
+	assert.Equal(t, "GET, HEAD", post.Header().Get("Allow"))
+}
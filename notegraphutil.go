@@ -1,16 +1,131 @@
 package main
 
 import (
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/freddy33/graphml"
-	uuid "github.com/satori/go.uuid"
 	"github.com/sirupsen/logrus"
 )
 
+// Enum of all NodeColorBys
+const (
+	NotebookColor  NodeColorBy = iota // fill colour by notebook of the Note
+	CommunityColor NodeColorBy = iota // fill colour by community of the Note (requires NoteGraph analysis)
+)
+
+// NodeColorBy identifies the Note property from which the fill colour of nodes in yEd is derived
+type NodeColorBy int
+
+func (ncb NodeColorBy) String() string {
+	return [...]string{"notebook", "community"}[ncb]
+}
+
+// NewNodeColorBy create a NodeColorBy instance from the string
+func NewNodeColorBy(value string) (*NodeColorBy, error) {
+	if value == NotebookColor.String() {
+		nodeColorBy := NotebookColor
+		return &nodeColorBy, nil
+	} else if value == CommunityColor.String() {
+		nodeColorBy := CommunityColor
+		return &nodeColorBy, nil
+	}
+
+	return nil, errors.New("Invalid NodeColorBy [" + value + "]")
+}
+
+// NodeColors are the fill colours of nodes in yEd assigned to notebooks or communities in order
+var NodeColors = []string{"#00A82D", "#1F77B4", "#FF7F0E", "#D62728", "#9467BD", "#8C564B", "#E377C2", "#BCBD22", "#17BECF", "#FFBB78", "#AEC7E8", "#98DF8A"}
+
+// DefaultNodeColor is the fill colour of nodes in yEd without notebook or community
+const DefaultNodeColor = "#C0C0C0"
+
+// MinNodeSize is the width and height of nodes in yEd without NoteLinks
+const MinNodeSize = 30.0
+
+// MaxNodeSize is the width and height of nodes in yEd with the most NoteLinks
+const MaxNodeSize = 90.0
+
 // NoteGraphUtil converts a NoteGraph to GraphML and saves the the GraphML document to a file
 type NoteGraphUtil struct {
-	GraphMLUtil GraphMLUtil
+	ExportAttributes
+	GraphMLUtil    GraphMLUtil
+	YFiles         bool        // add yFiles node and edge graphics for yEd
+	NodeColorBy    NodeColorBy // fill colour of nodes with yFiles graphics
+	AggregateLinks bool        // collapse parallel NoteLinks into a single weighted edge
+	DropSelfLoops  bool        // omit NoteLinks from a Note to itself
+	Undirected     bool        // merge NoteLinks in both directions into a single undirected weighted edge
+	Reciprocal     bool        // annotate edges with NoteLinks in both directions
+}
+
+// EdgeReciprocalID is the ID of the GraphML attribute used to mark edges with NoteLinks in both directions
+const EdgeReciprocalID = "edge-reciprocal"
+
+// EdgeReciprocalName is the name of the GraphML attribute used to mark edges with NoteLinks in both directions
+const EdgeReciprocalName = "reciprocal"
+
+// EdgeWeightID is the ID of the GraphML attribute used for the number of NoteLinks of aggregated edges
+const EdgeWeightID = "edge-weight"
+
+// EdgeWeightName is the name of the GraphML attribute used for the number of NoteLinks of aggregated edges
+const EdgeWeightName = "weight"
+
+// EdgeURLTypesID is the ID of the GraphML attribute used for the URLTypes of the NoteLinks of aggregated edges
+const EdgeURLTypesID = "edge-url-types"
+
+// EdgeURLTypesName is the name of the GraphML attribute used for the URLTypes of the NoteLinks of aggregated edges
+const EdgeURLTypesName = "urlTypes"
+
+// AggregatedTextSeparator separates the distinct texts of the NoteLinks of aggregated edges
+const AggregatedTextSeparator = "; "
+
+// NodeStyle is the size, fill colour, and position of the yFiles graphics of the GraphML node of a Note
+type NodeStyle struct {
+	Size     float64
+	Color    string
+	Position NotePosition
+}
+
+// EdgeIDs creates stable GraphML edge IDs from the source Note GUID, the target Note GUID, and the ordinal of the NoteLink among
+// all NoteLinks between the same Notes
+type EdgeIDs struct {
+	ordinals map[NoteLinkKey]int
+}
+
+// NewEdgeIDs creates a new instance of EdgeIDs
+func NewEdgeIDs() *EdgeIDs {
+	return &EdgeIDs{ordinals: map[NoteLinkKey]int{}}
+}
+
+// EdgeID returns the edge ID of the next NoteLink between the source and target Note of the NoteLink
+func (ei *EdgeIDs) EdgeID(noteLink NoteLink) string {
+	noteLinkKey := NoteLinkKey{noteLink.SourceNoteGUID, noteLink.TargetNoteGUID}
+	ei.ordinals[noteLinkKey]++
+	return fmt.Sprintf("%s-%s-%d", noteLink.SourceNoteGUID, noteLink.TargetNoteGUID, ei.ordinals[noteLinkKey])
+}
+
+// EdgeNoteLink is a NoteLink with its edge ID
+type EdgeNoteLink struct {
+	EdgeID   string
+	NoteLink NoteLink
+}
+
+// CanonicalNoteLinks returns the NoteLinks with their edge IDs in canonical order, sorted by source Note GUID, target Note GUID,
+// text, and URL, all exporters and DiffNoteGraph enumerate NoteLinks in canonical order so that an edge ID identifies the same
+// NoteLink in all OutputFormats regardless of the order in which the NoteLinks were added to the NoteGraph
+func CanonicalNoteLinks(noteLinks []NoteLink) []EdgeNoteLink {
+	edgeIDs := NewEdgeIDs()
+	edgeNoteLinks := []EdgeNoteLink{}
+	for _, noteLink := range SortNoteLinks(append([]NoteLink{}, noteLinks...)) {
+		edgeNoteLinks = append(edgeNoteLinks, EdgeNoteLink{EdgeID: edgeIDs.EdgeID(noteLink), NoteLink: noteLink})
+	}
+
+	return edgeNoteLinks
 }
 
 // NoteGraphID is the ID used for the note graph of the GraphML document
@@ -18,44 +133,245 @@ const NoteGraphID = "NoteGraph"
 
 // NewNoteGraphUtil creates a new instance of NoteGraphUtil
 func NewNoteGraphUtil() *NoteGraphUtil {
-	return &NoteGraphUtil{GraphMLUtil{}}
+	return &NoteGraphUtil{GraphMLUtil: GraphMLUtil{}}
+}
+
+// ExportNoteGraph writes the NoteGraph as GraphML document to the writer, nodes and edges are created and written one at a time
+// to keep memory bounded for large NoteGraphs, the output is identical to encoding the GraphML document created by ConvertNoteGraph
+func (ngu *NoteGraphUtil) ExportNoteGraph(noteGraph *NoteGraph, allNotes bool, writer io.Writer) error {
+	notes := ngu.GraphNotes(noteGraph, allNotes)
+	noteLinks, noteLinkAttributes := ngu.GraphNoteLinks(noteGraph)
+	ngu.AddNoteLinkAttributes(noteLinkAttributes...)
+
+	logrus.Infof("Writing NoteGraph with [%d] Notes and [%d] NoteLinks as GraphML", len(notes), len(noteLinks))
+
+	nodeStyles := map[string]NodeStyle{}
+	if ngu.YFiles {
+		nodeStyles = ngu.NodeStyles(notes, noteLinks)
+	}
+
+	graphMLWriter := NewGraphMLWriter(writer)
+	graphMLWriter.WriteDocumentStart(ngu.CreateGraphMLDocument([]graphml.Graph{}))
+	graphMLWriter.WriteGraphStart(ngu.GraphMLUtil.CreateGraph(NoteGraphID, ngu.EdgeDefault(), nil, nil))
+	for _, note := range notes {
+		node := ngu.CreateNode(note)
+		if ngu.YFiles {
+			node.Data = append(node.Data, ngu.CreateNodeGraphics(note, nodeStyles[note.GUID]))
+		}
+
+		graphMLWriter.WriteNode(&node)
+	}
+
+	for _, edgeNoteLink := range CanonicalNoteLinks(noteLinks) {
+		edge := ngu.CreateEdge(edgeNoteLink.EdgeID, edgeNoteLink.NoteLink)
+		if ngu.YFiles {
+			edge.Data = append(edge.Data, ngu.GraphMLUtil.CreateEdgeGraphics(!ngu.Undirected))
+		}
+
+		graphMLWriter.WriteEdge(&edge)
+	}
+
+	graphMLWriter.WriteGraphEnd()
+	writeErr := graphMLWriter.WriteDocumentEnd()
+	if writeErr != nil {
+		return fmt.Errorf("Failed to write GraphML document: %w", writeErr)
+	}
+
+	return nil
 }
 
-// PrintNoteGraphStats prints NoteGraph stats to stdout
+// PrintNoteGraphStats prints NoteGraph stats to the ReportOutput
 func (ngu *NoteGraphUtil) PrintNoteGraphStats(noteGraph *NoteGraph) {
-	logrus.Infof("NoteGraph Stats")
-	logrus.Infof("   Notes: %d", len(*noteGraph.GetNotes()))
-	logrus.Infof("   Linked Notes: %d", len(*noteGraph.GetLinkedNotes()))
-	logrus.Infof("   Note Links: %d", len(*noteGraph.GetNoteLinks()))
-	logrus.Infof("   Valid Note Links: %d", len(*noteGraph.GetValidNoteLinks()))
-	logrus.Infof("   Broken Note Links: %d", len(*noteGraph.GetBrokenNoteLinks()))
+	Reportf("NoteGraph Stats")
+	Reportf("   Notes: %d", len(*noteGraph.GetNotes()))
+	Reportf("   Linked Notes: %d", len(*noteGraph.GetLinkedNotes()))
+	Reportf("   Note Links: %d", len(*noteGraph.GetNoteLinks()))
+	Reportf("   Valid Note Links: %d", len(*noteGraph.GetValidNoteLinks()))
+	Reportf("   Broken Note Links: %d", len(*noteGraph.GetBrokenNoteLinks()))
+	Reportf("   Reciprocal Note Link Pairs: %d", len(ngu.ReciprocalNoteLinkKeys(*noteGraph.GetValidNoteLinks()))/2)
 }
 
 // PrintBrokenNoteLinks prints all broken NoteLinks
 func (ngu *NoteGraphUtil) PrintBrokenNoteLinks(noteGraph *NoteGraph) {
 	brokenNoteLinks := *noteGraph.GetBrokenNoteLinks()
 	if len(brokenNoteLinks) > 0 {
-		logrus.Infof("Broken Note Links")
+		Reportf("Broken Note Links")
 		for _, noteLink := range brokenNoteLinks {
 			sourceNote := noteGraph.GetNote(noteLink.SourceNoteGUID)
 			targetNote := noteGraph.GetNote(noteLink.TargetNoteGUID)
-			logrus.Infof("   NoteLink [%v] from source Note [%v] to target Note [%v]", noteLink, sourceNote, targetNote)
+			Reportf("   NoteLink [%v] from source Note [%v] to target Note [%v]", noteLink, sourceNote, targetNote)
 		}
 	}
 }
 
+// PrintNoteGraphAnalysis prints the size distribution and representative Notes of the connected components and communities
+func (ngu *NoteGraphUtil) PrintNoteGraphAnalysis(noteGraph *NoteGraph, noteGraphAnalysis *NoteGraphAnalysis) {
+	ngu.PrintNoteClusters(noteGraph, "Weakly Connected Components", noteGraphAnalysis.WeakComponents)
+	ngu.PrintNoteClusters(noteGraph, "Strongly Connected Components", noteGraphAnalysis.StrongComponents)
+	ngu.PrintNoteClusters(noteGraph, fmt.Sprintf("Communities (modularity %.4f)", noteGraphAnalysis.Modularity), noteGraphAnalysis.Communities)
+}
+
+// PrintNoteClusters prints the size distribution of the NoteClusters and the representative Note of each NoteCluster with more than one Note
+func (ngu *NoteGraphUtil) PrintNoteClusters(noteGraph *NoteGraph, title string, noteClusters []NoteCluster) {
+	Reportf("%s: %d", title, len(noteClusters))
+
+	sizeDistribution := map[int]int{}
+	for _, noteCluster := range noteClusters {
+		sizeDistribution[noteCluster.Size()]++
+	}
+
+	sizes := []int{}
+	for size := range sizeDistribution {
+		sizes = append(sizes, size)
+	}
+
+	sort.Sort(sort.Reverse(sort.IntSlice(sizes)))
+	for _, size := range sizes {
+		Reportf("   Size %d: %d", size, sizeDistribution[size])
+	}
+
+	for _, noteCluster := range noteClusters {
+		if noteCluster.Size() > 1 {
+			representativeNote := noteGraph.GetNote(noteCluster.RepresentativeNoteGUID)
+			Reportf("   #%d with %d Notes represented by Note [%s]", noteCluster.ID, noteCluster.Size(), representativeNote.Title)
+		}
+	}
+}
+
+// PrintNotePaths prints the title and the Notes and NoteLinks along each NotePath
+func (ngu *NoteGraphUtil) PrintNotePaths(noteGraph *NoteGraph, title string, notePaths []NotePath) {
+	Reportf("%s: %d", title, len(notePaths))
+	for _, notePath := range notePaths {
+		Reportf("   Path with %d NoteLinks", notePath.Length())
+		Reportf("      Note [%s]", noteGraph.Notes[notePath.NoteGUIDs[0]].Title)
+		for index, noteLink := range notePath.NoteLinks {
+			if noteLink.SourceNoteGUID == notePath.NoteGUIDs[index] {
+				Reportf("      --[%s]--> Note [%s]", noteLink.Text, noteGraph.Notes[notePath.NoteGUIDs[index+1]].Title)
+			} else {
+				Reportf("      <--[%s]-- Note [%s]", noteLink.Text, noteGraph.Notes[notePath.NoteGUIDs[index+1]].Title)
+			}
+		}
+	}
+}
+
+// PrintBacklinks prints the source Note and text of each NoteLink pointing to the Note
+func (ngu *NoteGraphUtil) PrintBacklinks(noteGraph *NoteGraph, noteGUID string, backlinks []NoteLink) {
+	Reportf("Backlinks to Note [%s]: %d", noteGraph.Notes[noteGUID].Title, len(backlinks))
+	for _, noteLink := range backlinks {
+		Reportf("   Note [%s] --[%s]-->", noteGraph.Notes[noteLink.SourceNoteGUID].Title, noteLink.Text)
+	}
+}
+
 // ConvertNoteGraph converts the NoteGraph into a GraphML document
 func (ngu *NoteGraphUtil) ConvertNoteGraph(noteGraph *NoteGraph, allNotes bool) *graphml.Document {
 	notes := ngu.GraphNotes(noteGraph, allNotes)
-	noteLinks := ngu.GraphNoteLinks(noteGraph)
+	noteLinks, noteLinkAttributes := ngu.GraphNoteLinks(noteGraph)
+	ngu.AddNoteLinkAttributes(noteLinkAttributes...)
 
 	nodes := ngu.CreateNodes(notes)
 	edges := ngu.CreateEdges(noteLinks)
+	if ngu.YFiles {
+		ngu.AddNodeGraphics(nodes, notes, noteLinks)
+		ngu.AddEdgeGraphics(edges)
+	}
 
 	logrus.Infof("Converting NoteGraph with [%d|%d] Notes|nodes and [%d|%d] NoteLinks|edges to GraphML", len(notes), len(nodes), len(noteLinks), len(edges))
 
-	graph := ngu.GraphMLUtil.CreateGraph(NoteGraphID, graphml.EdgeDirected, nodes, edges)
-	return ngu.GraphMLUtil.CreateGraphMLDocument([]graphml.Graph{*graph})
+	graph := ngu.GraphMLUtil.CreateGraph(NoteGraphID, ngu.EdgeDefault(), nodes, edges)
+	return ngu.CreateGraphMLDocument([]graphml.Graph{*graph})
+}
+
+// CreateGraphMLDocument creates the GraphML document with the GraphML graphs and the GraphML attribute definitions of the standardised
+// GraphML attributes, the NoteAttributes, the NoteLinkAttributes, and the yFiles graphics
+func (ngu *NoteGraphUtil) CreateGraphMLDocument(graphs []graphml.Graph) *graphml.Document {
+	graphMLDocument := ngu.GraphMLUtil.CreateGraphMLDocument(graphs)
+	for _, noteAttribute := range ngu.NoteAttributes {
+		graphMLDocument.Keys = append(graphMLDocument.Keys, graphml.NewKey(graphml.KindNode, noteAttribute.ID, noteAttribute.Name, noteAttribute.Type))
+	}
+
+	for _, noteLinkAttribute := range ngu.NoteLinkAttributes {
+		graphMLDocument.Keys = append(graphMLDocument.Keys, graphml.NewKey(graphml.KindEdge, noteLinkAttribute.ID, noteLinkAttribute.Name, noteLinkAttribute.Type))
+	}
+
+	if ngu.YFiles {
+		ngu.GraphMLUtil.AddYFilesKeys(graphMLDocument)
+	}
+
+	return graphMLDocument
+}
+
+// AddNodeGraphics adds yFiles graphics to the GraphML nodes created from the Notes
+func (ngu *NoteGraphUtil) AddNodeGraphics(nodes []graphml.Node, notes []Note, noteLinks []NoteLink) {
+	nodeStyles := ngu.NodeStyles(notes, noteLinks)
+	for index, note := range notes {
+		nodes[index].Data = append(nodes[index].Data, ngu.CreateNodeGraphics(note, nodeStyles[note.GUID]))
+	}
+}
+
+// NodeStyles returns the NodeStyles of the yFiles graphics by Note GUID, the node size is proportional to the number of NoteLinks
+// of the Note, the fill colour is derived from the notebook or community of the Note, and the node is centered at the position of
+// the Note if the NoteAttributes contain a layout
+func (ngu *NoteGraphUtil) NodeStyles(notes []Note, noteLinks []NoteLink) map[string]NodeStyle {
+	degrees := map[string]int{}
+	maxDegree := 0
+	for _, noteLink := range noteLinks {
+		for _, noteGUID := range []string{noteLink.SourceNoteGUID, noteLink.TargetNoteGUID} {
+			degrees[noteGUID]++
+			if degrees[noteGUID] > maxDegree {
+				maxDegree = degrees[noteGUID]
+			}
+		}
+	}
+
+	nodeColors := ngu.NodeColors(notes)
+	notePositions := NewNoteGraphLayouter(0).NotePositions(ngu.NoteAttributes)
+	nodeStyles := map[string]NodeStyle{}
+	for _, note := range notes {
+		nodeSize := MinNodeSize
+		if maxDegree > 0 {
+			nodeSize += (MaxNodeSize - MinNodeSize) * float64(degrees[note.GUID]) / float64(maxDegree)
+		}
+
+		nodeStyles[note.GUID] = NodeStyle{Size: nodeSize, Color: nodeColors[note.GUID], Position: notePositions[note.GUID]}
+	}
+
+	return nodeStyles
+}
+
+// CreateNodeGraphics creates the yFiles graphics of the GraphML node of the Note with the NodeStyle
+func (ngu *NoteGraphUtil) CreateNodeGraphics(note Note, nodeStyle NodeStyle) graphml.Data {
+	return ngu.GraphMLUtil.CreateNodeGraphics(note.Title, nodeStyle.Position.X, nodeStyle.Position.Y, nodeStyle.Size, nodeStyle.Color)
+}
+
+// AddEdgeGraphics adds yFiles graphics to the GraphML edges
+func (ngu *NoteGraphUtil) AddEdgeGraphics(edges []graphml.Edge) {
+	for index := range edges {
+		edges[index].Data = append(edges[index].Data, ngu.GraphMLUtil.CreateEdgeGraphics(!ngu.Undirected))
+	}
+}
+
+// NodeColors returns the fill colours of the nodes by Note GUID based on the notebook or community of the Notes
+func (ngu *NoteGraphUtil) NodeColors(notes []Note) map[string]string {
+	groups := map[string]string{}
+	for _, note := range notes {
+		if ngu.NodeColorBy == NotebookColor {
+			groups[note.GUID] = note.Notebook
+		} else {
+			for _, noteAttribute := range ngu.NoteAttributes {
+				if noteAttribute.ID == NodeCommunityID {
+					groups[note.GUID] = noteAttribute.Values[note.GUID]
+				}
+			}
+		}
+	}
+
+	groupColors := GroupColors(groups)
+	nodeColors := map[string]string{}
+	for _, note := range notes {
+		nodeColors[note.GUID] = groupColors[groups[note.GUID]]
+	}
+
+	return nodeColors
 }
 
 // GraphNotes returns all Notes to include in the GraphML graph
@@ -67,29 +383,352 @@ func (ngu *NoteGraphUtil) GraphNotes(noteGraph *NoteGraph, allNotes bool) []Note
 	return *noteGraph.GetLinkedNotes()
 }
 
-// GraphNoteLinks returns all NoteLinks to include in the GraphML graph
-func (ngu *NoteGraphUtil) GraphNoteLinks(noteGraph *NoteGraph) []NoteLink {
-	return *noteGraph.GetValidNoteLinks()
+// GraphNoteLinks returns all NoteLinks to include in the GraphML graph ordered by source Note GUID, target Note GUID, text, and URL
+// so that the GraphML document does not depend on the order in which the Notes were retrieved, self-loops are dropped, NoteLinks
+// annotated as reciprocal, merged into undirected NoteLinks, and parallel NoteLinks aggregated if enabled, the weight, URLTypes,
+// and reciprocal NoteLinkAttributes of the NoteLinks are returned for the caller to add
+func (ngu *NoteGraphUtil) GraphNoteLinks(noteGraph *NoteGraph) ([]NoteLink, []NoteLinkAttribute) {
+	noteLinks := SortNoteLinks(append([]NoteLink{}, *noteGraph.GetValidNoteLinks()...))
+	if ngu.DropSelfLoops {
+		noteLinks = ngu.DropSelfLoopNoteLinks(noteLinks)
+	}
+
+	noteLinkAttributes := []NoteLinkAttribute{}
+	reciprocalNoteLinkKeys := ngu.ReciprocalNoteLinkKeys(noteLinks)
+	if ngu.Undirected {
+		noteLinks, noteLinkAttributes = ngu.MergeNoteLinks(noteLinks)
+	} else if ngu.AggregateLinks {
+		noteLinks, noteLinkAttributes = ngu.AggregateNoteLinks(noteLinks)
+	}
+
+	if ngu.Reciprocal {
+		reciprocalValues := map[NoteLinkKey]string{}
+		for _, noteLink := range noteLinks {
+			noteLinkKey := NoteLinkKey{noteLink.SourceNoteGUID, noteLink.TargetNoteGUID}
+			reciprocalValues[noteLinkKey] = strconv.FormatBool(reciprocalNoteLinkKeys[noteLinkKey])
+		}
+
+		noteLinkAttributes = append(noteLinkAttributes, NoteLinkAttribute{ID: EdgeReciprocalID, Name: EdgeReciprocalName, Type: "boolean", Values: reciprocalValues})
+	}
+
+	return noteLinks, noteLinkAttributes
+}
+
+// SortNoteLinks sorts the NoteLinks by source Note GUID, target Note GUID, text, and URL
+func SortNoteLinks(noteLinks []NoteLink) []NoteLink {
+	sort.SliceStable(noteLinks, func(i, j int) bool {
+		if noteLinks[i].SourceNoteGUID != noteLinks[j].SourceNoteGUID {
+			return noteLinks[i].SourceNoteGUID < noteLinks[j].SourceNoteGUID
+		} else if noteLinks[i].TargetNoteGUID != noteLinks[j].TargetNoteGUID {
+			return noteLinks[i].TargetNoteGUID < noteLinks[j].TargetNoteGUID
+		} else if noteLinks[i].Text != noteLinks[j].Text {
+			return noteLinks[i].Text < noteLinks[j].Text
+		}
+
+		return noteLinks[i].URL.String() < noteLinks[j].URL.String()
+	})
+
+	return noteLinks
+}
+
+// ReciprocalNoteLinkKeys returns the NoteLinkKeys of all NoteLinks between two different Notes for which a NoteLink in the opposite
+// direction exists
+func (ngu *NoteGraphUtil) ReciprocalNoteLinkKeys(noteLinks []NoteLink) map[NoteLinkKey]bool {
+	noteLinkKeys := map[NoteLinkKey]bool{}
+	for _, noteLink := range noteLinks {
+		noteLinkKeys[NoteLinkKey{noteLink.SourceNoteGUID, noteLink.TargetNoteGUID}] = true
+	}
+
+	reciprocalNoteLinkKeys := map[NoteLinkKey]bool{}
+	for noteLinkKey := range noteLinkKeys {
+		if noteLinkKey.SourceNoteGUID != noteLinkKey.TargetNoteGUID && noteLinkKeys[NoteLinkKey{noteLinkKey.TargetNoteGUID, noteLinkKey.SourceNoteGUID}] {
+			reciprocalNoteLinkKeys[noteLinkKey] = true
+		}
+	}
+
+	return reciprocalNoteLinkKeys
+}
+
+// MergeNoteLinks merges the NoteLinks in both directions between two Notes into a single undirected NoteLink from the Note with the
+// lower GUID to the Note with the higher GUID, the merged NoteLinks are aggregated and returned with the weight and URLTypes
+// NoteLinkAttributes
+func (ngu *NoteGraphUtil) MergeNoteLinks(noteLinks []NoteLink) ([]NoteLink, []NoteLinkAttribute) {
+	undirectedNoteLinks := []NoteLink{}
+	for _, noteLink := range noteLinks {
+		if noteLink.SourceNoteGUID > noteLink.TargetNoteGUID {
+			noteLink.SourceNoteGUID, noteLink.TargetNoteGUID = noteLink.TargetNoteGUID, noteLink.SourceNoteGUID
+		}
+
+		undirectedNoteLinks = append(undirectedNoteLinks, noteLink)
+	}
+
+	return ngu.AggregateNoteLinks(SortNoteLinks(undirectedNoteLinks))
+}
+
+// EdgeDefault returns the default direction of the edges of the GraphML graph
+func (ngu *NoteGraphUtil) EdgeDefault() graphml.EdgeDir {
+	if ngu.Undirected {
+		return graphml.EdgeUndirected
+	}
+
+	return graphml.EdgeDirected
+}
+
+// DropSelfLoopNoteLinks returns the NoteLinks without the NoteLinks from a Note to itself
+func (ngu *NoteGraphUtil) DropSelfLoopNoteLinks(noteLinks []NoteLink) []NoteLink {
+	filteredNoteLinks := []NoteLink{}
+	for _, noteLink := range noteLinks {
+		if noteLink.SourceNoteGUID != noteLink.TargetNoteGUID {
+			filteredNoteLinks = append(filteredNoteLinks, noteLink)
+		}
+	}
+
+	logrus.Debugf("Dropped [%d] self-loop NoteLinks", len(noteLinks)-len(filteredNoteLinks))
+	return filteredNoteLinks
+}
+
+// AggregateNoteLinks collapses all parallel NoteLinks between the same source and target Note into a single NoteLink with the
+// distinct texts of the NoteLinks and returns the weight and URLTypes of the aggregated NoteLinks as NoteLinkAttributes, the URL
+// and URLType of the first NoteLink are kept
+func (ngu *NoteGraphUtil) AggregateNoteLinks(noteLinks []NoteLink) ([]NoteLink, []NoteLinkAttribute) {
+	aggregatedNoteLinks := []NoteLink{}
+	texts := map[NoteLinkKey][]string{}
+	distinctTexts := map[NoteLinkKey]map[string]bool{}
+	urlTypes := map[NoteLinkKey]map[URLType]bool{}
+	weights := map[NoteLinkKey]int{}
+	for _, noteLink := range noteLinks {
+		noteLinkKey := NoteLinkKey{noteLink.SourceNoteGUID, noteLink.TargetNoteGUID}
+		if weights[noteLinkKey] == 0 {
+			aggregatedNoteLinks = append(aggregatedNoteLinks, noteLink)
+			urlTypes[noteLinkKey] = map[URLType]bool{}
+			distinctTexts[noteLinkKey] = map[string]bool{}
+		}
+
+		weights[noteLinkKey]++
+		urlTypes[noteLinkKey][noteLink.URLType] = true
+		if noteLink.Text != "" && !distinctTexts[noteLinkKey][noteLink.Text] {
+			distinctTexts[noteLinkKey][noteLink.Text] = true
+			texts[noteLinkKey] = append(texts[noteLinkKey], noteLink.Text)
+		}
+	}
+
+	weightValues := map[NoteLinkKey]string{}
+	urlTypesValues := map[NoteLinkKey]string{}
+	for index, aggregatedNoteLink := range aggregatedNoteLinks {
+		noteLinkKey := NoteLinkKey{aggregatedNoteLink.SourceNoteGUID, aggregatedNoteLink.TargetNoteGUID}
+		aggregatedNoteLinks[index].Text = strings.Join(texts[noteLinkKey], AggregatedTextSeparator)
+		weightValues[noteLinkKey] = strconv.Itoa(weights[noteLinkKey])
+
+		noteLinkURLTypes := []string{}
+		for _, urlType := range []URLType{AppLink, WebLink, PublicLink, ShortenedLink} {
+			if urlTypes[noteLinkKey][urlType] {
+				noteLinkURLTypes = append(noteLinkURLTypes, urlType.String())
+			}
+		}
+		urlTypesValues[noteLinkKey] = strings.Join(noteLinkURLTypes, ",")
+	}
+
+	noteLinkAttributes := []NoteLinkAttribute{
+		{ID: EdgeWeightID, Name: EdgeWeightName, Type: "int", Values: weightValues},
+		{ID: EdgeURLTypesID, Name: EdgeURLTypesName, Type: "string", Values: urlTypesValues}}
+
+	logrus.Infof("Aggregated [%d] NoteLinks into [%d] weighted NoteLinks", len(noteLinks), len(aggregatedNoteLinks))
+	return aggregatedNoteLinks, noteLinkAttributes
 }
 
-// CreateNodes creates a GraphML node from the Note
+// CreateNodes creates the GraphML nodes from the Notes
 func (ngu *NoteGraphUtil) CreateNodes(notes []Note) []graphml.Node {
 	nodes := []graphml.Node{}
 	for _, note := range notes {
-		node := ngu.GraphMLUtil.CreateNode(note.GUID, note.Title, strings.ReplaceAll(note.Description, " ", "‧"), note.URL.String())
-		nodes = append(nodes, *node)
+		nodes = append(nodes, ngu.CreateNode(note))
 	}
 
 	return nodes
 }
 
-// CreateEdges creates a GraphML edge from the NoteLink
+// CreateNode creates a GraphML node from the Note with the values of the NoteAttributes
+func (ngu *NoteGraphUtil) CreateNode(note Note) graphml.Node {
+	node := ngu.GraphMLUtil.CreateNode(note.GUID, note.Title, strings.ReplaceAll(note.Description, " ", "‧"), note.URL.String())
+	for _, noteAttribute := range ngu.NoteAttributes {
+		if value, found := noteAttribute.Values[note.GUID]; found {
+			node.Data = append(node.Data, graphml.NewData(noteAttribute.ID, value))
+		}
+	}
+
+	return *node
+}
+
+// CreateEdges creates the GraphML edges from the NoteLinks
 func (ngu *NoteGraphUtil) CreateEdges(noteLinks []NoteLink) []graphml.Edge {
 	edges := []graphml.Edge{}
-	for _, noteLink := range noteLinks {
-		edge := ngu.GraphMLUtil.CreateEdge(uuid.NewV4().String(), noteLink.SourceNoteGUID, noteLink.TargetNoteGUID, noteLink.Text, strings.ReplaceAll(noteLink.Text, " ", "‧"))
-		edges = append(edges, *edge)
+	for _, edgeNoteLink := range CanonicalNoteLinks(noteLinks) {
+		edges = append(edges, ngu.CreateEdge(edgeNoteLink.EdgeID, edgeNoteLink.NoteLink))
 	}
 
 	return edges
 }
+
+// CreateEdge creates a GraphML edge with the specified id from the NoteLink with the values of the NoteLinkAttributes
+func (ngu *NoteGraphUtil) CreateEdge(edgeID string, noteLink NoteLink) graphml.Edge {
+	edge := ngu.GraphMLUtil.CreateEdge(edgeID, noteLink.SourceNoteGUID, noteLink.TargetNoteGUID, noteLink.Text, strings.ReplaceAll(noteLink.Text, " ", "‧"))
+	for _, noteLinkAttribute := range ngu.NoteLinkAttributes {
+		if value, found := noteLinkAttribute.Value(edgeID, noteLink); found {
+			edge.Data = append(edge.Data, graphml.NewData(noteLinkAttribute.ID, value))
+		}
+	}
+
+	return *edge
+}
+
+// ConvertGraphMLDocument converts a GraphML document created with ConvertNoteGraph back into a NoteGraph
+// GraphML documents do not contain the URLs of NoteLinks, the URL and URLType of the target Note are used instead
+func (ngu *NoteGraphUtil) ConvertGraphMLDocument(graphMLDocument *graphml.Document) (*NoteGraph, error) {
+	if len(graphMLDocument.Graphs) != 1 {
+		return nil, fmt.Errorf("Failed to convert GraphML document with [%d] graphs to NoteGraph: expected exactly one graph", len(graphMLDocument.Graphs))
+	}
+
+	graph := graphMLDocument.Graphs[0]
+	noteGraph := NewNoteGraph()
+	for _, node := range graph.Nodes {
+		note, err := ngu.CreateNote(node)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to convert GraphML node with ID [%s] to Note: %w", node.ID, err)
+		}
+
+		noteGraph.Add(*note, []NoteLink{})
+	}
+
+	for _, edge := range graph.Edges {
+		noteGraph.NoteLinks = append(noteGraph.NoteLinks, *ngu.CreateNoteLink(noteGraph, edge))
+	}
+
+	logrus.Infof("Converted GraphML with [%d|%d] nodes|Notes and [%d|%d] edges|NoteLinks to NoteGraph", len(graph.Nodes), len(noteGraph.Notes), len(graph.Edges), len(noteGraph.NoteLinks))
+	return noteGraph, nil
+}
+
+// ConvertGraphMLAttributes converts the node and edge GraphML attributes of a GraphML document that are not part of the standardised
+// GraphML attributes definition into NoteAttributes and NoteLinkAttributes, yFiles graphics are not converted, edge attribute values
+// are kept by edge ID so that parallel edges keep their own values, and by source and target Note for GraphML documents whose edge
+// IDs were not created by CanonicalNoteLinks
+func (ngu *NoteGraphUtil) ConvertGraphMLAttributes(graphMLDocument *graphml.Document) ([]NoteAttribute, []NoteLinkAttribute) {
+	standardKeyIDs := map[string]bool{NodeLabelID: true, NodeDescriptionID: true, NodeURLID: true, EdgeLabelID: true, EdgeDescriptionID: true}
+	noteAttributes := []NoteAttribute{}
+	noteLinkAttributes := []NoteLinkAttribute{}
+	for _, key := range graphMLDocument.Keys {
+		if standardKeyIDs[key.ID] || ngu.IsYFilesKey(key) {
+			continue
+		}
+
+		name := key.Name
+		if name == "" {
+			name = key.ID
+		}
+
+		if key.For == graphml.KindNode {
+			noteAttribute := NoteAttribute{ID: key.ID, Name: name, Type: key.Type, Values: map[string]string{}}
+			for _, graph := range graphMLDocument.Graphs {
+				for _, node := range graph.Nodes {
+					if value, found := ngu.GraphMLUtil.GetDataValue(node.Data, key.ID); found {
+						noteAttribute.Values[node.ID] = value
+					}
+				}
+			}
+
+			noteAttributes = append(noteAttributes, noteAttribute)
+		} else if key.For == graphml.KindEdge {
+			noteLinkAttribute := NoteLinkAttribute{ID: key.ID, Name: name, Type: key.Type, Values: map[NoteLinkKey]string{}, EdgeValues: map[string]string{}}
+			for _, graph := range graphMLDocument.Graphs {
+				for _, edge := range graph.Edges {
+					if value, found := ngu.GraphMLUtil.GetDataValue(edge.Data, key.ID); found {
+						noteLinkAttribute.EdgeValues[edge.ID] = value
+						noteLinkAttribute.Values[NoteLinkKey{edge.Source, edge.Target}] = value
+					}
+				}
+			}
+
+			noteLinkAttributes = append(noteLinkAttributes, noteLinkAttribute)
+		}
+	}
+
+	logrus.Infof("Converted [%d] GraphML node attributes and [%d] GraphML edge attributes", len(noteAttributes), len(noteLinkAttributes))
+	return noteAttributes, noteLinkAttributes
+}
+
+// IsYFilesKey returns true if the GraphML attribute definition is a yFiles extension such as node or edge graphics
+func (ngu *NoteGraphUtil) IsYFilesKey(key graphml.Key) bool {
+	for _, attr := range key.Unrecognized {
+		if attr.Name.Local == "yfiles.type" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CreateNote creates a Note from the GraphML node
+func (ngu *NoteGraphUtil) CreateNote(node graphml.Node) (*Note, error) {
+	if node.ID == "" {
+		return nil, errors.New("GraphML node without ID")
+	}
+
+	title, _ := ngu.GraphMLUtil.GetDataValue(node.Data, NodeLabelID)
+	description, _ := ngu.GraphMLUtil.GetDataValue(node.Data, NodeDescriptionID)
+	rawURL, _ := ngu.GraphMLUtil.GetDataValue(node.Data, NodeURLID)
+
+	noteURL, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse URL [%s]: %w", rawURL, err)
+	}
+
+	noteURLType := WebLink
+	if noteURL.Scheme == "evernote" {
+		noteURLType = AppLink
+	}
+
+	return &Note{GUID: node.ID, Title: title, Description: strings.ReplaceAll(description, "‧", " "), URL: *noteURL, URLType: noteURLType}, nil
+}
+
+// CreateNoteLink creates a NoteLink from the GraphML edge
+func (ngu *NoteGraphUtil) CreateNoteLink(noteGraph *NoteGraph, edge graphml.Edge) *NoteLink {
+	text, _ := ngu.GraphMLUtil.GetDataValue(edge.Data, EdgeLabelID)
+	noteLink := &NoteLink{SourceNoteGUID: edge.Source, TargetNoteGUID: edge.Target, Text: text}
+	if targetNote := noteGraph.GetNote(edge.Target); targetNote != nil {
+		noteLink.URL = targetNote.URL
+		noteLink.URLType = targetNote.URLType
+	}
+
+	return noteLink
+}
+
+// GroupColors returns the fill colours of the groups (notebooks or communities) of the Notes by group, groups are assigned NodeColors
+// in order of name or numeric ID (largest community first) and the empty group gets the DefaultNodeColor
+func GroupColors(groups map[string]string) map[string]string {
+	distinctGroups := map[string]bool{}
+	for _, group := range groups {
+		if group != "" {
+			distinctGroups[group] = true
+		}
+	}
+
+	sortedGroups := []string{}
+	for group := range distinctGroups {
+		sortedGroups = append(sortedGroups, group)
+	}
+
+	sort.Slice(sortedGroups, func(i, j int) bool {
+		iID, iErr := strconv.Atoi(sortedGroups[i])
+		jID, jErr := strconv.Atoi(sortedGroups[j])
+		if iErr == nil && jErr == nil {
+			return iID < jID
+		}
+
+		return sortedGroups[i] < sortedGroups[j]
+	})
+
+	groupColors := map[string]string{"": DefaultNodeColor}
+	for index, group := range sortedGroups {
+		groupColors[group] = NodeColors[index%len(NodeColors)]
+	}
+
+	return groupColors
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"bytes"
 	"strings"
 	"testing"
 
@@ -19,6 +20,188 @@ func TestCreateEdges(t *testing.T) {
 	assert.Equal(t, webNoteLink.TargetNoteGUID, edges[0].Target)
 }
 
+func TestCreateEdgesEdgeIDs(t *testing.T) {
+	noteLinks := []NoteLink{{SourceNoteGUID: "A", TargetNoteGUID: "B"}, {SourceNoteGUID: "A", TargetNoteGUID: "C"}, {SourceNoteGUID: "A", TargetNoteGUID: "B"}}
+	edges := NewNoteGraphUtil().CreateEdges(noteLinks)
+
+	assert.Equal(t, "A-B-1", edges[0].ID)
+	assert.Equal(t, "A-B-2", edges[1].ID)
+	assert.Equal(t, "A-C-1", edges[2].ID)
+}
+
+func TestCanonicalNoteLinksSameEdgeIDsInAllOutputFormats(t *testing.T) {
+	noteGraph := NewNoteGraph()
+	noteGraph.Add(Note{GUID: "A", Title: "TitleA"}, []NoteLink{
+		{SourceNoteGUID: "A", TargetNoteGUID: "B", Text: "zeta"},
+		{SourceNoteGUID: "A", TargetNoteGUID: "B", Text: "alpha"}})
+	noteGraph.Add(Note{GUID: "B", Title: "TitleB"}, []NoteLink{})
+
+	edgeNoteLinks := CanonicalNoteLinks(*noteGraph.GetNoteLinks())
+	assert.Equal(t, "A-B-1", edgeNoteLinks[0].EdgeID)
+	assert.Equal(t, "alpha", edgeNoteLinks[0].NoteLink.Text)
+	assert.Equal(t, "A-B-2", edgeNoteLinks[1].EdgeID)
+	assert.Equal(t, "zeta", edgeNoteLinks[1].NoteLink.Text)
+
+	relationships := NewCypherUtil(false).CreateRelationships(noteGraph)
+	assert.Equal(t, []string{"A-B-1", "A-B-2"}, []string{relationships[0].EdgeID, relationships[1].EdgeID})
+	assert.Equal(t, []string{"alpha", "zeta"}, []string{relationships[0].NoteLink.Text, relationships[1].NoteLink.Text})
+
+	edges := NewNoteGraphUtil().CreateEdges(*noteGraph.GetValidNoteLinks())
+	assert.Equal(t, []string{"A-B-1", "A-B-2"}, []string{edges[0].ID, edges[1].ID})
+}
+
+func TestConvertNoteGraphAggregateLinks(t *testing.T) {
+	noteGraph := NewNoteGraph()
+	noteGraph.Add(Note{GUID: "A", Title: "TitleA"}, []NoteLink{
+		{SourceNoteGUID: "A", TargetNoteGUID: "B", Text: "first", URLType: WebLink},
+		{SourceNoteGUID: "A", TargetNoteGUID: "B", Text: "second", URLType: AppLink},
+		{SourceNoteGUID: "A", TargetNoteGUID: "B", Text: "first", URLType: WebLink},
+		{SourceNoteGUID: "A", TargetNoteGUID: "C", Text: "third", URLType: WebLink},
+		{SourceNoteGUID: "A", TargetNoteGUID: "A", Text: "self", URLType: WebLink}})
+	noteGraph.Add(Note{GUID: "B", Title: "TitleB"}, []NoteLink{})
+	noteGraph.Add(Note{GUID: "C", Title: "TitleC"}, []NoteLink{})
+
+	noteGraphUtil := NewNoteGraphUtil()
+	noteGraphUtil.AggregateLinks = true
+	noteGraphUtil.DropSelfLoops = true
+	graphMLDocument := noteGraphUtil.ConvertNoteGraph(noteGraph, true)
+	xmlDocument, err := xmlquery.Parse(strings.NewReader(EncodeGraphMLDocument(graphMLDocument)))
+	if err != nil {
+		panic(err)
+	}
+
+	AssertKeyEqual(t, xmlDocument, EdgeWeightID, "edge", EdgeWeightName, "int")
+	AssertKeyEqual(t, xmlDocument, EdgeURLTypesID, "edge", EdgeURLTypesName, "string")
+
+	edges := xmlquery.Find(xmlDocument, "/graphml/graph/edge")
+	assert.Equal(t, 2, len(edges))
+	assert.Equal(t, "A-B-1", edges[0].SelectAttr("id"))
+	assert.Equal(t, "first; second", xmlquery.FindOne(edges[0], "data[@key='"+EdgeLabelID+"']").InnerText())
+	assert.Equal(t, "3", xmlquery.FindOne(edges[0], "data[@key='"+EdgeWeightID+"']").InnerText())
+	assert.Equal(t, "AppLink,WebLink", xmlquery.FindOne(edges[0], "data[@key='"+EdgeURLTypesID+"']").InnerText())
+	assert.Equal(t, "A-C-1", edges[1].SelectAttr("id"))
+	assert.Equal(t, "1", xmlquery.FindOne(edges[1], "data[@key='"+EdgeWeightID+"']").InnerText())
+	assert.Equal(t, "WebLink", xmlquery.FindOne(edges[1], "data[@key='"+EdgeURLTypesID+"']").InnerText())
+}
+
+func TestGraphNoteLinksAggregateLinks(t *testing.T) {
+	noteGraph := NewNoteGraph()
+	noteGraph.Add(Note{GUID: "A", Title: "TitleA"}, []NoteLink{
+		{SourceNoteGUID: "A", TargetNoteGUID: "B", Text: "first", URLType: WebLink},
+		{SourceNoteGUID: "A", TargetNoteGUID: "B", Text: "second", URLType: AppLink}})
+	noteGraph.Add(Note{GUID: "B", Title: "TitleB"}, []NoteLink{{SourceNoteGUID: "B", TargetNoteGUID: "A", Text: "back", URLType: WebLink}})
+
+	noteGraphUtil := NewNoteGraphUtil()
+	noteGraphUtil.AggregateLinks = true
+	noteGraphUtil.Reciprocal = true
+	noteLinks, noteLinkAttributes := noteGraphUtil.GraphNoteLinks(noteGraph)
+
+	assert.Equal(t, 2, len(noteLinks))
+	assert.Equal(t, "first; second", noteLinks[0].Text)
+	assert.Empty(t, noteGraphUtil.NoteLinkAttributes)
+	assert.Equal(t, 3, len(noteLinkAttributes))
+	assert.Equal(t, EdgeWeightID, noteLinkAttributes[0].ID)
+	assert.Equal(t, "2", noteLinkAttributes[0].Values[NoteLinkKey{"A", "B"}])
+	assert.Equal(t, EdgeURLTypesID, noteLinkAttributes[1].ID)
+	assert.Equal(t, "AppLink,WebLink", noteLinkAttributes[1].Values[NoteLinkKey{"A", "B"}])
+	assert.Equal(t, EdgeReciprocalID, noteLinkAttributes[2].ID)
+	assert.Equal(t, "true", noteLinkAttributes[2].Values[NoteLinkKey{"B", "A"}])
+}
+
+func TestConvertNoteGraphUndirected(t *testing.T) {
+	noteGraph := NewNoteGraph()
+	noteGraph.Add(Note{GUID: "A", Title: "TitleA"}, []NoteLink{{SourceNoteGUID: "A", TargetNoteGUID: "B", Text: "A->B"}, {SourceNoteGUID: "A", TargetNoteGUID: "C", Text: "A->C"}})
+	noteGraph.Add(Note{GUID: "B", Title: "TitleB"}, []NoteLink{{SourceNoteGUID: "B", TargetNoteGUID: "A", Text: "B->A"}})
+	noteGraph.Add(Note{GUID: "C", Title: "TitleC"}, []NoteLink{})
+
+	noteGraphUtil := NewNoteGraphUtil()
+	noteGraphUtil.Undirected = true
+	noteGraphUtil.Reciprocal = true
+	noteGraphUtil.YFiles = true
+	xmlDocument, err := xmlquery.Parse(strings.NewReader(EncodeGraphMLDocument(noteGraphUtil.ConvertNoteGraph(noteGraph, true))))
+	if err != nil {
+		panic(err)
+	}
+
+	assert.Equal(t, string(graphml.EdgeUndirected), xmlquery.FindOne(xmlDocument, "/graphml/graph").SelectAttr("edgedefault"))
+
+	edges := xmlquery.Find(xmlDocument, "/graphml/graph/edge")
+	assert.Equal(t, 2, len(edges))
+	assert.Equal(t, "A", edges[0].SelectAttr("source"))
+	assert.Equal(t, "B", edges[0].SelectAttr("target"))
+	assert.Equal(t, "A->B; B->A", xmlquery.FindOne(edges[0], "data[@key='"+EdgeLabelID+"']").InnerText())
+	assert.Equal(t, "2", xmlquery.FindOne(edges[0], "data[@key='"+EdgeWeightID+"']").InnerText())
+	assert.Equal(t, "true", xmlquery.FindOne(edges[0], "data[@key='"+EdgeReciprocalID+"']").InnerText())
+	assert.Equal(t, "none", xmlquery.FindOne(edges[0], "data[@key='"+EdgeGraphicsID+"']/y:PolyLineEdge/y:Arrows").SelectAttr("target"))
+	assert.Equal(t, "false", xmlquery.FindOne(edges[1], "data[@key='"+EdgeReciprocalID+"']").InnerText())
+}
+
+func TestConvertNoteGraphReciprocal(t *testing.T) {
+	noteGraph := NewNoteGraph()
+	noteGraph.Add(Note{GUID: "A", Title: "TitleA"}, []NoteLink{{SourceNoteGUID: "A", TargetNoteGUID: "B"}, {SourceNoteGUID: "A", TargetNoteGUID: "C"}, {SourceNoteGUID: "A", TargetNoteGUID: "A"}})
+	noteGraph.Add(Note{GUID: "B", Title: "TitleB"}, []NoteLink{{SourceNoteGUID: "B", TargetNoteGUID: "A"}})
+	noteGraph.Add(Note{GUID: "C", Title: "TitleC"}, []NoteLink{})
+
+	noteGraphUtil := NewNoteGraphUtil()
+	noteGraphUtil.Reciprocal = true
+	xmlDocument, err := xmlquery.Parse(strings.NewReader(EncodeGraphMLDocument(noteGraphUtil.ConvertNoteGraph(noteGraph, true))))
+	if err != nil {
+		panic(err)
+	}
+
+	AssertKeyEqual(t, xmlDocument, EdgeReciprocalID, "edge", EdgeReciprocalName, "boolean")
+	assert.Equal(t, string(graphml.EdgeDirected), xmlquery.FindOne(xmlDocument, "/graphml/graph").SelectAttr("edgedefault"))
+
+	reciprocal := map[string]string{}
+	for _, edge := range xmlquery.Find(xmlDocument, "/graphml/graph/edge") {
+		reciprocal[edge.SelectAttr("source")+edge.SelectAttr("target")] = xmlquery.FindOne(edge, "data[@key='"+EdgeReciprocalID+"']").InnerText()
+	}
+
+	assert.Equal(t, map[string]string{"AA": "false", "AB": "true", "AC": "false", "BA": "true"}, reciprocal)
+	assert.Equal(t, map[NoteLinkKey]bool{{"A", "B"}: true, {"B", "A"}: true}, noteGraphUtil.ReciprocalNoteLinkKeys(*noteGraph.GetValidNoteLinks()))
+}
+
+func TestDropSelfLoopNoteLinks(t *testing.T) {
+	noteLinks := []NoteLink{{SourceNoteGUID: "A", TargetNoteGUID: "A"}, {SourceNoteGUID: "A", TargetNoteGUID: "B"}}
+
+	assert.Equal(t, []NoteLink{{SourceNoteGUID: "A", TargetNoteGUID: "B"}}, NewNoteGraphUtil().DropSelfLoopNoteLinks(noteLinks))
+}
+
+func TestExportNoteGraphDeterministic(t *testing.T) {
+	noteA := Note{GUID: "A", Title: "TitleA", URL: *CreateWebLinkURL("A")}
+	noteB := Note{GUID: "B", Title: "TitleB", URL: *CreateWebLinkURL("B")}
+	noteC := Note{GUID: "C", Title: "TitleC", URL: *CreateWebLinkURL("C")}
+	noteLinkAB1 := NoteLink{SourceNoteGUID: "A", TargetNoteGUID: "B", Text: "first", URL: *CreateWebLinkURL("B")}
+	noteLinkAB2 := NoteLink{SourceNoteGUID: "A", TargetNoteGUID: "B", Text: "second", URL: *CreateWebLinkURL("B")}
+	noteLinkAC := NoteLink{SourceNoteGUID: "A", TargetNoteGUID: "C", Text: "third", URL: *CreateWebLinkURL("C")}
+	noteLinkCA := NoteLink{SourceNoteGUID: "C", TargetNoteGUID: "A", Text: "fourth", URL: *CreateWebLinkURL("A")}
+
+	noteGraph := NewNoteGraph()
+	noteGraph.Add(noteA, []NoteLink{noteLinkAB1, noteLinkAB2, noteLinkAC})
+	noteGraph.Add(noteB, []NoteLink{})
+	noteGraph.Add(noteC, []NoteLink{noteLinkCA})
+
+	reorderedNoteGraph := NewNoteGraph()
+	reorderedNoteGraph.Add(noteC, []NoteLink{noteLinkCA})
+	reorderedNoteGraph.Add(noteB, []NoteLink{})
+	reorderedNoteGraph.Add(noteA, []NoteLink{noteLinkAC, noteLinkAB2, noteLinkAB1})
+
+	noteGraphUtil := NewNoteGraphUtil()
+	noteGraphUtil.YFiles = true
+	exportedGraphMLDocuments := []bytes.Buffer{{}, {}, {}}
+	for index, exportNoteGraph := range []*NoteGraph{noteGraph, noteGraph, reorderedNoteGraph} {
+		err := noteGraphUtil.ExportNoteGraph(exportNoteGraph, true, &exportedGraphMLDocuments[index])
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	assert.Equal(t, exportedGraphMLDocuments[0].Bytes(), exportedGraphMLDocuments[1].Bytes())
+	assert.Equal(t, exportedGraphMLDocuments[0].Bytes(), exportedGraphMLDocuments[2].Bytes())
+	assert.Equal(t, EncodeGraphMLDocument(noteGraphUtil.ConvertNoteGraph(reorderedNoteGraph, true)), exportedGraphMLDocuments[0].String())
+	assert.True(t, strings.Contains(exportedGraphMLDocuments[0].String(), `<edge id="A-B-2" source="A" target="B"><data key="edge-label">second</data>`))
+}
+
 func TestCreateNodes(t *testing.T) {
 	webLinkURL := CreateWebLinkURL("GUID")
 	note := Note{GUID: "GUID", Title: "Title", Description: "Title", URL: *webLinkURL, URLType: WebLink}
@@ -142,3 +325,154 @@ func AssertNoteLinkEqualEdge(t *testing.T, xmlNode *xmlquery.Node, edgeIndex, so
 	assert.Equal(t, edgeLabel, xmlquery.FindOne(edge, "/data[@key='"+EdgeLabelID+"']").InnerText())
 	assert.Equal(t, edgeDescription, xmlquery.FindOne(edge, "/data[@key='"+EdgeDescriptionID+"']").InnerText())
 }
+
+func TestConvertGraphMLDocument(t *testing.T) {
+	noteA := Note{GUID: "A", Title: "TitleA", Description: "Description A", URL: *CreateWebLinkURL("A"), URLType: WebLink}
+	noteB := Note{GUID: "B", Title: "TitleB", Description: "Description B", URL: *CreateAppLinkURL("B"), URLType: AppLink}
+	noteLinkAB := NoteLink{SourceNoteGUID: "A", TargetNoteGUID: "B", Text: "A->B", URL: *CreateAppLinkURL("B"), URLType: AppLink}
+
+	noteGraph := NewNoteGraph()
+	noteGraph.Add(noteA, []NoteLink{noteLinkAB})
+	noteGraph.Add(noteB, []NoteLink{})
+
+	noteGraphUtil := NewNoteGraphUtil()
+	graphMLDocument := DecodeGraphMLDocument(EncodeGraphMLDocument(noteGraphUtil.ConvertNoteGraph(noteGraph, true)))
+	convertedNoteGraph, err := noteGraphUtil.ConvertGraphMLDocument(graphMLDocument)
+	if err != nil {
+		panic(err)
+	}
+
+	assert.Equal(t, noteGraph, convertedNoteGraph)
+}
+
+func TestExportNoteGraph(t *testing.T) {
+	noteGraph := NewNoteGraph()
+	noteGraph.Add(Note{GUID: "A", Title: "Title <A>", Description: "Description A", Notebook: "Home"}, []NoteLink{{SourceNoteGUID: "A", TargetNoteGUID: "B", Text: "A & B"}})
+	noteGraph.Add(Note{GUID: "B", Title: "TitleB", Notebook: "Work"}, []NoteLink{{SourceNoteGUID: "B", TargetNoteGUID: "C"}})
+	noteGraph.Add(Note{GUID: "C", Title: "TitleC"}, []NoteLink{})
+	noteGraph.Add(Note{GUID: "D", Title: "TitleD"}, []NoteLink{})
+
+	noteGraphUtil := NewNoteGraphUtil()
+	noteGraphUtil.YFiles = true
+	noteGraphUtil.AddNoteAttributes(NoteAttribute{ID: NodeCommunityID, Name: NodeCommunityName, Type: "int", Values: map[string]string{"A": "0", "B": "0"}})
+	noteGraphUtil.AddNoteLinkAttributes(NoteLinkAttribute{ID: "edge-change", Name: "change", Type: "string", Values: map[NoteLinkKey]string{{"A", "B"}: "added"}})
+
+	exportedGraphMLDocument := bytes.Buffer{}
+	err := noteGraphUtil.ExportNoteGraph(noteGraph, true, &exportedGraphMLDocument)
+
+	assert.Nil(t, err)
+	assert.Equal(t, EncodeGraphMLDocument(noteGraphUtil.ConvertNoteGraph(noteGraph, true)), exportedGraphMLDocument.String())
+}
+
+func TestConvertGraphMLAttributes(t *testing.T) {
+	noteGraph := NewNoteGraph()
+	noteGraph.Add(Note{GUID: "A", Title: "TitleA"}, []NoteLink{{SourceNoteGUID: "A", TargetNoteGUID: "B"}})
+	noteGraph.Add(Note{GUID: "B", Title: "TitleB"}, []NoteLink{})
+
+	communityAttribute := NoteAttribute{ID: NodeCommunityID, Name: NodeCommunityName, Type: "int", Values: map[string]string{"A": "0", "B": "1"}}
+	xAttribute := NoteAttribute{ID: NodeXID, Name: NodeXName, Type: "double", Values: map[string]string{"A": "-10.50", "B": "10.50"}}
+	changeAttribute := NoteLinkAttribute{ID: "edge-change", Name: "change", Type: "string", Values: map[NoteLinkKey]string{{"A", "B"}: "added"}, EdgeValues: map[string]string{"A-B-1": "added"}}
+
+	noteGraphUtil := NewNoteGraphUtil()
+	noteGraphUtil.YFiles = true
+	noteGraphUtil.AddNoteAttributes(communityAttribute, xAttribute)
+	noteGraphUtil.AddNoteLinkAttributes(changeAttribute)
+	graphMLDocument := DecodeGraphMLDocument(EncodeGraphMLDocument(noteGraphUtil.ConvertNoteGraph(noteGraph, true)))
+
+	noteAttributes, noteLinkAttributes := NewNoteGraphUtil().ConvertGraphMLAttributes(graphMLDocument)
+
+	assert.Equal(t, []NoteAttribute{communityAttribute, xAttribute}, noteAttributes)
+	assert.Equal(t, []NoteLinkAttribute{changeAttribute}, noteLinkAttributes)
+}
+
+func TestConvertGraphMLAttributesParallelEdges(t *testing.T) {
+	noteGraph := NewNoteGraph()
+	noteGraph.Add(Note{GUID: "A", Title: "TitleA"}, []NoteLink{{SourceNoteGUID: "A", TargetNoteGUID: "B", Text: "zeta"}, {SourceNoteGUID: "A", TargetNoteGUID: "B", Text: "alpha"}})
+	noteGraph.Add(Note{GUID: "B", Title: "TitleB"}, []NoteLink{})
+
+	noteGraphUtil := NewNoteGraphUtil()
+	noteGraphUtil.AddNoteLinkAttributes(NoteLinkAttribute{ID: "edge-change", Name: "change", Type: "string", Values: map[NoteLinkKey]string{}, EdgeValues: map[string]string{"A-B-1": "removed", "A-B-2": "unchanged"}})
+	graphMLDocument := DecodeGraphMLDocument(EncodeGraphMLDocument(noteGraphUtil.ConvertNoteGraph(noteGraph, true)))
+
+	loadedNoteGraph, err := NewNoteGraphUtil().ConvertGraphMLDocument(graphMLDocument)
+	if err != nil {
+		panic(err)
+	}
+	_, noteLinkAttributes := NewNoteGraphUtil().ConvertGraphMLAttributes(graphMLDocument)
+
+	reexportNoteGraphUtil := NewNoteGraphUtil()
+	reexportNoteGraphUtil.AddNoteLinkAttributes(noteLinkAttributes...)
+	xmlDocument, err := xmlquery.Parse(strings.NewReader(EncodeGraphMLDocument(reexportNoteGraphUtil.ConvertNoteGraph(loadedNoteGraph, true))))
+	if err != nil {
+		panic(err)
+	}
+
+	edges := xmlquery.Find(xmlDocument, "/graphml/graph/edge")
+	assert.Equal(t, 2, len(edges))
+	assert.Equal(t, "alpha", xmlquery.FindOne(edges[0], "data[@key='"+EdgeLabelID+"']").InnerText())
+	assert.Equal(t, "removed", xmlquery.FindOne(edges[0], "data[@key='edge-change']").InnerText())
+	assert.Equal(t, "zeta", xmlquery.FindOne(edges[1], "data[@key='"+EdgeLabelID+"']").InnerText())
+	assert.Equal(t, "unchanged", xmlquery.FindOne(edges[1], "data[@key='edge-change']").InnerText())
+}
+
+func TestConvertNoteGraphYFiles(t *testing.T) {
+	// A links to B and C, B and C are in notebook Work, D is not linked and has no notebook
+	noteGraph := NewNoteGraph()
+	noteGraph.Add(Note{GUID: "A", Title: "TitleA", Notebook: "Home"}, []NoteLink{{SourceNoteGUID: "A", TargetNoteGUID: "B"}, {SourceNoteGUID: "A", TargetNoteGUID: "C"}})
+	noteGraph.Add(Note{GUID: "B", Title: "TitleB", Notebook: "Work"}, []NoteLink{})
+	noteGraph.Add(Note{GUID: "C", Title: "TitleC", Notebook: "Work"}, []NoteLink{})
+	noteGraph.Add(Note{GUID: "D", Title: "TitleD"}, []NoteLink{})
+
+	noteGraphUtil := NewNoteGraphUtil()
+	noteGraphUtil.YFiles = true
+	encodedGraphMLDocument := EncodeGraphMLDocument(noteGraphUtil.ConvertNoteGraph(noteGraph, true))
+	xmlDocument, err := xmlquery.Parse(strings.NewReader(encodedGraphMLDocument))
+	if err != nil {
+		panic(err)
+	}
+
+	assert.Equal(t, "nodegraphics", xmlquery.FindOne(xmlDocument, "/graphml/key[@id='"+NodeGraphicsID+"']").SelectAttr("yfiles.type"))
+	assert.Equal(t, "edgegraphics", xmlquery.FindOne(xmlDocument, "/graphml/key[@id='"+EdgeGraphicsID+"']").SelectAttr("yfiles.type"))
+
+	AssertShapeNode(t, xmlDocument, "A", "TitleA", "90.0", NodeColors[0])
+	AssertShapeNode(t, xmlDocument, "B", "TitleB", "60.0", NodeColors[1])
+	AssertShapeNode(t, xmlDocument, "C", "TitleC", "60.0", NodeColors[1])
+	AssertShapeNode(t, xmlDocument, "D", "TitleD", "30.0", DefaultNodeColor)
+
+	arrows := xmlquery.Find(xmlDocument, "/graphml/graph/edge/data[@key='"+EdgeGraphicsID+"']/y:PolyLineEdge/y:Arrows")
+	assert.Equal(t, 2, len(arrows))
+	assert.Equal(t, "standard", arrows[0].SelectAttr("target"))
+}
+
+func TestNodeColorsByCommunity(t *testing.T) {
+	notes := []Note{{GUID: "A"}, {GUID: "B"}, {GUID: "C"}, {GUID: "D"}}
+	noteGraphUtil := NewNoteGraphUtil()
+	noteGraphUtil.NodeColorBy = CommunityColor
+	noteGraphUtil.AddNoteAttributes(NoteAttribute{ID: NodeCommunityID, Name: NodeCommunityName, Type: "int", Values: map[string]string{"A": "10", "B": "2", "C": "2"}})
+
+	nodeColors := noteGraphUtil.NodeColors(notes)
+
+	assert.Equal(t, map[string]string{"A": NodeColors[1], "B": NodeColors[0], "C": NodeColors[0], "D": DefaultNodeColor}, nodeColors)
+}
+
+func TestNewNodeColorBy(t *testing.T) {
+	notebookColor, err := NewNodeColorBy("notebook")
+	assert.Nil(t, err)
+	assert.Equal(t, NotebookColor, *notebookColor)
+
+	communityColor, err := NewNodeColorBy("community")
+	assert.Nil(t, err)
+	assert.Equal(t, CommunityColor, *communityColor)
+
+	_, err = NewNodeColorBy("tag")
+	assert.NotNil(t, err)
+}
+
+func AssertShapeNode(t *testing.T, xmlNode *xmlquery.Node, nodeID, nodeLabel, nodeSize, fillColor string) {
+	shapeNode := xmlquery.FindOne(xmlNode, "/graphml/graph/node[@id='"+nodeID+"']/data[@key='"+NodeGraphicsID+"']/y:ShapeNode")
+	assert.Equal(t, nodeSize, xmlquery.FindOne(shapeNode, "y:Geometry").SelectAttr("width"))
+	assert.Equal(t, nodeSize, xmlquery.FindOne(shapeNode, "y:Geometry").SelectAttr("height"))
+	assert.Equal(t, fillColor, xmlquery.FindOne(shapeNode, "y:Fill").SelectAttr("color"))
+	assert.Equal(t, nodeLabel, xmlquery.FindOne(shapeNode, "y:NodeLabel").InnerText())
+	assert.Equal(t, "ellipse", xmlquery.FindOne(shapeNode, "y:Shape").SelectAttr("type"))
+}
@@ -28,7 +28,7 @@ func NewNoteLinkParser(evernoteHost, userID, shardID string) *NoteLinkParser {
 func (elp *NoteLinkParser) ExtractNoteLinks(noteGUID, noteContent string) ([]NoteLink, error) {
 	enmlDocument, err := htmlquery.Parse(strings.NewReader(noteContent))
 	if err != nil {
-		return nil, fmt.Errorf("Failed to parse note content of note with GUID [%s]: %w", noteGUID, err)
+		return nil, NewNoteGraphError(ParseError, err, "Failed to parse note content of note with GUID [%s]", noteGUID)
 	}
 
 	noteLinks := []NoteLink{}
@@ -38,7 +38,7 @@ func (elp *NoteLinkParser) ExtractNoteLinks(noteGUID, noteContent string) ([]Not
 		linkText := htmlquery.InnerText(a)
 		linkURL, err := url.Parse(linkHref)
 		if err != nil {
-			logrus.Errorf("Failed to parse URL with href [%s] and text [%s] in content of note with GUID [%s]: %v", linkHref, linkText, noteGUID, err)
+			logrus.WithFields(logrus.Fields{"note_guid": noteGUID, "href": linkHref, "text": linkText}).WithError(err).Error("Failed to parse URL in note content")
 		} else {
 			noteLink := elp.ParseNoteLink(noteGUID, *linkURL, linkText)
 			if noteLink != nil {
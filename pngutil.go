@@ -0,0 +1,138 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"math"
+	"strconv"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+	"golang.org/x/image/vector"
+)
+
+// PNGUtil renders the force-directed layout of a NoteGraph as PNG image
+type PNGUtil struct {
+	DrawingUtil
+}
+
+// NewPNGUtil creates a new instance of PNGUtil
+func NewPNGUtil(labels bool, iterations int) *PNGUtil {
+	return &PNGUtil{DrawingUtil: DrawingUtil{Labels: labels, Iterations: iterations}}
+}
+
+// ExportNoteGraph renders the NoteGraph as PNG image and writes the PNG image to the writer
+func (pu *PNGUtil) ExportNoteGraph(noteGraph *NoteGraph, allNotes bool, writer io.Writer) error {
+	drawing := pu.CreateDrawing(noteGraph, allNotes)
+
+	logrus.Infof("Rendering NoteGraph with [%d] Notes and [%d] NoteLinks as PNG", len(drawing.Nodes), len(drawing.Edges))
+
+	encodeErr := png.Encode(writer, pu.RenderDrawing(drawing))
+	if encodeErr != nil {
+		return fmt.Errorf("Failed to encode PNG image: %w", encodeErr)
+	}
+
+	return nil
+}
+
+// RenderDrawing rasterizes the NoteGraphDrawing with anti-aliased edges and nodes on white background
+func (pu *PNGUtil) RenderDrawing(drawing *NoteGraphDrawing) *image.RGBA {
+	width, height := int(drawing.Width), int(drawing.Height)
+	rgba := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(rgba, rgba.Bounds(), image.White, image.Point{}, draw.Src)
+
+	edgeColor := image.NewUniform(color.NRGBA{R: 0x80, G: 0x80, B: 0x80, A: 0x99})
+	for _, edge := range drawing.Edges {
+		pu.FillPolygon(rgba, pu.LinePolygon(edge.X1, edge.Y1, edge.X2, edge.Y2, 1), edgeColor)
+		pu.FillPolygon(rgba, pu.ArrowheadPolygon(edge.X1, edge.Y1, edge.X2, edge.Y2, 6), edgeColor)
+	}
+
+	for _, node := range drawing.Nodes {
+		pu.FillPolygon(rgba, pu.CirclePolygon(node.X, node.Y, node.Radius), image.NewUniform(pu.ParseColor(node.Color)))
+	}
+
+	if drawing.Labels {
+		drawer := &font.Drawer{Dst: rgba, Src: image.Black, Face: basicfont.Face7x13}
+		for _, node := range drawing.Nodes {
+			labelWidth := drawer.MeasureString(node.Note.Title)
+			drawer.Dot = fixed.Point26_6{X: fixed.I(int(node.X)) - labelWidth/2, Y: fixed.I(int(node.Y + node.Radius + 12))}
+			drawer.DrawString(node.Note.Title)
+		}
+	}
+
+	return rgba
+}
+
+// FillPolygon fills the polygon anti-aliased with the colour, only the bounding box of the polygon is rasterized
+func (pu *PNGUtil) FillPolygon(rgba *image.RGBA, polygon [][2]float64, src image.Image) {
+	if len(polygon) < 3 {
+		return
+	}
+
+	minX, minY, maxX, maxY := polygon[0][0], polygon[0][1], polygon[0][0], polygon[0][1]
+	for _, point := range polygon {
+		minX, minY = math.Min(minX, point[0]), math.Min(minY, point[1])
+		maxX, maxY = math.Max(maxX, point[0]), math.Max(maxY, point[1])
+	}
+
+	bounds := image.Rect(int(math.Floor(minX)), int(math.Floor(minY)), int(math.Ceil(maxX))+1, int(math.Ceil(maxY))+1)
+	rasterizer := vector.NewRasterizer(bounds.Dx(), bounds.Dy())
+	rasterizer.MoveTo(float32(polygon[0][0]-float64(bounds.Min.X)), float32(polygon[0][1]-float64(bounds.Min.Y)))
+	for _, point := range polygon[1:] {
+		rasterizer.LineTo(float32(point[0]-float64(bounds.Min.X)), float32(point[1]-float64(bounds.Min.Y)))
+	}
+	rasterizer.ClosePath()
+	rasterizer.Draw(rgba, bounds, src, image.Point{})
+}
+
+// LinePolygon returns the rectangle of a line with the specified width
+func (pu *PNGUtil) LinePolygon(x1, y1, x2, y2, width float64) [][2]float64 {
+	length := math.Hypot(x2-x1, y2-y1)
+	if length == 0 {
+		return nil
+	}
+
+	nx, ny := -(y2-y1)/length*width/2, (x2-x1)/length*width/2
+	return [][2]float64{{x1 + nx, y1 + ny}, {x2 + nx, y2 + ny}, {x2 - nx, y2 - ny}, {x1 - nx, y1 - ny}}
+}
+
+// ArrowheadPolygon returns the triangle of an arrowhead with the specified size pointing to the end of the line
+func (pu *PNGUtil) ArrowheadPolygon(x1, y1, x2, y2, size float64) [][2]float64 {
+	angle := math.Atan2(y2-y1, x2-x1)
+	return [][2]float64{
+		{x2, y2},
+		{x2 - size*math.Cos(angle-0.4), y2 - size*math.Sin(angle-0.4)},
+		{x2 - size*math.Cos(angle+0.4), y2 - size*math.Sin(angle+0.4)}}
+}
+
+// CirclePolygon returns a polygon approximating the circle
+func (pu *PNGUtil) CirclePolygon(x, y, radius float64) [][2]float64 {
+	segments := 32
+	polygon := [][2]float64{}
+	for segment := 0; segment < segments; segment++ {
+		angle := 2 * math.Pi * float64(segment) / float64(segments)
+		polygon = append(polygon, [2]float64{x + radius*math.Cos(angle), y + radius*math.Sin(angle)})
+	}
+
+	return polygon
+}
+
+// ParseColor parses a colour in #RRGGBB notation, invalid colours are returned as grey
+func (pu *PNGUtil) ParseColor(value string) color.Color {
+	if len(value) != 7 || value[0] != '#' {
+		return color.RGBA{R: 0xC0, G: 0xC0, B: 0xC0, A: 0xFF}
+	}
+
+	rgb, err := strconv.ParseUint(value[1:], 16, 32)
+	if err != nil {
+		return color.RGBA{R: 0xC0, G: 0xC0, B: 0xC0, A: 0xFF}
+	}
+
+	return color.RGBA{R: uint8(rgb >> 16), G: uint8(rgb >> 8), B: uint8(rgb), A: 0xFF}
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"bytes"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExportNoteGraphToPNG(t *testing.T) {
+	buffer := bytes.Buffer{}
+	pngUtil := NewPNGUtil(true, 50)
+	err := pngUtil.ExportNoteGraph(CreateDOTTestNoteGraph(), true, &buffer)
+	assert.Nil(t, err)
+
+	image, err := png.Decode(&buffer)
+	assert.Nil(t, err)
+
+	drawing := pngUtil.CreateDrawing(CreateDOTTestNoteGraph(), true)
+	assert.Equal(t, int(drawing.Width), image.Bounds().Dx())
+	assert.Equal(t, int(drawing.Height), image.Bounds().Dy())
+
+	r, g, b, _ := image.At(0, 0).RGBA()
+	assert.Equal(t, []uint32{0xFFFF, 0xFFFF, 0xFFFF}, []uint32{r, g, b})
+
+	node := drawing.Nodes[0]
+	assert.Equal(t, pngUtil.ParseColor(node.Color), color.RGBAModel.Convert(image.At(int(node.X), int(node.Y))))
+}
+
+func TestParseColor(t *testing.T) {
+	pngUtil := NewPNGUtil(false, 0)
+
+	assert.Equal(t, color.RGBA{R: 0x00, G: 0xA8, B: 0x2D, A: 0xFF}, pngUtil.ParseColor("#00A82D"))
+	assert.Equal(t, color.RGBA{R: 0xC0, G: 0xC0, B: 0xC0, A: 0xFF}, pngUtil.ParseColor(""))
+	assert.Equal(t, color.RGBA{R: 0xC0, G: 0xC0, B: 0xC0, A: 0xFF}, pngUtil.ParseColor("#XYZXYZ"))
+}
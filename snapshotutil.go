@@ -0,0 +1,190 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// SnapshotVersion is the version of the NoteGraphSnapshot format
+const SnapshotVersion = 1
+
+// NoteGraphSnapshot is the JSON representation of a NoteGraph persisted to the file system
+type NoteGraphSnapshot struct {
+	Version   int                `json:"version"`
+	CreatedAt time.Time          `json:"createdAt"`
+	Notes     []SnapshotNote     `json:"notes"`
+	NoteLinks []SnapshotNoteLink `json:"noteLinks"`
+}
+
+// SnapshotNote is the JSON representation of a Note
+type SnapshotNote struct {
+	GUID        string    `json:"guid"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	URL         string    `json:"url"`
+	URLType     string    `json:"urlType"`
+	Notebook    string    `json:"notebook,omitempty"`
+	Tags        []string  `json:"tags,omitempty"`
+	Content     string    `json:"content,omitempty"` // ENML, required to export Note content without accessing the Evernote API
+	Created     time.Time `json:"created"`
+	Updated     time.Time `json:"updated"`
+}
+
+// SnapshotNoteLink is the JSON representation of a NoteLink
+type SnapshotNoteLink struct {
+	SourceNoteGUID string `json:"sourceNoteGuid"`
+	TargetNoteGUID string `json:"targetNoteGuid"`
+	Text           string `json:"text"`
+	URL            string `json:"url"`
+	URLType        string `json:"urlType"`
+}
+
+// SnapshotUtil saves NoteGraphs as NoteGraphSnapshots and loads NoteGraphs from NoteGraphSnapshots
+type SnapshotUtil struct{}
+
+// NewSnapshotUtil creates a new instance of SnapshotUtil
+func NewSnapshotUtil() *SnapshotUtil {
+	return &SnapshotUtil{}
+}
+
+// SaveSnapshot saves the NoteGraph as NoteGraphSnapshot with the specified filename on the file system
+func (su *SnapshotUtil) SaveSnapshot(filename string, noteGraph *NoteGraph) error {
+	logrus.WithField("filename", filename).Info("Saving NoteGraph snapshot to file")
+
+	file, fileErr := os.Create(filename)
+	if fileErr != nil {
+		return fmt.Errorf("Failed to create NoteGraph snapshot file [%s]: %w", filename, fileErr)
+	}
+	defer file.Close()
+
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	encodeErr := encoder.Encode(su.CreateSnapshot(noteGraph))
+	if encodeErr != nil {
+		return fmt.Errorf("Failed to encode NoteGraph snapshot to file [%s]: %w", filename, encodeErr)
+	}
+
+	return nil
+}
+
+// LoadSnapshot loads the NoteGraph from the NoteGraphSnapshot with the specified filename on the file system
+func (su *SnapshotUtil) LoadSnapshot(filename string) (*NoteGraph, error) {
+	logrus.WithField("filename", filename).Info("Loading NoteGraph snapshot from file")
+
+	file, fileErr := os.Open(filename)
+	if fileErr != nil {
+		return nil, fmt.Errorf("Failed to open NoteGraph snapshot file [%s]: %w", filename, fileErr)
+	}
+	defer file.Close()
+
+	noteGraphSnapshot := &NoteGraphSnapshot{}
+	decodeErr := json.NewDecoder(file).Decode(noteGraphSnapshot)
+	if decodeErr != nil {
+		return nil, fmt.Errorf("Failed to decode NoteGraph snapshot from file [%s]: %w", filename, decodeErr)
+	}
+
+	noteGraph, restoreErr := su.RestoreNoteGraph(noteGraphSnapshot)
+	if restoreErr != nil {
+		return nil, fmt.Errorf("Failed to restore NoteGraph from snapshot file [%s]: %w", filename, restoreErr)
+	}
+
+	return noteGraph, nil
+}
+
+// CreateSnapshot creates the NoteGraphSnapshot of the NoteGraph with Notes ordered by GUID
+func (su *SnapshotUtil) CreateSnapshot(noteGraph *NoteGraph) *NoteGraphSnapshot {
+	snapshotNotes := []SnapshotNote{}
+	for _, note := range *noteGraph.GetNotes() {
+		snapshotNotes = append(snapshotNotes, SnapshotNote{
+			GUID:        note.GUID,
+			Title:       note.Title,
+			Description: note.Description,
+			URL:         note.URL.String(),
+			URLType:     note.URLType.String(),
+			Notebook:    note.Notebook,
+			Tags:        note.Tags,
+			Content:     note.Content,
+			Created:     note.Created,
+			Updated:     note.Updated})
+	}
+
+	sort.Slice(snapshotNotes, func(i, j int) bool {
+		return snapshotNotes[i].GUID < snapshotNotes[j].GUID
+	})
+
+	snapshotNoteLinks := []SnapshotNoteLink{}
+	for _, noteLink := range *noteGraph.GetNoteLinks() {
+		snapshotNoteLinks = append(snapshotNoteLinks, SnapshotNoteLink{
+			SourceNoteGUID: noteLink.SourceNoteGUID,
+			TargetNoteGUID: noteLink.TargetNoteGUID,
+			Text:           noteLink.Text,
+			URL:            noteLink.URL.String(),
+			URLType:        noteLink.URLType.String()})
+	}
+
+	return &NoteGraphSnapshot{Version: SnapshotVersion, CreatedAt: time.Now().UTC(), Notes: snapshotNotes, NoteLinks: snapshotNoteLinks}
+}
+
+// RestoreNoteGraph restores the NoteGraph from the NoteGraphSnapshot
+func (su *SnapshotUtil) RestoreNoteGraph(noteGraphSnapshot *NoteGraphSnapshot) (*NoteGraph, error) {
+	if noteGraphSnapshot.Version != SnapshotVersion {
+		return nil, fmt.Errorf("Unsupported NoteGraph snapshot version [%d]", noteGraphSnapshot.Version)
+	}
+
+	noteGraph := NewNoteGraph()
+	for _, snapshotNote := range noteGraphSnapshot.Notes {
+		noteURL, noteURLType, err := su.RestoreURL(snapshotNote.URL, snapshotNote.URLType)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to restore Note with GUID [%s]: %w", snapshotNote.GUID, err)
+		}
+
+		noteGraph.Add(Note{
+			GUID:        snapshotNote.GUID,
+			Title:       snapshotNote.Title,
+			Description: snapshotNote.Description,
+			URL:         *noteURL,
+			URLType:     *noteURLType,
+			Notebook:    snapshotNote.Notebook,
+			Tags:        snapshotNote.Tags,
+			Content:     snapshotNote.Content,
+			Created:     snapshotNote.Created,
+			Updated:     snapshotNote.Updated}, []NoteLink{})
+	}
+
+	for _, snapshotNoteLink := range noteGraphSnapshot.NoteLinks {
+		noteLinkURL, noteLinkURLType, err := su.RestoreURL(snapshotNoteLink.URL, snapshotNoteLink.URLType)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to restore NoteLink from Note with GUID [%s]: %w", snapshotNoteLink.SourceNoteGUID, err)
+		}
+
+		noteGraph.NoteLinks = append(noteGraph.NoteLinks, NoteLink{
+			SourceNoteGUID: snapshotNoteLink.SourceNoteGUID,
+			TargetNoteGUID: snapshotNoteLink.TargetNoteGUID,
+			Text:           snapshotNoteLink.Text,
+			URL:            *noteLinkURL,
+			URLType:        *noteLinkURLType})
+	}
+
+	return noteGraph, nil
+}
+
+// RestoreURL parses the URL and URLType of a SnapshotNote or SnapshotNoteLink
+func (su *SnapshotUtil) RestoreURL(rawURL, rawURLType string) (*url.URL, *URLType, error) {
+	parsedURL, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Failed to parse URL [%s]: %w", rawURL, err)
+	}
+
+	urlType, err := NewURLType(rawURLType)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Failed to parse URLType of URL [%s]: %w", rawURL, err)
+	}
+
+	return parsedURL, urlType, nil
+}
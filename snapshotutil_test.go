@@ -0,0 +1,41 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSaveLoadSnapshot(t *testing.T) {
+	testSnapshotFile := filepath.Join(os.TempDir(), "testSnapshot.json")
+	defer os.Remove(testSnapshotFile)
+
+	noteA := Note{GUID: "A", Title: "TitleA", Description: "DescriptionA", Content: "<en-note>ContentA</en-note>", URL: *CreateWebLinkURL("A"), URLType: WebLink}
+	noteB := Note{GUID: "B", Title: "TitleB", Description: "DescriptionB", URL: *CreateAppLinkURL("B"), URLType: AppLink}
+	noteLinkAB := NoteLink{SourceNoteGUID: "A", TargetNoteGUID: "B", Text: "A->B", URL: *CreateAppLinkURL("B"), URLType: AppLink}
+	noteLinkBX := NoteLink{SourceNoteGUID: "B", TargetNoteGUID: "X", Text: "B->X", URL: *CreateWebLinkURL("X"), URLType: WebLink}
+
+	noteGraph := NewNoteGraph()
+	noteGraph.Add(noteA, []NoteLink{noteLinkAB})
+	noteGraph.Add(noteB, []NoteLink{noteLinkBX})
+
+	snapshotUtil := NewSnapshotUtil()
+	saveErr := snapshotUtil.SaveSnapshot(testSnapshotFile, noteGraph)
+	if saveErr != nil {
+		panic(saveErr)
+	}
+
+	loadedNoteGraph, loadErr := snapshotUtil.LoadSnapshot(testSnapshotFile)
+	if loadErr != nil {
+		panic(loadErr)
+	}
+
+	assert.Equal(t, noteGraph, loadedNoteGraph)
+}
+
+func TestRestoreNoteGraphWithUnsupportedVersion(t *testing.T) {
+	_, err := NewSnapshotUtil().RestoreNoteGraph(&NoteGraphSnapshot{Version: SnapshotVersion + 1})
+	assert.NotNil(t, err)
+}
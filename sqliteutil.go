@@ -0,0 +1,354 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	// pure-Go SQLite driver keeps the binary cgo-free
+	_ "modernc.org/sqlite"
+)
+
+// SQLiteDriverName is the database/sql driver name of the pure-Go SQLite driver
+const SQLiteDriverName = "sqlite"
+
+// SQLiteAttributePrefix is the prefix of the columns of NoteAttributes and NoteLinkAttributes, which keeps attribute columns apart
+// from the fixed columns of the notes and links tables
+const SQLiteAttributePrefix = "attr_"
+
+// SQLiteSchema creates the notebooks, tags, notes, note_tags, and links tables with foreign keys and indexes
+var SQLiteSchema = []string{
+	`CREATE TABLE notebooks (
+		id INTEGER PRIMARY KEY,
+		name TEXT NOT NULL UNIQUE)`,
+	`CREATE TABLE tags (
+		id INTEGER PRIMARY KEY,
+		name TEXT NOT NULL UNIQUE)`,
+	`CREATE TABLE notes (
+		guid TEXT PRIMARY KEY,
+		title TEXT NOT NULL,
+		description TEXT NOT NULL,
+		url TEXT NOT NULL,
+		url_type TEXT NOT NULL,
+		notebook_id INTEGER REFERENCES notebooks(id),
+		created TEXT,
+		updated TEXT)`,
+	`CREATE TABLE note_tags (
+		note_guid TEXT NOT NULL REFERENCES notes(guid),
+		tag_id INTEGER NOT NULL REFERENCES tags(id),
+		PRIMARY KEY (note_guid, tag_id))`,
+	`CREATE TABLE links (
+		id INTEGER PRIMARY KEY,
+		source_note_guid TEXT NOT NULL REFERENCES notes(guid),
+		target_note_guid TEXT NOT NULL,
+		text TEXT NOT NULL,
+		url TEXT NOT NULL,
+		url_type TEXT NOT NULL,
+		status TEXT NOT NULL CHECK (status IN ('valid', 'broken')))`,
+	`CREATE INDEX notes_notebook_id ON notes(notebook_id)`,
+	`CREATE INDEX notes_title ON notes(title)`,
+	`CREATE INDEX notes_updated ON notes(updated)`,
+	`CREATE INDEX note_tags_tag_id ON note_tags(tag_id)`,
+	`CREATE INDEX links_source_note_guid ON links(source_note_guid)`,
+	`CREATE INDEX links_target_note_guid ON links(target_note_guid)`,
+	`CREATE INDEX links_status ON links(status)`}
+
+// Enum of all LinkStatuses
+const (
+	ValidLink  LinkStatus = iota // source and target Note of the NoteLink exist
+	BrokenLink LinkStatus = iota // target Note of the NoteLink does not exist
+)
+
+// LinkStatus identifies whether a NoteLink is valid or broken
+type LinkStatus int
+
+func (ls LinkStatus) String() string {
+	return [...]string{"valid", "broken"}[ls]
+}
+
+// SQLiteUtil exports a NoteGraph into a SQLite database file, the NoteAttributes and NoteLinkAttributes become additional
+// columns of the notes and links tables
+type SQLiteUtil struct {
+	ExportAttributes
+}
+
+// NewSQLiteUtil creates a new instance of SQLiteUtil
+func NewSQLiteUtil() *SQLiteUtil {
+	return &SQLiteUtil{}
+}
+
+// ExportNoteGraph is not supported, SQLite databases have to be written to a file with ExportNoteGraphFiles
+func (su *SQLiteUtil) ExportNoteGraph(noteGraph *NoteGraph, allNotes bool, writer io.Writer) error {
+	return errors.New("SQLite export writes a database file and requires an output filename")
+}
+
+// ExportNoteGraphFiles writes the NoteGraph into a new SQLite database with the specified filename, an existing file is replaced
+// All NoteLinks are included, NoteLinks from Notes that do not exist cannot be stored and are skipped
+func (su *SQLiteUtil) ExportNoteGraphFiles(noteGraph *NoteGraph, allNotes bool, filename string) error {
+	removeErr := os.Remove(filename)
+	if removeErr != nil && !os.IsNotExist(removeErr) {
+		return fmt.Errorf("Failed to remove existing SQLite database file [%s]: %w", filename, removeErr)
+	}
+
+	db, openErr := su.OpenDatabase(filename)
+	if openErr != nil {
+		return fmt.Errorf("Failed to open SQLite database file [%s]: %w", filename, openErr)
+	}
+	defer db.Close()
+
+	tx, txErr := db.Begin()
+	if txErr != nil {
+		return fmt.Errorf("Failed to begin transaction in SQLite database file [%s]: %w", filename, txErr)
+	}
+
+	insertErr := su.InsertNoteGraph(tx, noteGraph, allNotes)
+	if insertErr != nil {
+		tx.Rollback()
+		return fmt.Errorf("Failed to export NoteGraph to SQLite database file [%s]: %w", filename, insertErr)
+	}
+
+	commitErr := tx.Commit()
+	if commitErr != nil {
+		return fmt.Errorf("Failed to commit transaction in SQLite database file [%s]: %w", filename, commitErr)
+	}
+
+	return nil
+}
+
+// OpenDatabase opens the SQLite database file with foreign keys enabled, the foreign_keys pragma is set in the data source name
+// as it applies to a single connection and every connection of the pool has to enforce foreign keys
+func (su *SQLiteUtil) OpenDatabase(filename string) (*sql.DB, error) {
+	return sql.Open(SQLiteDriverName, filename+"?_pragma=foreign_keys(1)")
+}
+
+// InsertNoteGraph creates the schema and inserts notebooks, tags, Notes, and NoteLinks
+func (su *SQLiteUtil) InsertNoteGraph(tx *sql.Tx, noteGraph *NoteGraph, allNotes bool) error {
+	for _, statement := range su.CreateSchema() {
+		if _, err := tx.Exec(statement); err != nil {
+			return fmt.Errorf("Failed to create schema: %w", err)
+		}
+	}
+
+	notes := su.SelectNotes(noteGraph, allNotes)
+	notebookIDs, err := su.InsertNames(tx, "notebooks", su.NotebookNames(notes))
+	if err != nil {
+		return err
+	}
+
+	tagIDs, err := su.InsertNames(tx, "tags", su.TagNames(notes))
+	if err != nil {
+		return err
+	}
+
+	for _, note := range notes {
+		if err := su.InsertNote(tx, note, notebookIDs, tagIDs); err != nil {
+			return fmt.Errorf("Failed to insert Note with GUID [%s]: %w", note.GUID, err)
+		}
+	}
+
+	insertedNoteLinks := 0
+	for _, edgeNoteLink := range CanonicalNoteLinks(*noteGraph.GetNoteLinks()) {
+		edgeID, noteLink := edgeNoteLink.EdgeID, edgeNoteLink.NoteLink
+		if noteGraph.GetNote(noteLink.SourceNoteGUID) == nil {
+			logrus.Warnf("Skipping NoteLink [%v] from Note that does not exist", noteLink)
+			continue
+		}
+
+		if err := su.InsertNoteLink(tx, noteGraph, edgeID, noteLink); err != nil {
+			return fmt.Errorf("Failed to insert NoteLink from Note with GUID [%s] to Note with GUID [%s]: %w", noteLink.SourceNoteGUID, noteLink.TargetNoteGUID, err)
+		}
+		insertedNoteLinks++
+	}
+
+	logrus.Infof("Exported NoteGraph with [%d] Notes, [%d] notebooks, [%d] tags, and [%d] NoteLinks to SQLite", len(notes), len(notebookIDs), len(tagIDs), insertedNoteLinks)
+	return nil
+}
+
+// CreateSchema returns the statements creating the schema including the columns of the NoteAttributes and NoteLinkAttributes
+func (su *SQLiteUtil) CreateSchema() []string {
+	statements := append([]string{}, SQLiteSchema...)
+	for _, noteAttribute := range su.NoteAttributes {
+		statements = append(statements, fmt.Sprintf("ALTER TABLE notes ADD COLUMN %s %s", su.AttributeColumn(noteAttribute.Name), su.ColumnType(noteAttribute.Type)))
+	}
+
+	for _, noteLinkAttribute := range su.NoteLinkAttributes {
+		statements = append(statements, fmt.Sprintf("ALTER TABLE links ADD COLUMN %s %s", su.AttributeColumn(noteLinkAttribute.Name), su.ColumnType(noteLinkAttribute.Type)))
+	}
+
+	return statements
+}
+
+// SelectNotes returns the Notes to export ordered by GUID, all source Notes of NoteLinks are included to satisfy foreign keys
+func (su *SQLiteUtil) SelectNotes(noteGraph *NoteGraph, allNotes bool) []Note {
+	selectedNotes := map[string]Note{}
+	graphNotes := *noteGraph.GetLinkedNotes()
+	if allNotes {
+		graphNotes = *noteGraph.GetNotes()
+	}
+
+	for _, note := range graphNotes {
+		selectedNotes[note.GUID] = note
+	}
+
+	for _, noteLink := range *noteGraph.GetNoteLinks() {
+		if note := noteGraph.GetNote(noteLink.SourceNoteGUID); note != nil {
+			selectedNotes[note.GUID] = *note
+		}
+	}
+
+	notes := []Note{}
+	for _, note := range selectedNotes {
+		notes = append(notes, note)
+	}
+
+	sort.Slice(notes, func(i, j int) bool {
+		return notes[i].GUID < notes[j].GUID
+	})
+
+	return notes
+}
+
+// NotebookNames returns the sorted distinct notebook names of the Notes
+func (su *SQLiteUtil) NotebookNames(notes []Note) []string {
+	names := []string{}
+	for _, note := range notes {
+		if note.Notebook != "" {
+			names = append(names, note.Notebook)
+		}
+	}
+
+	return su.DistinctNames(names)
+}
+
+// TagNames returns the sorted distinct tag names of the Notes
+func (su *SQLiteUtil) TagNames(notes []Note) []string {
+	names := []string{}
+	for _, note := range notes {
+		names = append(names, note.Tags...)
+	}
+
+	return su.DistinctNames(names)
+}
+
+// DistinctNames sorts the names and removes duplicates
+func (su *SQLiteUtil) DistinctNames(names []string) []string {
+	sort.Strings(names)
+	distinctNames := []string{}
+	for index, name := range names {
+		if index == 0 || names[index-1] != name {
+			distinctNames = append(distinctNames, name)
+		}
+	}
+
+	return distinctNames
+}
+
+// InsertNames inserts the names into the notebooks or tags table and returns the IDs by name
+func (su *SQLiteUtil) InsertNames(tx *sql.Tx, table string, names []string) (map[string]int64, error) {
+	ids := map[string]int64{}
+	for index, name := range names {
+		id := int64(index + 1)
+		if _, err := tx.Exec("INSERT INTO "+table+" (id, name) VALUES (?, ?)", id, name); err != nil {
+			return nil, fmt.Errorf("Failed to insert [%s] into table [%s]: %w", name, table, err)
+		}
+		ids[name] = id
+	}
+
+	return ids, nil
+}
+
+// InsertNote inserts the Note with its NoteAttributes and tags
+func (su *SQLiteUtil) InsertNote(tx *sql.Tx, note Note, notebookIDs map[string]int64, tagIDs map[string]int64) error {
+	columns := []string{"guid", "title", "description", "url", "url_type", "notebook_id", "created", "updated"}
+	values := []interface{}{note.GUID, note.Title, note.Description, note.URL.String(), note.URLType.String(), su.NullableID(notebookIDs, note.Notebook), su.NullableTime(note.Created), su.NullableTime(note.Updated)}
+	for _, noteAttribute := range su.NoteAttributes {
+		if value, found := noteAttribute.Values[note.GUID]; found {
+			columns = append(columns, su.AttributeColumn(noteAttribute.Name))
+			values = append(values, TypedAttributeValue(noteAttribute.Type, value))
+		}
+	}
+
+	if _, err := tx.Exec(su.InsertStatement("notes", columns), values...); err != nil {
+		return err
+	}
+
+	for _, tag := range note.Tags {
+		if _, err := tx.Exec("INSERT OR IGNORE INTO note_tags (note_guid, tag_id) VALUES (?, ?)", note.GUID, tagIDs[tag]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// InsertNoteLink inserts the NoteLink with the edge ID with its LinkStatus and NoteLinkAttributes
+func (su *SQLiteUtil) InsertNoteLink(tx *sql.Tx, noteGraph *NoteGraph, edgeID string, noteLink NoteLink) error {
+	linkStatus := ValidLink
+	if noteGraph.GetNote(noteLink.TargetNoteGUID) == nil {
+		linkStatus = BrokenLink
+	}
+
+	columns := []string{"source_note_guid", "target_note_guid", "text", "url", "url_type", "status"}
+	values := []interface{}{noteLink.SourceNoteGUID, noteLink.TargetNoteGUID, noteLink.Text, noteLink.URL.String(), noteLink.URLType.String(), linkStatus.String()}
+	for _, noteLinkAttribute := range su.NoteLinkAttributes {
+		if value, found := noteLinkAttribute.Value(edgeID, noteLink); found {
+			columns = append(columns, su.AttributeColumn(noteLinkAttribute.Name))
+			values = append(values, TypedAttributeValue(noteLinkAttribute.Type, value))
+		}
+	}
+
+	_, err := tx.Exec(su.InsertStatement("links", columns), values...)
+	return err
+}
+
+// InsertStatement creates the INSERT statement with one parameter per column
+func (su *SQLiteUtil) InsertStatement(table string, columns []string) string {
+	parameters := strings.TrimSuffix(strings.Repeat("?, ", len(columns)), ", ")
+	return fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", table, strings.Join(columns, ", "), parameters)
+}
+
+// NullableID returns the ID of the name or nil if the name is unknown
+func (su *SQLiteUtil) NullableID(ids map[string]int64, name string) interface{} {
+	if id, found := ids[name]; found {
+		return id
+	}
+
+	return nil
+}
+
+// NullableTime formats the time as ISO 8601 text understood by the SQLite date and time functions or returns nil for zero time
+func (su *SQLiteUtil) NullableTime(t time.Time) interface{} {
+	if t.IsZero() {
+		return nil
+	}
+
+	return t.UTC().Format("2006-01-02 15:04:05")
+}
+
+// ColumnType converts a GraphML attribute type into a SQLite column type
+func (su *SQLiteUtil) ColumnType(attributeType string) string {
+	if attributeType == "int" || attributeType == "long" || attributeType == "boolean" {
+		return "INTEGER"
+	} else if attributeType == "float" || attributeType == "double" {
+		return "REAL"
+	}
+
+	return "TEXT"
+}
+
+// Identifier quotes the column name with double quotes
+func (su *SQLiteUtil) Identifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// AttributeColumn returns the quoted column name of the NoteAttribute or NoteLinkAttribute with the SQLiteAttributePrefix
+func (su *SQLiteUtil) AttributeColumn(name string) string {
+	return su.Identifier(SQLiteAttributePrefix + name)
+}
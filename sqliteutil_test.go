@@ -0,0 +1,137 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func CreateSQLiteTestNoteGraph() *NoteGraph {
+	noteGraph := CreateHygieneTestNoteGraph()
+	noteA := noteGraph.Notes["A"]
+	noteA.Notebook = "Work"
+	noteA.Tags = []string{"project", "todo"}
+	noteA.Created = time.Date(2020, 1, 1, 10, 0, 0, 0, time.UTC)
+	noteGraph.Notes["A"] = noteA
+
+	noteB := noteGraph.Notes["B"]
+	noteB.Notebook = "Work"
+	noteB.Tags = []string{"project"}
+	noteGraph.Notes["B"] = noteB
+	return noteGraph
+}
+
+func TestExportNoteGraphToSQLite(t *testing.T) {
+	testFile := filepath.Join(os.TempDir(), "testNoteGraph.sqlite")
+	defer os.Remove(testFile)
+
+	sqliteUtil := NewSQLiteUtil()
+	sqliteUtil.AddNoteAttributes(NoteAttribute{ID: NodeCommunityID, Name: NodeCommunityName, Type: "int", Values: map[string]string{"A": "0", "B": "0"}})
+	err := SaveNoteGraphExport(testFile, sqliteUtil, CreateSQLiteTestNoteGraph(), true)
+	if err != nil {
+		panic(err)
+	}
+
+	db, err := sql.Open(SQLiteDriverName, testFile)
+	if err != nil {
+		panic(err)
+	}
+	defer db.Close()
+
+	assert.Equal(t, 5, QueryCount(db, "SELECT COUNT(*) FROM notes"))
+	assert.Equal(t, 1, QueryCount(db, "SELECT COUNT(*) FROM notebooks"))
+	assert.Equal(t, 2, QueryCount(db, "SELECT COUNT(*) FROM tags"))
+	assert.Equal(t, 3, QueryCount(db, "SELECT COUNT(*) FROM note_tags"))
+	assert.Equal(t, 6, QueryCount(db, "SELECT COUNT(*) FROM links"))
+	assert.Equal(t, 1, QueryCount(db, "SELECT COUNT(*) FROM links WHERE status = 'broken' AND target_note_guid = 'X'"))
+	assert.Equal(t, 2, QueryCount(db, "SELECT COUNT(*) FROM notes n JOIN notebooks nb ON n.notebook_id = nb.id WHERE nb.name = 'Work' AND n.attr_community = 0"))
+	assert.Equal(t, 1, QueryCount(db, "SELECT COUNT(*) FROM notes WHERE created >= date('2020-01-01') AND created < date('2020-01-02')"))
+	assert.Equal(t, 2, QueryCount(db, "SELECT COUNT(DISTINCT source_note_guid) FROM links WHERE target_note_guid = 'B'"))
+}
+
+func TestExportNoteGraphToSQLiteReplacesFile(t *testing.T) {
+	testFile := filepath.Join(os.TempDir(), "testNoteGraphReplace.sqlite")
+	defer os.Remove(testFile)
+
+	for i := 0; i < 2; i++ {
+		err := NewSQLiteUtil().ExportNoteGraphFiles(CreateSQLiteTestNoteGraph(), false, testFile)
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	db, err := sql.Open(SQLiteDriverName, testFile)
+	if err != nil {
+		panic(err)
+	}
+	defer db.Close()
+
+	assert.Equal(t, 4, QueryCount(db, "SELECT COUNT(*) FROM notes"))
+}
+
+func TestExportNoteGraphToSQLiteAttributeColumns(t *testing.T) {
+	testFile := filepath.Join(os.TempDir(), "testNoteGraphAttributes.sqlite")
+	defer os.Remove(testFile)
+
+	sqliteUtil := NewSQLiteUtil()
+	sqliteUtil.AddNoteAttributes(NoteAttribute{ID: "title", Name: "title", Type: "string", Values: map[string]string{"A": "attribute"}})
+	sqliteUtil.AddNoteLinkAttributes(NoteLinkAttribute{ID: "status", Name: "status", Type: "string", Values: map[NoteLinkKey]string{{SourceNoteGUID: "A", TargetNoteGUID: "B"}: "attribute"}})
+	err := sqliteUtil.ExportNoteGraphFiles(CreateSQLiteTestNoteGraph(), false, testFile)
+	if err != nil {
+		panic(err)
+	}
+
+	db, err := sqliteUtil.OpenDatabase(testFile)
+	if err != nil {
+		panic(err)
+	}
+	defer db.Close()
+
+	assert.Equal(t, 1, QueryCount(db, "SELECT COUNT(*) FROM notes WHERE title = 'TitleA' AND attr_title = 'attribute'"))
+	assert.Equal(t, 2, QueryCount(db, "SELECT COUNT(*) FROM links WHERE status = 'valid' AND attr_status = 'attribute'"))
+}
+
+func TestSQLiteOpenDatabaseForeignKeys(t *testing.T) {
+	testFile := filepath.Join(os.TempDir(), "testNoteGraphForeignKeys.sqlite")
+	defer os.Remove(testFile)
+
+	db, err := NewSQLiteUtil().OpenDatabase(testFile)
+	if err != nil {
+		panic(err)
+	}
+	defer db.Close()
+
+	// foreign keys are enforced by every connection of the pool
+	firstConn, err := db.Conn(context.Background())
+	if err != nil {
+		panic(err)
+	}
+	defer firstConn.Close()
+
+	secondConn, err := db.Conn(context.Background())
+	if err != nil {
+		panic(err)
+	}
+	defer secondConn.Close()
+
+	for _, conn := range []*sql.Conn{firstConn, secondConn} {
+		foreignKeys := 0
+		assert.Nil(t, conn.QueryRowContext(context.Background(), "PRAGMA foreign_keys").Scan(&foreignKeys))
+		assert.Equal(t, 1, foreignKeys)
+	}
+}
+
+func QueryCount(db *sql.DB, query string) int {
+	count := 0
+	err := db.QueryRow(query).Scan(&count)
+	if err != nil {
+		panic(err)
+	}
+
+	return count
+}
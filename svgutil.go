@@ -0,0 +1,70 @@
+package main
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// SVGUtil renders the force-directed layout of a NoteGraph as SVG image with clickable Notes
+type SVGUtil struct {
+	DrawingUtil
+}
+
+// NewSVGUtil creates a new instance of SVGUtil
+func NewSVGUtil(labels bool, iterations int) *SVGUtil {
+	return &SVGUtil{DrawingUtil: DrawingUtil{Labels: labels, Iterations: iterations}}
+}
+
+// ExportNoteGraph renders the NoteGraph as SVG image and writes the SVG image to the writer
+func (su *SVGUtil) ExportNoteGraph(noteGraph *NoteGraph, allNotes bool, writer io.Writer) error {
+	drawing := su.CreateDrawing(noteGraph, allNotes)
+
+	logrus.Infof("Rendering NoteGraph with [%d] Notes and [%d] NoteLinks as SVG", len(drawing.Nodes), len(drawing.Edges))
+
+	bufferedWriter := bufio.NewWriter(writer)
+	fmt.Fprintf(bufferedWriter, "%s", xml.Header)
+	fmt.Fprintf(bufferedWriter, "<svg xmlns=\"http://www.w3.org/2000/svg\" xmlns:xlink=\"http://www.w3.org/1999/xlink\" width=\"%.0f\" height=\"%.0f\" viewBox=\"0 0 %.0f %.0f\">\n", drawing.Width, drawing.Height, drawing.Width, drawing.Height)
+	fmt.Fprintf(bufferedWriter, "  <defs>\n")
+	fmt.Fprintf(bufferedWriter, "    <marker id=\"arrow\" viewBox=\"0 0 10 10\" refX=\"10\" refY=\"5\" markerWidth=\"6\" markerHeight=\"6\" orient=\"auto\"><path d=\"M 0 0 L 10 5 L 0 10 z\" fill=\"#808080\"/></marker>\n")
+	fmt.Fprintf(bufferedWriter, "  </defs>\n")
+	fmt.Fprintf(bufferedWriter, "  <rect width=\"100%%\" height=\"100%%\" fill=\"#FFFFFF\"/>\n")
+
+	fmt.Fprintf(bufferedWriter, "  <g stroke=\"#808080\" stroke-opacity=\"0.6\" stroke-width=\"1\">\n")
+	for _, edge := range drawing.Edges {
+		fmt.Fprintf(bufferedWriter, "    <line x1=\"%.1f\" y1=\"%.1f\" x2=\"%.1f\" y2=\"%.1f\" marker-end=\"url(#arrow)\"/>\n", edge.X1, edge.Y1, edge.X2, edge.Y2)
+	}
+	fmt.Fprintf(bufferedWriter, "  </g>\n")
+
+	fmt.Fprintf(bufferedWriter, "  <g font-family=\"sans-serif\" font-size=\"10\" text-anchor=\"middle\">\n")
+	for _, node := range drawing.Nodes {
+		fmt.Fprintf(bufferedWriter, "    %s\n", su.CreateNode(node, drawing.Labels))
+	}
+	fmt.Fprintf(bufferedWriter, "  </g>\n")
+	fmt.Fprintf(bufferedWriter, "</svg>\n")
+
+	return bufferedWriter.Flush()
+}
+
+// CreateNode creates the SVG link with title, circle, and optional label of the DrawingNode pointing to the Note URL
+func (su *SVGUtil) CreateNode(node DrawingNode, labels bool) string {
+	url := su.Escape(node.Note.URL.String())
+	svg := fmt.Sprintf("<a href=\"%s\" xlink:href=\"%s\" target=\"_blank\"><title>%s</title>", url, url, su.Escape(node.Note.Title))
+	svg += fmt.Sprintf("<circle cx=\"%.1f\" cy=\"%.1f\" r=\"%.1f\" fill=\"%s\"/>", node.X, node.Y, node.Radius, node.Color)
+	if labels {
+		svg += fmt.Sprintf("<text x=\"%.1f\" y=\"%.1f\">%s</text>", node.X, node.Y+node.Radius+10, su.Escape(node.Note.Title))
+	}
+
+	return svg + "</a>"
+}
+
+// Escape escapes the text for SVG attribute values and character data
+func (su *SVGUtil) Escape(text string) string {
+	builder := strings.Builder{}
+	xml.EscapeText(&builder, []byte(text))
+	return builder.String()
+}
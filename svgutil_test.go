@@ -0,0 +1,38 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/antchfx/xmlquery"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExportNoteGraphToSVG(t *testing.T) {
+	noteGraph := CreateDOTTestNoteGraph()
+	buffer := bytes.Buffer{}
+	err := NewSVGUtil(true, 50).ExportNoteGraph(noteGraph, false, &buffer)
+	assert.Nil(t, err)
+
+	xmlDocument, err := xmlquery.Parse(strings.NewReader(buffer.String()))
+	if err != nil {
+		panic(err)
+	}
+
+	assert.Equal(t, 4, len(xmlquery.Find(xmlDocument, "//svg/g/a/circle")))
+	assert.Equal(t, 4, len(xmlquery.Find(xmlDocument, "//svg/g/a/text")))
+	assert.Equal(t, len(*noteGraph.GetValidNoteLinks()), len(xmlquery.Find(xmlDocument, "//svg/g/line")))
+
+	noteA := noteGraph.Notes["A"]
+	link := xmlquery.FindOne(xmlDocument, "//svg/g/a[title='"+noteA.Title+"']")
+	assert.Equal(t, noteA.URL.String(), link.SelectAttr("href"))
+}
+
+func TestExportNoteGraphToSVGWithoutLabels(t *testing.T) {
+	buffer := bytes.Buffer{}
+	err := NewSVGUtil(false, 50).ExportNoteGraph(CreateDOTTestNoteGraph(), true, &buffer)
+	assert.Nil(t, err)
+	assert.False(t, strings.Contains(buffer.String(), "<text"))
+	assert.Equal(t, 5, strings.Count(buffer.String(), "<circle"))
+}
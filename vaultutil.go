@@ -0,0 +1,201 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// VaultUtil exports the Notes of a NoteGraph as Markdown files of an Obsidian vault, AppLinks and WebLinks to exported Notes
+// become Obsidian wiki links and broken NoteLinks are annotated plain links
+type VaultUtil struct {
+	ExportAttributes
+}
+
+// NewVaultUtil creates a new instance of VaultUtil
+func NewVaultUtil() *VaultUtil {
+	return &VaultUtil{}
+}
+
+// ExportNoteGraph is not supported, Markdown files have to be written to a directory with ExportNoteGraphFiles
+func (vu *VaultUtil) ExportNoteGraph(noteGraph *NoteGraph, allNotes bool, writer io.Writer) error {
+	return errors.New("Vault export writes one Markdown file per Note and requires an output directory")
+}
+
+// ExportNoteGraphFiles writes one Markdown file per Note into the directory with the specified name, the directory is created
+// if it does not exist and existing Markdown files with the same name are replaced
+func (vu *VaultUtil) ExportNoteGraphFiles(noteGraph *NoteGraph, allNotes bool, directory string) error {
+	notes := *noteGraph.GetLinkedNotes()
+	if allNotes {
+		notes = *noteGraph.GetNotes()
+	}
+
+	logrus.WithFields(logrus.Fields{"notes": len(notes), "directory": directory}).Info("Saving Notes as Markdown files to vault directory")
+
+	mkdirErr := os.MkdirAll(directory, 0755)
+	if mkdirErr != nil {
+		return fmt.Errorf("Failed to create vault directory [%s]: %w", directory, mkdirErr)
+	}
+
+	if len(notes) > 0 && !vu.HasContent(notes) {
+		logrus.Warn("NoteGraph snapshot without Note content, fetch with -noteContent to export the Note content to the vault")
+	}
+
+	filenames := vu.CreateFilenames(notes)
+	for _, note := range notes {
+		markdown, markdownErr := vu.CreateMarkdown(noteGraph, note, filenames)
+		if markdownErr != nil {
+			return fmt.Errorf("Failed to convert Note with GUID [%s] to Markdown: %w", note.GUID, markdownErr)
+		}
+
+		filename := filepath.Join(directory, filenames[note.GUID]+".md")
+		writeErr := ioutil.WriteFile(filename, []byte(markdown), 0644)
+		if writeErr != nil {
+			return fmt.Errorf("Failed to write Markdown file [%s]: %w", filename, writeErr)
+		}
+	}
+
+	return nil
+}
+
+// CreateFilenames creates unique filenames (without extension) by Note GUID from the Note titles, Notes with the same title are
+// numbered in order of GUID
+func (vu *VaultUtil) CreateFilenames(notes []Note) map[string]string {
+	sortedNotes := append([]Note{}, notes...)
+	sort.Slice(sortedNotes, func(i, j int) bool {
+		return sortedNotes[i].GUID < sortedNotes[j].GUID
+	})
+
+	filenames := map[string]string{}
+	usedFilenames := map[string]bool{}
+	for _, note := range sortedNotes {
+		baseFilename := vu.SanitizeFilename(note.Title)
+		if baseFilename == "" {
+			baseFilename = note.GUID
+		}
+
+		filename := baseFilename
+		for number := 2; usedFilenames[strings.ToLower(filename)]; number++ {
+			filename = baseFilename + " (" + strconv.Itoa(number) + ")"
+		}
+
+		usedFilenames[strings.ToLower(filename)] = true
+		filenames[note.GUID] = filename
+	}
+
+	return filenames
+}
+
+// SanitizeFilename replaces all characters that are not allowed in filenames or Obsidian wiki links
+func (vu *VaultUtil) SanitizeFilename(title string) string {
+	replacer := strings.NewReplacer(`\`, "-", "/", "-", ":", "-", "*", "-", "?", "-", `"`, "-", "<", "-", ">", "-", "|", "-", "#", "-", "^", "-", "[", "(", "]", ")", "\n", " ", "\r", " ", "\t", " ")
+	return strings.Trim(replacer.Replace(title), " .")
+}
+
+// CreateMarkdown creates the Markdown file content with YAML front matter and the converted note content of the Note
+func (vu *VaultUtil) CreateMarkdown(noteGraph *NoteGraph, note Note, filenames map[string]string) (string, error) {
+	noteLinks := map[string]NoteLink{}
+	for _, noteLink := range *noteGraph.GetNoteLinks() {
+		if noteLink.SourceNoteGUID == note.GUID {
+			noteLinks[noteLink.URL.String()] = noteLink
+		}
+	}
+
+	linkResolver := func(href, text string) string {
+		hrefURL, err := url.Parse(href)
+		if err != nil {
+			return ""
+		}
+
+		noteLink, found := noteLinks[hrefURL.String()]
+		if !found {
+			return ""
+		}
+
+		if filename, exported := filenames[noteLink.TargetNoteGUID]; exported {
+			if text == "" || text == filename {
+				return "[[" + filename + "]]"
+			}
+
+			return "[[" + filename + "|" + strings.ReplaceAll(text, "|", "-") + "]]"
+		}
+
+		if noteGraph.GetNote(noteLink.TargetNoteGUID) == nil {
+			if text == "" {
+				text = href
+			}
+
+			return "[" + text + "](" + href + ") *(broken link)*"
+		}
+
+		return ""
+	}
+
+	content := ""
+	if note.Content != "" {
+		var err error
+		content, err = NewENMLConverter(linkResolver).ConvertENML(note.Content)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return vu.CreateFrontMatter(note) + "\n" + content, nil
+}
+
+// CreateFrontMatter creates the YAML front matter with GUID, URL, notebook, tags, creation and update time, and NoteAttributes
+func (vu *VaultUtil) CreateFrontMatter(note Note) string {
+	lines := []string{"---", "guid: " + vu.YAMLString(note.GUID), "url: " + vu.YAMLString(note.URL.String())}
+	if note.Notebook != "" {
+		lines = append(lines, "notebook: "+vu.YAMLString(note.Notebook))
+	}
+
+	if len(note.Tags) > 0 {
+		lines = append(lines, "tags:")
+		for _, tag := range note.Tags {
+			lines = append(lines, "  - "+vu.YAMLString(strings.ReplaceAll(tag, " ", "-")))
+		}
+	}
+
+	if !note.Created.IsZero() {
+		lines = append(lines, "created: "+note.Created.UTC().Format(time.RFC3339))
+	}
+
+	if !note.Updated.IsZero() {
+		lines = append(lines, "updated: "+note.Updated.UTC().Format(time.RFC3339))
+	}
+
+	for _, noteAttribute := range vu.NoteAttributes {
+		if value, found := noteAttribute.Values[note.GUID]; found {
+			lines = append(lines, noteAttribute.Name+": "+vu.YAMLString(value))
+		}
+	}
+
+	return strings.Join(append(lines, "---"), "\n") + "\n"
+}
+
+// YAMLString creates a double-quoted YAML string
+func (vu *VaultUtil) YAMLString(value string) string {
+	return strconv.Quote(value)
+}
+
+// HasContent returns true if at least one of the Notes has content
+func (vu *VaultUtil) HasContent(notes []Note) bool {
+	for _, note := range notes {
+		if note.Content != "" {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func CreateVaultTestNoteGraph() *NoteGraph {
+	// Note A links to B and to the missing Note X, B and C have the same title
+	urlB, _ := url.Parse("evernote:///view/1/s1/B/B/")
+	urlX, _ := url.Parse("evernote:///view/1/s1/X/X/")
+	noteGraph := NewNoteGraph()
+	noteGraph.Add(Note{GUID: "A", Title: "Title: A", Notebook: "Work", Tags: []string{"project plan"}, Created: time.Date(2020, 1, 1, 10, 0, 0, 0, time.UTC),
+		Content: `<en-note><div>See <a href="evernote:///view/1/s1/B/B/">Duplicate</a> and <a href="evernote:///view/1/s1/X/X/">Missing</a></div></en-note>`},
+		[]NoteLink{{SourceNoteGUID: "A", TargetNoteGUID: "B", Text: "Duplicate", URL: *urlB}, {SourceNoteGUID: "A", TargetNoteGUID: "X", Text: "Missing", URL: *urlX}})
+	noteGraph.Add(Note{GUID: "B", Title: "Duplicate"}, []NoteLink{})
+	noteGraph.Add(Note{GUID: "C", Title: "Duplicate"}, []NoteLink{})
+	return noteGraph
+}
+
+func TestCreateFilenames(t *testing.T) {
+	vaultUtil := NewVaultUtil()
+	filenames := vaultUtil.CreateFilenames([]Note{{GUID: "C", Title: "Duplicate"}, {GUID: "B", Title: "duplicate"}, {GUID: "A", Title: "A/B: [x]?"}, {GUID: "D", Title: " . "}})
+
+	assert.Equal(t, map[string]string{"A": "A-B- (x)-", "B": "duplicate", "C": "Duplicate (2)", "D": "D"}, filenames)
+}
+
+func TestExportNoteGraphToVault(t *testing.T) {
+	testDirectory, err := ioutil.TempDir("", "testVault")
+	if err != nil {
+		panic(err)
+	}
+	defer os.RemoveAll(testDirectory)
+
+	vaultUtil := NewVaultUtil()
+	vaultUtil.AddNoteAttributes(NoteAttribute{ID: NodeCommunityID, Name: NodeCommunityName, Type: "int", Values: map[string]string{"A": "0"}})
+	err = SaveNoteGraphExport(testDirectory, vaultUtil, CreateVaultTestNoteGraph(), true)
+	assert.Nil(t, err)
+
+	files, _ := ioutil.ReadDir(testDirectory)
+	assert.Equal(t, 3, len(files))
+
+	markdown, err := ioutil.ReadFile(filepath.Join(testDirectory, "Title- A.md"))
+	assert.Nil(t, err)
+	assert.Equal(t, `---
+guid: "A"
+url: ""
+notebook: "Work"
+tags:
+  - "project-plan"
+created: 2020-01-01T10:00:00Z
+community: "0"
+---
+
+See [[Duplicate]] and [Missing](evernote:///view/1/s1/X/X/) *(broken link)*
+`, string(markdown))
+
+	markdown, err = ioutil.ReadFile(filepath.Join(testDirectory, "Duplicate (2).md"))
+	assert.Nil(t, err)
+	assert.Equal(t, "---\nguid: \"C\"\nurl: \"\"\n---\n\n", string(markdown))
+}
+
+func TestExportNoteGraphToVaultWriter(t *testing.T) {
+	err := NewVaultUtil().ExportNoteGraph(CreateVaultTestNoteGraph(), true, ioutil.Discard)
+	assert.NotNil(t, err)
+}